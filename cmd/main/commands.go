@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command 命令行子命令
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"build-transfer":          {"build an unsigned SOL transfer on an online machine", runBuildTransfer},
+	"sign":                    {"sign a transaction on an offline machine that holds the key file", runSign},
+	"broadcast":               {"verify and broadcast a signed transaction", runBroadcast},
+	"build-multisig-transfer": {"build an unsigned token transfer authorized by an SPL Token multisig", runBuildMultisigTransfer},
+	"merge":                   {"merge partially signed copies of the same transaction", runMerge},
+	"new-mnemonic":            {"generate a BIP39 mnemonic and show its first derived addresses", runNewMnemonic},
+	"restore-wallets":         {"regenerate wallet files from a mnemonic (m/44'/501'/n'/0')", runRestoreWallets},
+	"vanity":                  {"search for an address with a given prefix / suffix using all CPU cores", runVanity},
+	"registry":                {"manage wallet labels: add, list, show, rename, remove", runRegistry},
+	"contacts":                {"manage the address book and check recipients: add, list, remove, check", runContacts},
+	"encrypt-key":             {"encrypt a plain key file into a passphrase-protected keystore", runEncryptKey},
+	"reclaim-rent":            {"close empty token accounts and reclaim their rent", runReclaimRent},
+	"sweep":                   {"move all SOL and tokens to another address and close the token accounts", runSweep},
+	"wrap-sol":                {"wrap native SOL into the wallet's wSOL token account", runWrapSOL},
+	"unwrap-sol":              {"close the wSOL token account and get native SOL back", runUnwrapSOL},
+	"sol-balance":             {"show native and wrapped SOL balances separately", runSOLBalance},
+	"history":                 {"list recent transactions of an address with their memos", runHistory},
+	"pay-request":             {"create a Solana Pay transfer request URL and QR code", runPayRequest},
+	"pay":                     {"pay a Solana Pay transfer request URL", runPay},
+	"pay-status":              {"wait for the payment of a Solana Pay transfer request", runPayStatus},
+	"watch-deposits":          {"report SOL and token deposits to addresses once they are deep enough", runWatchDeposits},
+	"portfolio":               {"list all balances of a wallet with their USD value", runPortfolio},
+	"compare-quotes":          {"compare swap quotes from Jupiter and Raydium and pick the best", runCompareQuotes},
+	"trigger":                 {"limit orders, stop-loss and take-profit: place, list, cancel, run", runTrigger},
+	"serve-signer":            {"run a local signing server for remote signer development", runServeSigner},
+}
+
+// runCommand 执行子命令
+func runCommand(name string, args []string) error {
+	cmd, ok := commands[name]
+	if !ok {
+		printUsage()
+		return fmt.Errorf("unknown command %q", name)
+	}
+	return cmd.run(args)
+}
+
+func printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: main [command] [flags]")
+	fmt.Fprintln(os.Stderr, "Run without a command to execute the devnet transfer demo.")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-24s %s\n", name, commands[name].usage)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runContacts 管理地址簿：add / list / remove / check
+func runContacts(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: contacts add|list|remove|check [flags]")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("contacts "+sub, flag.ExitOnError)
+	path := fs.String("book", wallet.DefaultAddressBookPath, "address book file")
+	network := fs.String("network", "devnet", "network the contact belongs to (list: empty lists all networks)")
+	name := fs.String("name", "", "contact name")
+	address := fs.String("address", "", "contact address (add), contact name or address (check)")
+	owner := fs.String("owner", "", "program that must own the account, e.g. 11111111111111111111111111111111 for a wallet (add)")
+	notes := fs.String("notes", "", "free-form notes (add)")
+	fs.Parse(args)
+
+	book, err := wallet.OpenAddressBook(*path)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "add":
+		if *name == "" || *address == "" {
+			return errors.New("-name and -address are required")
+		}
+		if err := book.Add(wallet.Contact{Name: *name, Address: *address, Network: *network, ExpectedOwner: *owner, Notes: *notes}); err != nil {
+			return err
+		}
+		fmt.Printf("Saved %s as %q on %s\n", *address, *name, *network)
+	case "list":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tADDRESS\tNETWORK\tEXPECTED OWNER\tNOTES")
+		for _, c := range book.List(*network) {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Name, c.Address, c.Network, c.ExpectedOwner, c.Notes)
+		}
+		return w.Flush()
+	case "remove":
+		if *name == "" {
+			return errors.New("-name is required")
+		}
+		return book.Remove(*network, *name)
+	case "check":
+		if *address == "" {
+			return errors.New("-address is required")
+		}
+		wm, err := newWalletManager(*network)
+		if err != nil {
+			return err
+		}
+		wm.AddressBook = book
+		check, err := wm.CheckRecipient(context.Background(), *address)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Address: %s\n", check.Address)
+		if check.Contact != nil {
+			fmt.Printf("Contact: %s\n", check.Contact.Name)
+		}
+		if check.Owner != "" {
+			fmt.Printf("Owner:   %s\n", check.Owner)
+		}
+		for _, w := range check.Warnings {
+			fmt.Printf("Warning: %s\n", w.Message)
+		}
+	default:
+		return fmt.Errorf("unknown contacts command %q", sub)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runWatchDeposits 持续监控地址及其代币账户的到账，每笔到账输出一行
+func runWatchDeposits(args []string) error {
+	fs := flag.NewFlagSet("watch-deposits", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	addresses := fs.String("address", "", "comma-separated wallet addresses to watch")
+	checkpoint := fs.String("checkpoint", "deposits.json", "file that records progress so each deposit is reported once across restarts")
+	confirmations := fs.Uint64("confirmations", wallet.DefaultDepositConfirmations, "slots a deposit must be behind the latest confirmed slot before it is reported")
+	interval := fs.Duration("interval", wallet.DefaultDepositInterval, "polling interval")
+	fs.Parse(args)
+
+	if *addresses == "" {
+		return errors.New("-address is required")
+	}
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	watcher, err := wm.NewDepositWatcher(wallet.DepositWatcherConfig{
+		Addresses:      strings.Split(*addresses, ","),
+		CheckpointPath: *checkpoint,
+		Confirmations:  *confirmations,
+		Interval:       *interval,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Fprintln(os.Stderr, "Watching for deposits, press Ctrl+C to stop")
+	err = watcher.Run(ctx, func(d wallet.Deposit) error {
+		when := "-"
+		if d.BlockTime != nil {
+			when = d.BlockTime.Format(time.RFC3339)
+		}
+		fmt.Printf("%s  %s  slot=%d  to=%s  mint=%s  amount=%d  from=%s\n",
+			d.Signature, when, d.Slot, d.Account, d.Mint, d.Amount, d.Sender)
+		return nil
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
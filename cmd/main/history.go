@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// runHistory 列出地址最近的交易及其 memo
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	address := fs.String("address", "", "wallet address")
+	limit := fs.Int("limit", 20, "number of transactions (at most 1000)")
+	before := fs.String("before", "", "start before this signature, for paging")
+	fs.Parse(args)
+
+	if *address == "" {
+		return errors.New("-address is required")
+	}
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	entries, err := wm.History(context.Background(), *address, *limit, *before)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		when := "-"
+		if e.BlockTime != nil {
+			when = e.BlockTime.Format(time.RFC3339)
+		}
+		status := "ok"
+		if e.Err != nil {
+			status = "failed: " + e.Err.Error()
+		}
+		fmt.Printf("%s  %s  slot=%d  %s", e.Signature, when, e.Slot, status)
+		if len(e.Memos) > 0 {
+			fmt.Printf("  memo=%q", strings.Join(e.Memos, "; "))
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 
 	"github.com/paxzhu/go-solana/pkg/wallet"
@@ -11,8 +12,8 @@ import (
 
 const SOL_MINT_ADDR = "So11111111111111111111111111111111111111112"
 const GOAT_MINT_ADDR = "CzLSujWBLFsSjncfkh59rUFqvafWcY5tzedWJSuypump"
-const keyPath1 = "assets/wallet_7dEc3i8Niz.json"
-const keyPath2 = "assets/wallet_3qQEWctNXM.json"
+const defaultKeyPath1 = "assets/wallet_7dEc3i8Niz.json"
+const defaultKeyPath2 = "assets/wallet_3qQEWctNXM.json"
 const singleTransferAmount = 100000000 // 0.1 SOL
 const QuoteAPI = "https://quote-api.jup.ag/v6/quote?inputMint=So11111111111111111111111111111111111111112&outputMint=CzLSujWBLFsSjncfkh59rUFqvafWcY5tzedWJSuypump&amount=500000000&slippageBps=100"
 const mintAddr = "J2LDFD6Cso2aRq6XhT2hBq1jcgqo9j4bEBM1VCh71ojX"
@@ -20,14 +21,53 @@ const mintAuthority = "3qQEWctNXMxHiyoaQwAwn2xBRZGC7c2XeoPuLcZ6ZrUP"
 const ata2 = "3zwAJGJTTnSJfVHaLLtubVmpZXDLFY51fhVZjg1HCQxb"
 const ata1 = "31Fuuv2ekbt4ATZD1w8x9MkiiEnScWGZkGFDPteHsgXP"
 
+// logLevelEnv 设置日志级别的环境变量（debug、info、warn、error）
+const logLevelEnv = "WALLET_LOG_LEVEL"
+
 func main() {
+	// 所有日志输出到 stderr 并自动隐藏私钥、密码和 RPC URL 中的 API key
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv(logLevelEnv))); err != nil {
+		level = slog.LevelInfo
+	}
+	slog.SetDefault(wallet.NewLogger(os.Stderr, &slog.HandlerOptions{Level: level}))
+	startMetricsServer()
+	shutdownTracing := setupTracing()
+
+	// 带子命令时执行对应命令，否则运行下面的转账演示
+	if len(os.Args) > 1 {
+		err := runCommand(os.Args[1], os.Args[2:])
+		shutdownTracing()
+		if err != nil {
+			log.Fatalf("%s: %v", os.Args[1], err)
+		}
+		return
+	}
+	runDemo()
+	shutdownTracing()
+}
+
+// demoKeyPath 返回登记表中 label 对应的私钥文件，未登记时返回 fallback
+func demoKeyPath(label string, fallback string) string {
+	if path, err := wallet.ResolveKeyPath(wallet.DefaultRegistryPath, label); err == nil {
+		return path
+	}
+	return fallback
+}
+
+// runDemo 两个 devnet 账户互相转账的演示流程
+func runDemo() {
 	// wallet.GenerateWallets(2)
+	// 优先使用登记表中标签为 account1 / account2 的钱包，未登记时使用默认私钥文件
+	keyPath1 := demoKeyPath("account1", defaultKeyPath1)
+	keyPath2 := demoKeyPath("account2", defaultKeyPath2)
+
 	// 创建 WalletManager 实例
-	wm1, err := wallet.NewWalletManager("devnet")
+	wm1, err := newWalletManager("devnet")
 	if err != nil {
 		log.Fatalf("Error creating WalletManager: %v", err)
 	}
-	wm2, err := wallet.NewWalletManager("devnet")
+	wm2, err := newWalletManager("devnet")
 	if err != nil {
 		log.Fatalf("Error creating WalletManager: %v", err)
 	}
@@ -111,7 +151,7 @@ func main() {
 	}
 	fmt.Printf("[Account2 transfer SOL to Account1 successful] - txhash2: %s\n", txhash2)
 
-	// wallet.CreateMint(wm1.Account, wm2.Account)
+	// mint, _, err := wm1.CreateMint(context.Background(), wm2.Signer)
 	// ata1, err := wm1.CreateTokenAccount(context.Background(), mintAddr)
 	// if err != nil {
 	// 	log.Fatalf("Error creating token account: %v", err)
@@ -122,7 +162,7 @@ func main() {
 	// 	log.Fatalf("Error creating token account: %v", err)
 	// }
 	// fmt.Println("CreateTokenAccount successful!ata2:", ata2)
-	_, err = wm1.TransferTokensChecked(mintAddr, wm2.Account, ata1, ata2, singleTransferAmount, 8)
+	_, err = wm1.TransferTokensChecked(context.Background(), mintAddr, wm2.Signer, ata1, ata2, singleTransferAmount, 8)
 	if err != nil {
 		log.Fatalf("[Account1 transfer token to Account2 failed] - Error transferring token: %v", err)
 	}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// metricsAddrEnv 设置后在该地址提供 Prometheus /metrics
+const metricsAddrEnv = "WALLET_METRICS_ADDR"
+
+// cliMetrics 设置了 WALLET_METRICS_ADDR 时所有命令共用的指标
+var cliMetrics *wallet.Metrics
+
+// startMetricsServer 设置了 WALLET_METRICS_ADDR 时在后台提供 /metrics
+func startMetricsServer() {
+	addr := os.Getenv(metricsAddrEnv)
+	if addr == "" {
+		return
+	}
+	cliMetrics = wallet.NewMetrics(nil)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", cliMetrics.Handler())
+
+	go func() {
+		slog.Info("metrics server listening", "addr", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			slog.Error("metrics server stopped", "addr", addr, "error", err)
+		}
+	}()
+}
+
+// newWalletManager 创建 WalletManager，启用了指标时自动记录，设置了 WALLET_POLICY_FILE 时启用策略检查。
+// 转账时使用默认地址簿解析联系人名称。
+func newWalletManager(network string) (*wallet.WalletManager, error) {
+	book, err := wallet.OpenAddressBook(wallet.DefaultAddressBookPath)
+	if err != nil {
+		return nil, err
+	}
+	opts := []wallet.ManagerOption{wallet.WithAddressBook(book)}
+	if cliMetrics != nil {
+		opts = append(opts, wallet.WithMetrics(cliMetrics))
+	}
+	policy, err := loadCLIPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		opts = append(opts, wallet.WithPolicy(policy))
+	}
+	return wallet.NewWalletManager(network, opts...)
+}
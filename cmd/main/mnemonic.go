@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runNewMnemonic 生成新的助记词并显示派生出的前几个地址
+func runNewMnemonic(args []string) error {
+	fs := flag.NewFlagSet("new-mnemonic", flag.ExitOnError)
+	words := fs.Int("words", 24, "number of words: 12 or 24")
+	show := fs.Int("show", 3, "number of derived addresses to display")
+	fs.Parse(args)
+
+	mnemonic, err := wallet.NewMnemonic(*words)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Mnemonic (write it down and keep it offline):")
+	fmt.Println(mnemonic)
+	return printDerivedAccounts(mnemonic, "", *show)
+}
+
+// runRestoreWallets 从助记词重新生成钱包文件
+func runRestoreWallets(args []string) error {
+	fs := flag.NewFlagSet("restore-wallets", flag.ExitOnError)
+	mnemonicEnv := fs.String("mnemonic-env", "WALLET_MNEMONIC", "environment variable holding the mnemonic")
+	passphraseEnv := fs.String("passphrase-env", "WALLET_MNEMONIC_PASSPHRASE", "environment variable holding the optional BIP39 passphrase")
+	count := fs.Int("count", 1, "number of wallets to derive, starting at index 0")
+	dryRun := fs.Bool("dry-run", false, "only print the derived addresses")
+	fs.Parse(args)
+
+	mnemonic := os.Getenv(*mnemonicEnv)
+	if mnemonic == "" {
+		return fmt.Errorf("environment variable %s is not set", *mnemonicEnv)
+	}
+	if *count < 1 {
+		return errors.New("-count must be at least 1")
+	}
+	passphrase := os.Getenv(*passphraseEnv)
+
+	if *dryRun {
+		return printDerivedAccounts(mnemonic, passphrase, *count)
+	}
+	return wallet.GenerateWalletsFromMnemonic(mnemonic, passphrase, *count)
+}
+
+func printDerivedAccounts(mnemonic string, passphrase string, count int) error {
+	accounts, err := wallet.DeriveAccounts(mnemonic, passphrase, 0, count)
+	if err != nil {
+		return err
+	}
+	for i, account := range accounts {
+		fmt.Printf("%s => %s\n", wallet.DerivationPath(uint32(i)), account.PublicKey.ToBase58())
+	}
+	return nil
+}
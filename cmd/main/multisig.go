@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runBuildMultisigTransfer 构建以 SPL Token 多签账户为权限的未签名代币转账
+func runBuildMultisigTransfer(args []string) error {
+	fs := flag.NewFlagSet("build-multisig-transfer", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network to query blockhash / nonce from")
+	feePayer := fs.String("fee-payer", "", "fee payer public key")
+	mint := fs.String("mint", "", "token mint address")
+	from := fs.String("from", "", "source token account owned by the multisig")
+	to := fs.String("to", "", "destination token account")
+	multisig := fs.String("multisig", "", "multisig authority address")
+	signers := fs.String("signers", "", "comma separated multisig members that will sign")
+	amount := fs.Uint64("amount", 0, "amount in base units")
+	decimals := fs.Uint("decimals", 0, "mint decimals")
+	nonce := fs.String("nonce", "", "durable nonce account (recommended when collecting signatures)")
+	encoding := fs.String("encoding", wallet.EncodingBase64, "output encoding: base64 or base58")
+	out := fs.String("out", "", "write the unsigned transaction to this file instead of stdout")
+	fs.Parse(args)
+
+	if *feePayer == "" || *mint == "" || *from == "" || *to == "" || *multisig == "" || *signers == "" || *amount == 0 {
+		return errors.New("-fee-payer, -mint, -from, -to, -multisig, -signers and -amount are required")
+	}
+
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	var opts []wallet.TxOption
+	if *nonce != "" {
+		opts = append(opts, wallet.WithDurableNonce(*nonce, nil))
+	}
+
+	tx, err := wm.BuildMultisigTokenTransfer(context.Background(), wallet.MultisigTransferParam{
+		FeePayer:      *feePayer,
+		Mint:          *mint,
+		FromTokenAddr: *from,
+		ToTokenAddr:   *to,
+		Multisig:      *multisig,
+		Signers:       strings.Split(*signers, ","),
+		Amount:        *amount,
+		Decimals:      uint8(*decimals),
+	}, opts...)
+	if err != nil {
+		return err
+	}
+	return writeTransaction(tx, *encoding, *out)
+}
+
+// runMerge 合并多方分别签名的交易文件
+func runMerge(args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	in := fs.String("in", "", "comma separated partially signed transaction files")
+	encoding := fs.String("encoding", wallet.EncodingBase64, "transaction encoding: base64 or base58")
+	out := fs.String("out", "", "write the merged transaction to this file instead of stdout")
+	fs.Parse(args)
+
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+	var txs []types.Transaction
+	for _, path := range strings.Split(*in, ",") {
+		tx, err := readTransaction(path, *encoding)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, tx)
+	}
+
+	merged, err := wallet.MergeSignatures(txs...)
+	if err != nil {
+		return err
+	}
+	return writeTransaction(merged, *encoding, *out)
+}
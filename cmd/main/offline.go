@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runBuildTransfer 联网机器：构建未签名的 SOL 转账交易
+func runBuildTransfer(args []string) error {
+	fs := flag.NewFlagSet("build-transfer", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network to query blockhash / nonce from")
+	from := fs.String("from", "", "sender (fee payer) public key")
+	to := fs.String("to", "", "recipient public key")
+	amount := fs.Uint64("amount", 0, "amount in lamports")
+	nonce := fs.String("nonce", "", "durable nonce account (recommended for offline signing)")
+	memo := fs.String("memo", "", "attach an SPL memo, e.g. an invoice reference")
+	encoding := fs.String("encoding", wallet.EncodingBase64, "output encoding: base64 or base58")
+	out := fs.String("out", "", "write the unsigned transaction to this file instead of stdout")
+	fs.Parse(args)
+
+	if *from == "" || *to == "" || *amount == 0 {
+		return errors.New("-from, -to and -amount are required")
+	}
+
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	var opts []wallet.TxOption
+	if *nonce != "" {
+		opts = append(opts, wallet.WithDurableNonce(*nonce, nil))
+	}
+	if *memo != "" {
+		opts = append(opts, wallet.WithMemo(*memo))
+	}
+
+	tx, err := wm.BuildUnsignedTransferSOL(context.Background(), *from, *to, *amount, opts...)
+	if err != nil {
+		return err
+	}
+	return writeTransaction(tx, *encoding, *out)
+}
+
+// runSign 离线机器：核对摘要后用私钥文件签名
+func runSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	in := fs.String("in", "-", "transaction file, - for stdin")
+	encoding := fs.String("encoding", wallet.EncodingBase64, "transaction encoding: base64 or base58")
+	expectHash := fs.String("expect-hash", "", "message hash shown by build-transfer; refuse to sign if it differs")
+	out := fs.String("out", "", "write the signed transaction to this file instead of stdout")
+	fs.Parse(args)
+
+	if *keyPath == "" {
+		return errors.New("-key is required")
+	}
+	signer, err := loadSigner(*keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+	tx, err := readTransaction(*in, *encoding)
+	if err != nil {
+		return err
+	}
+
+	hash, err := wallet.MessageHash(tx)
+	if err != nil {
+		return err
+	}
+	if *expectHash != "" && *expectHash != hash {
+		return fmt.Errorf("message hash mismatch: expected %s, got %s", *expectHash, hash)
+	}
+	if _, err := wallet.VerifyTransaction(tx); err != nil {
+		return err
+	}
+
+	if err := wallet.SignTransaction(context.Background(), &tx, signer); err != nil {
+		return err
+	}
+	return writeTransaction(tx, *encoding, *out)
+}
+
+// runBroadcast 联网机器：校验签名后广播
+func runBroadcast(args []string) error {
+	fs := flag.NewFlagSet("broadcast", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network to broadcast to")
+	in := fs.String("in", "-", "signed transaction file, - for stdin")
+	encoding := fs.String("encoding", wallet.EncodingBase64, "transaction encoding: base64 or base58")
+	confirm := fs.Duration("confirm", 0, "wait up to this long for the transaction to be confirmed, 0 to return right after sending")
+	fs.Parse(args)
+
+	tx, err := readTransaction(*in, *encoding)
+	if err != nil {
+		return err
+	}
+	summary, err := wallet.SummarizeTransaction(tx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(os.Stderr, summary)
+
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	txhash, err := wm.SendSignedTransaction(context.Background(), tx)
+	if err != nil {
+		return err
+	}
+	fmt.Println("txhash:", txhash)
+
+	if *confirm > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), *confirm)
+		defer cancel()
+		if err := wm.WaitForConfirmation(ctx, txhash); err != nil {
+			return err
+		}
+		fmt.Println("confirmed")
+	}
+	return nil
+}
+
+// writeTransaction 打印交易摘要（stderr），并输出编码后的交易
+func writeTransaction(tx types.Transaction, encoding string, out string) error {
+	summary, err := wallet.SummarizeTransaction(tx)
+	if err != nil {
+		return err
+	}
+	encoded, err := wallet.EncodeTransaction(tx, encoding)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(os.Stderr, summary)
+	if out == "" {
+		fmt.Println(encoded)
+		return nil
+	}
+	if err := os.WriteFile(out, []byte(encoded+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	fmt.Fprintf(os.Stderr, "Transaction written to %s\n", out)
+	return nil
+}
+
+// readTransaction 从文件或 stdin 读取编码后的交易
+func readTransaction(in string, encoding string) (types.Transaction, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if in == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(in)
+	}
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("failed to read transaction: %w", err)
+	}
+	return wallet.DecodeTransaction(string(data), encoding)
+}
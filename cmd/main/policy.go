@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// policyFileEnv 设置后按该 JSON 策略文件在签名前检查每笔交易
+const policyFileEnv = "WALLET_POLICY_FILE"
+
+// loadCLIPolicy 加载 WALLET_POLICY_FILE，未设置时返回 nil。
+// 超过 approvalAbove 的操作在终端询问是否继续。
+func loadCLIPolicy() (*wallet.PolicyEngine, error) {
+	path := os.Getenv(policyFileEnv)
+	if path == "" {
+		return nil, nil
+	}
+	policy, err := wallet.LoadPolicy(path)
+	if err != nil {
+		return nil, err
+	}
+	return wallet.NewPolicyEngine(policy, promptApproval)
+}
+
+// promptApproval 在终端确认大额操作，默认拒绝
+func promptApproval(_ context.Context, spend wallet.Spend) (bool, error) {
+	recipient := spend.Recipient
+	if recipient == "" {
+		recipient = spend.RecipientAccount
+	}
+	fmt.Fprintf(os.Stderr, "%s: send %d of %s", spend.Operation, spend.Amount, spend.Mint)
+	if recipient != "" {
+		fmt.Fprintf(os.Stderr, " to %s", recipient)
+	}
+	fmt.Fprint(os.Stderr, "? [y/N] ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, nil
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
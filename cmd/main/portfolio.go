@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runPortfolio 列出钱包的所有持仓及其 USD 估值
+func runPortfolio(args []string) error {
+	fs := flag.NewFlagSet("portfolio", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label of the wallet")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	priceAPI := fs.String("price-api", wallet.JupiterPriceAPI, "price API base URL")
+	maxAge := fs.Duration("max-price-age", wallet.DefaultMaxPriceAge, "mark prices older than this as stale")
+	fs.Parse(args)
+
+	if *keyPath == "" {
+		return errors.New("-key is required")
+	}
+	wm, err := loadWallet(*network, *keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+	p, err := wm.Portfolio(context.Background(), wallet.WithPriceAPI(*priceAPI), wallet.WithMaxPriceAge(*maxAge))
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MINT\tAMOUNT\tPRICE (USD)\tVALUE (USD)\t24H")
+	for _, h := range p.Holdings {
+		price, value, change := "-", "-", "-"
+		if h.Priced {
+			price = fmt.Sprintf("%.6g", h.PriceUSD)
+			value = fmt.Sprintf("%.2f", h.ValueUSD)
+			if h.Stale {
+				price += " (stale)"
+			}
+		}
+		if h.Change24h != nil {
+			change = fmt.Sprintf("%+.2f%%", *h.Change24h)
+		}
+		fmt.Fprintf(tw, "%s\t%.*f\t%s\t%s\t%s\n", h.Mint, int(h.Decimals), h.UIAmount, price, value, change)
+	}
+	tw.Flush()
+
+	fmt.Printf("Total: %.2f USD", p.TotalUSD)
+	if p.Change24hPct != nil {
+		fmt.Printf(" (24h %+.2f USD, %+.2f%%)", p.Change24hUSD, *p.Change24hPct)
+	}
+	fmt.Println()
+	if p.Stale {
+		fmt.Fprintln(os.Stderr, "Warning: some prices are stale")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runCompareQuotes 向 Jupiter 和 Raydium 询价并给出最优报价及理由
+func runCompareQuotes(args []string) error {
+	fs := flag.NewFlagSet("compare-quotes", flag.ExitOnError)
+	network := fs.String("network", "mainnet", "network: mainnet, testnet, devnet or localhost")
+	inputMint := fs.String("input", wallet.SOL_MINT_ADDR, "input mint")
+	outputMint := fs.String("output", "", "output mint")
+	amount := fs.Uint64("amount", 0, "input amount in base units")
+	slippageBps := fs.Int("slippage-bps", 100, "slippage tolerance in basis points")
+	timeout := fs.Duration("timeout", wallet.DefaultQuoteTimeout, "timeout for each provider")
+	maxImpact := fs.Float64("max-price-impact", wallet.DefaultMaxPriceImpactPct, "ignore quotes with a higher price impact, in percent")
+	fs.Parse(args)
+
+	if *outputMint == "" || *amount == 0 {
+		return errors.New("-output and -amount are required")
+	}
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	a := wallet.NewQuoteAggregator(&wallet.JupiterQuoteProvider{Wallet: wm}, &wallet.RaydiumQuoteProvider{})
+	a.Timeout = *timeout
+	a.MaxPriceImpactPct = *maxImpact
+
+	result, err := a.Compare(context.Background(), wallet.QuoteRequest{
+		InputMint:   *inputMint,
+		OutputMint:  *outputMint,
+		Amount:      *amount,
+		SlippageBps: *slippageBps,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(result.Reason)
+	fmt.Printf("Best: %s, out %d via %s\n", result.Best.Provider, result.Best.OutAmount, strings.Join(result.Best.Route, " -> "))
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runRegistry 管理钱包登记表：add / list / show / rename / remove
+func runRegistry(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: registry add|list|show|rename|remove [flags]")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("registry "+sub, flag.ExitOnError)
+	path := fs.String("registry", wallet.DefaultRegistryPath, "registry file")
+	label := fs.String("label", "", "wallet label")
+	key := fs.String("key", "", "private key file or encrypted keystore (add)")
+	networks := fs.String("networks", "", "comma separated networks the wallet may be used on (add), empty means any")
+	notes := fs.String("notes", "", "free-form notes (add)")
+	to := fs.String("to", "", "new label (rename)")
+	network := fs.String("network", "", "only list wallets tagged for this network (list)")
+	fs.Parse(args)
+
+	registry, err := wallet.OpenRegistry(*path)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "add":
+		if *label == "" || *key == "" {
+			return errors.New("-label and -key are required")
+		}
+		entry := wallet.WalletEntry{Label: *label, Path: *key, Notes: *notes}
+		if *networks != "" {
+			entry.Networks = strings.Split(*networks, ",")
+		}
+		if err := registry.Add(entry); err != nil {
+			return err
+		}
+		entry, _ = registry.Lookup(*label)
+		fmt.Printf("Registered %s as %q\n", entry.PublicKey, entry.Label)
+	case "list":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "LABEL\tPUBLIC KEY\tPATH\tNETWORKS\tCREATED\tNOTES")
+		for _, e := range registry.List(*network) {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Label, e.PublicKey, e.Path,
+				strings.Join(e.Networks, ","), e.CreatedAt.Format("2006-01-02"), e.Notes)
+		}
+		return w.Flush()
+	case "show":
+		e, err := registry.Lookup(*label)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Label:      %s\nPublic key: %s\nPath:       %s\nNetworks:   %s\nCreated:    %s\nNotes:      %s\n",
+			e.Label, e.PublicKey, e.Path, strings.Join(e.Networks, ","), e.CreatedAt.Format("2006-01-02 15:04:05"), e.Notes)
+	case "rename":
+		if *label == "" || *to == "" {
+			return errors.New("-label and -to are required")
+		}
+		return registry.Rename(*label, *to)
+	case "remove":
+		if *label == "" {
+			return errors.New("-label is required")
+		}
+		return registry.Remove(*label)
+	default:
+		return fmt.Errorf("unknown registry command %q", sub)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runReclaimRent 关闭余额为 0 的代币账户，取回免租 SOL
+func runReclaimRent(args []string) error {
+	fs := flag.NewFlagSet("reclaim-rent", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label of the wallet")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	fs.Parse(args)
+
+	if *keyPath == "" {
+		return errors.New("-key is required")
+	}
+	signer, err := loadSigner(*keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	wm.Signer = signer
+
+	result, err := wm.ReclaimRent(context.Background())
+	if result != nil {
+		for _, sig := range result.Signatures {
+			fmt.Println("Signature:", sig)
+		}
+		fmt.Printf("Closed %d token accounts, reclaimed about %d lamports\n", len(result.Closed), result.Lamports)
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// loadSigner 按文件路径或登记表标签加载签名者，keystore 密码从环境变量 passphraseEnv 读取
+func loadSigner(labelOrPath string, passphraseEnv string) (wallet.Signer, error) {
+	return wallet.LoadSigner(labelOrPath, wallet.EnvPassphrase(passphraseEnv))
+}
+
+// runEncryptKey 将明文私钥文件加密为 keystore
+func runEncryptKey(args []string) error {
+	fs := flag.NewFlagSet("encrypt-key", flag.ExitOnError)
+	keyPath := fs.String("key", "", "plain private key file")
+	out := fs.String("out", "", "encrypted keystore output path")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	fs.Parse(args)
+
+	if *keyPath == "" || *out == "" {
+		return errors.New("-key and -out are required")
+	}
+	account, err := wallet.LoadKeypair(*keyPath)
+	if err != nil {
+		return err
+	}
+	passphrase, err := wallet.EnvPassphrase(*passphraseEnv)()
+	if err != nil {
+		return err
+	}
+	if err := wallet.SaveEncryptedKeystore(*out, account, passphrase); err != nil {
+		return err
+	}
+	fmt.Printf("Keystore for %s written to %s\n", account.PublicKey.ToBase58(), *out)
+	return nil
+}
+
+// runServeSigner 启动本地签名服务，供 RemoteSigner 开发调试使用
+func runServeSigner(args []string) error {
+	fs := flag.NewFlagSet("serve-signer", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:8900", "listen address")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	tokenEnv := fs.String("token-env", "WALLET_SIGNER_TOKEN", "environment variable holding the bearer token clients must send")
+	fs.Parse(args)
+
+	if *keyPath == "" {
+		return errors.New("-key is required")
+	}
+	signer, err := loadSigner(*keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("signing server for %s listening on %s", signer.PublicKey().ToBase58(), *addr)
+	return http.ListenAndServe(*addr, wallet.NewSigningServer(os.Getenv(*tokenEnv), signer))
+}
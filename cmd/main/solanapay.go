@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runPayRequest 生成 Solana Pay 收款链接和二维码
+func runPayRequest(args []string) error {
+	fs := flag.NewFlagSet("pay-request", flag.ExitOnError)
+	recipient := fs.String("recipient", "", "wallet address that receives the payment")
+	amount := fs.String("amount", "", "amount in SOL or token units, e.g. 1.5; empty lets the payer choose")
+	splToken := fs.String("spl-token", "", "token mint, empty for SOL")
+	label := fs.String("label", "", "merchant name shown to the payer")
+	message := fs.String("message", "", "description shown to the payer")
+	memo := fs.String("memo", "", "SPL memo written into the payment transaction")
+	png := fs.String("png", "", "also write the QR code as a PNG to this file")
+	size := fs.Int("size", 512, "PNG size in pixels")
+	fs.Parse(args)
+
+	if *recipient == "" {
+		return errors.New("-recipient is required")
+	}
+	req := &wallet.TransferRequest{
+		Recipient:  *recipient,
+		Amount:     *amount,
+		SPLToken:   *splToken,
+		References: []string{types.NewAccount().PublicKey.ToBase58()},
+		Label:      *label,
+		Message:    *message,
+		Memo:       *memo,
+	}
+	link, err := req.URL()
+	if err != nil {
+		return err
+	}
+	qr, err := wallet.TransferRequestQRTerminal(link)
+	if err != nil {
+		return err
+	}
+	fmt.Print(qr)
+	fmt.Println("URL:      ", link)
+	fmt.Println("Reference:", req.References[0])
+
+	if *png != "" {
+		data, err := wallet.TransferRequestQR(link, *size)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*png, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", *png, err)
+		}
+		fmt.Println("QR code written to", *png)
+	}
+	return nil
+}
+
+// runPay 解析 Solana Pay 链接，核对后付款
+func runPay(args []string) error {
+	fs := flag.NewFlagSet("pay", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label of the paying wallet")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	link := fs.String("url", "", "solana: transfer request URL")
+	yes := fs.Bool("yes", false, "pay without asking for confirmation")
+	fs.Parse(args)
+
+	if *keyPath == "" || *link == "" {
+		return errors.New("-key and -url are required")
+	}
+	req, err := wallet.ParseTransferRequest(*link)
+	if err != nil {
+		return err
+	}
+	unit := "SOL"
+	if req.SPLToken != "" {
+		unit = "of token " + req.SPLToken
+	}
+	fmt.Printf("Pay %s %s to %s\n", req.Amount, unit, req.Recipient)
+	if req.Label != "" {
+		fmt.Println("Label:  ", req.Label)
+	}
+	if req.Message != "" {
+		fmt.Println("Message:", req.Message)
+	}
+	if req.Memo != "" {
+		fmt.Println("Memo:   ", req.Memo)
+	}
+	if !*yes {
+		fmt.Print("Send this payment? [y/N] ")
+		var answer string
+		fmt.Scanln(&answer)
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			return errors.New("payment cancelled")
+		}
+	}
+
+	wm, err := loadWallet(*network, *keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+	txhash, err := wm.PayTransferRequest(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Signature:", txhash)
+	return nil
+}
+
+// runPayStatus 按 reference 等待并校验付款
+func runPayStatus(args []string) error {
+	fs := flag.NewFlagSet("pay-status", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	link := fs.String("url", "", "solana: transfer request URL that was shown to the payer")
+	timeout := fs.Duration("timeout", 5*time.Minute, "how long to wait for the payment")
+	fs.Parse(args)
+
+	if *link == "" {
+		return errors.New("-url is required")
+	}
+	req, err := wallet.ParseTransferRequest(*link)
+	if err != nil {
+		return err
+	}
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	signature, err := wm.WaitForTransferRequestPayment(ctx, req)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Paid:", signature)
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runSweep 把钱包的全部 SOL 和/或代币转给收款方
+func runSweep(args []string) error {
+	fs := flag.NewFlagSet("sweep", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label of the wallet")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	to := fs.String("to", "", "recipient address or contact name")
+	what := fs.String("what", "all", "what to sweep: sol, tokens or all")
+	priorityFee := fs.Uint64("priority-fee", 0, "priority fee in micro-lamports per compute unit")
+	computeUnits := fs.Uint("compute-units", 0, "compute unit limit, 0 keeps the default")
+	fs.Parse(args)
+
+	if *keyPath == "" || *to == "" {
+		return errors.New("-key and -to are required")
+	}
+	signer, err := loadSigner(*keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+	wm, err := newWalletManager(*network)
+	if err != nil {
+		return err
+	}
+	wm.Signer = signer
+
+	var opts []wallet.TxOption
+	if *priorityFee > 0 {
+		opts = append(opts, wallet.WithPriorityFee(*priorityFee, uint32(*computeUnits)))
+	}
+	var result *wallet.SweepResult
+	switch *what {
+	case "sol":
+		result, err = wm.SweepSOL(context.Background(), *to, opts...)
+	case "tokens":
+		result, err = wm.SweepTokens(context.Background(), *to, opts...)
+	case "all":
+		result, err = wm.SweepAll(context.Background(), *to, opts...)
+	default:
+		return fmt.Errorf("unknown -what %q", *what)
+	}
+	if result != nil {
+		for _, sig := range result.Signatures {
+			fmt.Println("Signature:", sig)
+		}
+		for _, t := range result.Tokens {
+			fmt.Printf("%s  mint=%s  amount=%d  closed=%t\n", t.Account, t.Mint, t.Amount, t.Closed)
+		}
+		if result.Lamports > 0 {
+			fmt.Printf("Sent %d lamports, fee %d lamports\n", result.Lamports, result.Fee)
+		}
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+	"go.opentelemetry.io/otel"
+)
+
+// setupTracing 设置了 OTEL_EXPORTER_OTLP_ENDPOINT 时把 span 导出到 OTLP 采集器。
+// 返回的函数在退出前调用，把缓冲中的 span 发送出去。
+func setupTracing() func() {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func() {}
+	}
+	tp, err := wallet.NewOTLPTracerProvider(context.Background(), "go-solana")
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		return func() {}
+	}
+	otel.SetTracerProvider(tp)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			slog.Error("failed to flush spans", "error", err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runTrigger 管理和执行条件单：place、list、cancel、run
+func runTrigger(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: trigger place|list|cancel|run [flags]")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("trigger "+sub, flag.ExitOnError)
+	path := fs.String("orders", wallet.DefaultTriggerOrdersPath, "order file")
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label of the wallet (run)")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	orderType := fs.String("type", "", "limit_buy, limit_sell, stop_loss or take_profit (place)")
+	mint := fs.String("mint", "", "token mint (place)")
+	amount := fs.Uint64("amount", 0, "lamports to spend for limit_buy, token base units to sell otherwise (place)")
+	price := fs.Float64("price", 0, "trigger price: SOL per token, or USD with -price-source feed (place)")
+	slippageBps := fs.Int("slippage-bps", 0, "slippage tolerance in basis points, 0 for the default (place)")
+	id := fs.String("id", "", "order ID (cancel)")
+	source := fs.String("price-source", "quote", "quote: Jupiter quote for the order size in SOL; feed: USD price API (run)")
+	interval := fs.Duration("interval", 15*time.Second, "polling interval (run)")
+	fs.Parse(args)
+
+	var wm *wallet.WalletManager
+	var err error
+	if sub == "run" {
+		if *keyPath == "" {
+			return errors.New("-key is required")
+		}
+		wm, err = loadWallet(*network, *keyPath, *passphraseEnv)
+	} else {
+		wm, err = newWalletManager(*network)
+	}
+	if err != nil {
+		return err
+	}
+	var priceSource wallet.TriggerPriceSource
+	switch *source {
+	case "quote":
+		priceSource = &wallet.QuotePriceSource{Wallet: wm}
+	case "feed":
+		priceSource = &wallet.FeedPriceSource{Wallet: wm}
+	default:
+		return fmt.Errorf("unknown price source %q", *source)
+	}
+	engine, err := wm.OpenTriggerEngine(*path, priceSource)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "place":
+		order, err := engine.Place(wallet.TriggerOrder{Type: *orderType, Mint: *mint, Amount: *amount, Price: *price, SlippageBps: *slippageBps})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Placed order %s\n", order.ID)
+	case "list":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNETWORK\tTYPE\tMINT\tAMOUNT\tPRICE\tSTATUS\tSIGNATURE / ERROR")
+		for _, o := range engine.Orders() {
+			result := o.Signature
+			if o.Error != "" {
+				result = o.Error
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%g\t%s\t%s\n", o.ID, o.Network, o.Type, o.Mint, o.Amount, o.Price, o.Status, result)
+		}
+		return w.Flush()
+	case "cancel":
+		if *id == "" {
+			return errors.New("-id is required")
+		}
+		if err := engine.Cancel(*id); err != nil {
+			return err
+		}
+		fmt.Printf("Canceled order %s\n", *id)
+	case "run":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		fmt.Fprintln(os.Stderr, "Watching orders, press Ctrl+C to stop")
+		err := engine.Run(ctx, *interval, func(o wallet.TriggerOrder) {
+			if o.Status == wallet.OrderExecuted {
+				fmt.Printf("%s %s %s executed at %g: %s\n", o.ID, o.Type, o.Mint, o.TriggeredPrice, o.Signature)
+				return
+			}
+			fmt.Printf("%s %s %s failed at %g: %s\n", o.ID, o.Type, o.Mint, o.TriggeredPrice, o.Error)
+		})
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown trigger command %q", sub)
+	}
+	return nil
+}
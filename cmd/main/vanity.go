@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runVanity 使用全部 CPU 核心搜索靓号地址，Ctrl+C 取消
+func runVanity(args []string) error {
+	fs := flag.NewFlagSet("vanity", flag.ExitOnError)
+	prefix := fs.String("prefix", "", "address prefix")
+	suffix := fs.String("suffix", "", "address suffix")
+	ignoreCase := fs.Bool("ignore-case", false, "case-insensitive match")
+	workers := fs.Int("workers", 0, "number of workers, 0 uses all CPU cores")
+	timeout := fs.Duration("timeout", 0, "give up after this duration, 0 means no limit")
+	fs.Parse(args)
+
+	if *prefix == "" && *suffix == "" {
+		return errors.New("-prefix or -suffix is required")
+	}
+	expected, err := wallet.EstimateVanityDifficulty(*prefix, *suffix, *ignoreCase)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Searching for prefix %q suffix %q, expected attempts: %.0f\n", *prefix, *suffix, expected)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	result, err := wallet.GenerateVanityWallet(ctx, wallet.VanityOptions{
+		Prefix:           *prefix,
+		Suffix:           *suffix,
+		IgnoreCase:       *ignoreCase,
+		Workers:          *workers,
+		ProgressInterval: 2 * time.Second,
+		Progress: func(s wallet.VanityStats) {
+			eta := time.Duration((s.ExpectedAttempts - float64(s.Attempts)) / s.KeysPerSecond * float64(time.Second))
+			if eta < 0 {
+				eta = 0
+			}
+			fmt.Printf("  %d attempts, %.0f keys/s, elapsed %s, expected remaining ~%s\n",
+				s.Attempts, s.KeysPerSecond, s.Elapsed.Round(time.Second), eta.Round(time.Second))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Found %s after %d attempts in %s, private key saved to %s\n",
+		result.Account.PublicKey.ToBase58(), result.Attempts, result.Elapsed.Round(time.Millisecond), result.Filename)
+	return nil
+}
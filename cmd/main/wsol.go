@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+)
+
+// runWrapSOL 把原生 SOL 包装为 wSOL
+func runWrapSOL(args []string) error {
+	fs := flag.NewFlagSet("wrap-sol", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label of the wallet")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	lamports := fs.Uint64("lamports", 0, "amount of SOL to wrap, in lamports")
+	fs.Parse(args)
+
+	if *keyPath == "" || *lamports == 0 {
+		return errors.New("-key and -lamports are required")
+	}
+	wm, err := loadWallet(*network, *keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+	txhash, err := wm.WrapSOL(context.Background(), *lamports)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Signature:", txhash)
+	return nil
+}
+
+// runUnwrapSOL 关闭 wSOL 账户，换回原生 SOL
+func runUnwrapSOL(args []string) error {
+	fs := flag.NewFlagSet("unwrap-sol", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label of the wallet")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	fs.Parse(args)
+
+	if *keyPath == "" {
+		return errors.New("-key is required")
+	}
+	wm, err := loadWallet(*network, *keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+	txhash, err := wm.UnwrapSOL(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Println("Signature:", txhash)
+	return nil
+}
+
+// runSOLBalance 分别显示原生 SOL 和 wSOL 余额
+func runSOLBalance(args []string) error {
+	fs := flag.NewFlagSet("sol-balance", flag.ExitOnError)
+	network := fs.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := fs.String("key", "", "private key file, encrypted keystore or registry label of the wallet")
+	passphraseEnv := fs.String("passphrase-env", wallet.DefaultPassphraseEnv, "environment variable holding the keystore passphrase")
+	fs.Parse(args)
+
+	if *keyPath == "" {
+		return errors.New("-key is required")
+	}
+	wm, err := loadWallet(*network, *keyPath, *passphraseEnv)
+	if err != nil {
+		return err
+	}
+	balance, err := wm.SOLBalances(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Native:  %d lamports\nWrapped: %d lamports\nTotal:   %d lamports\n", balance.Native, balance.Wrapped, balance.Total())
+	return nil
+}
+
+// loadWallet 创建连接 network 的 WalletManager，并使用 keyPath 指定的钱包签名
+func loadWallet(network string, keyPath string, passphraseEnv string) (*wallet.WalletManager, error) {
+	signer, err := loadSigner(keyPath, passphraseEnv)
+	if err != nil {
+		return nil, err
+	}
+	wm, err := newWalletManager(network)
+	if err != nil {
+		return nil, err
+	}
+	wm.Signer = signer
+	return wm, nil
+}
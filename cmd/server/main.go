@@ -0,0 +1,157 @@
+// server 以 REST API 和 gRPC 的形式提供钱包操作，供其他服务调用。
+// REST 接口定义见同目录下的 openapi.yaml，运行后也可以通过 GET /openapi.yaml 获取；
+// gRPC 服务定义见 pkg/walletpb/wallet.proto。
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/paxzhu/go-solana/pkg/wallet"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/reflection"
+)
+
+// 环境变量
+const (
+	apiKeysEnv  = "WALLET_API_KEYS" // 逗号分隔的 API key 列表
+	logLevelEnv = "WALLET_LOG_LEVEL"
+)
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "REST API listen address")
+	grpcAddr := flag.String("grpc-addr", "127.0.0.1:9090", "gRPC listen address, empty to disable")
+	network := flag.String("network", "devnet", "network: mainnet, testnet, devnet or localhost")
+	keyPath := flag.String("key", "", "private key file, encrypted keystore or registry label of the server wallet")
+	policyPath := flag.String("policy", "", "JSON spending policy checked before signing; operations that need approval are rejected")
+	flag.Parse()
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv(logLevelEnv))); err != nil {
+		level = slog.LevelInfo
+	}
+	slog.SetDefault(wallet.NewLogger(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	if err := run(*addr, *grpcAddr, *network, *keyPath, *policyPath); err != nil {
+		log.Fatalf("server: %v", err)
+	}
+}
+
+func run(addr string, grpcAddr string, network string, keyPath string, policyPath string) error {
+	if keyPath == "" {
+		return errors.New("-key is required")
+	}
+	apiKeys := splitAPIKeys(os.Getenv(apiKeysEnv))
+	if len(apiKeys) == 0 {
+		return fmt.Errorf("environment variable %s must list at least one API key", apiKeysEnv)
+	}
+	signer, err := wallet.LoadSigner(keyPath, wallet.EnvPassphrase(wallet.DefaultPassphraseEnv))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := []wallet.ManagerOption{wallet.WithMetrics(wallet.NewMetrics(nil))}
+	if policyPath != "" {
+		policy, err := wallet.LoadPolicy(policyPath)
+		if err != nil {
+			return err
+		}
+		// 服务端没有人工确认，超过 approvalAbove 的操作一律拒绝
+		engine, err := wallet.NewPolicyEngine(policy, nil)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, wallet.WithPolicy(engine))
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		tp, err := wallet.NewOTLPTracerProvider(ctx, "go-solana-server")
+		if err != nil {
+			return fmt.Errorf("failed to set up tracing: %w", err)
+		}
+		otel.SetTracerProvider(tp)
+		defer tp.Shutdown(context.Background())
+	}
+	wm, err := wallet.NewWalletManager(network, opts...)
+	if err != nil {
+		return err
+	}
+	wm.Signer = signer
+
+	mux := http.NewServeMux()
+	mux.Handle("/", wallet.NewAPIServer(wm, wallet.APIServerConfig{APIKeys: apiKeys}))
+	mux.Handle("GET /metrics", wm.Metrics.Handler())
+	mux.HandleFunc("GET /openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(openAPISpec)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if grpcAddr != "" {
+		registry, err := wallet.OpenRegistry(wallet.DefaultRegistryPath)
+		if err != nil {
+			return err
+		}
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			return err
+		}
+		grpcServer := wallet.NewGRPCServer(wm, wallet.GRPCServerConfig{
+			APIKeys:    apiKeys,
+			Registry:   registry,
+			Passphrase: wallet.EnvPassphrase(wallet.DefaultPassphraseEnv),
+		})
+		reflection.Register(grpcServer)
+		go func() {
+			<-ctx.Done()
+			grpcServer.GracefulStop()
+		}()
+		go func() {
+			slog.Info("grpc server listening", "addr", grpcAddr)
+			if err := grpcServer.Serve(lis); err != nil {
+				slog.Error("grpc server stopped", "addr", grpcAddr, "error", err)
+			}
+		}()
+	}
+
+	slog.Info("api server listening", "addr", addr, "network", network, "pubkey", signer.PublicKey().ToBase58())
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// splitAPIKeys 解析逗号分隔的 API key 列表
+func splitAPIKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
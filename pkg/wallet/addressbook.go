@@ -0,0 +1,343 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/mr-tron/base58"
+)
+
+// DefaultAddressBookPath 默认的地址簿文件
+const DefaultAddressBookPath = "assets/addressbook.json"
+
+var (
+	// ErrInvalidAddress 地址不是 32 字节的 base58 公钥
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrContactNotFound 地址簿中没有对应名称的联系人
+	ErrContactNotFound = errors.New("contact not found in address book")
+	// ErrUnexpectedOwner 收款账户的所属程序与联系人登记的不一致
+	ErrUnexpectedOwner = errors.New("recipient owner does not match contact")
+)
+
+// 收款地址检查的警告类型
+const (
+	WarningOffCurve     = "off_curve"     // 地址不在 ed25519 曲线上（PDA），没有私钥可以签名
+	WarningTokenAccount = "token_account" // 地址是代币账户而不是钱包
+	WarningFirstTime    = "first_time"    // 地址不在地址簿中，且从未转账过
+)
+
+// Contact 地址簿中的联系人，名称在同一网络内唯一
+type Contact struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Network string `json:"network"`
+	// ExpectedOwner 账户应属于的程序，例如 System Program 表示普通钱包，为空时不检查
+	ExpectedOwner string    `json:"expectedOwner,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Notes         string    `json:"notes,omitempty"`
+}
+
+// AddressBook 按网络保存的联系人和转账过的地址
+type AddressBook struct {
+	path     string
+	mu       sync.Mutex
+	contacts []Contact
+	sent     map[string]map[string]bool // network -> address
+}
+
+type addressBookFile struct {
+	Contacts []Contact           `json:"contacts"`
+	Sent     map[string][]string `json:"sent,omitempty"`
+}
+
+// RecipientWarning 收款地址检查发现的问题，不阻止转账
+type RecipientWarning struct {
+	Code    string // Warning* 常量之一
+	Message string
+}
+
+// RecipientCheck 收款地址检查结果
+type RecipientCheck struct {
+	Address  string
+	Contact  *Contact // 按联系人名称或地址匹配到的联系人
+	Owner    string   // 账户所属程序，账户不存在或无法查询时为空
+	Warnings []RecipientWarning
+}
+
+// HasWarning 判断是否包含指定类型的警告
+func (c *RecipientCheck) HasWarning(code string) bool {
+	for _, w := range c.Warnings {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAddress 解析 base58 地址，长度不是 32 字节时返回 ErrInvalidAddress。
+// common.PublicKeyFromString 对非法输入不报错，接收外部输入的地址应使用该函数。
+func ParseAddress(address string) (common.PublicKey, error) {
+	decoded, err := base58.Decode(address)
+	if err != nil || len(decoded) != common.PublicKeyLength {
+		return common.PublicKey{}, fmt.Errorf("%w: %q", ErrInvalidAddress, address)
+	}
+	return common.PublicKeyFromBytes(decoded), nil
+}
+
+// OpenAddressBook 打开地址簿文件，文件不存在时返回空地址簿
+func OpenAddressBook(path string) (*AddressBook, error) {
+	b := &AddressBook{path: path, sent: map[string]map[string]bool{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read address book: %w", err)
+	}
+
+	var file addressBookFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse address book %s: %w", path, err)
+	}
+	b.contacts = file.Contacts
+	for network, addresses := range file.Sent {
+		for _, addr := range addresses {
+			b.markSent(network, addr)
+		}
+	}
+	return b, nil
+}
+
+// Add 添加联系人，地址和 ExpectedOwner 必须是合法的 base58 公钥
+func (b *AddressBook) Add(c Contact) error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	if c.Network == "" {
+		return errors.New("network is required")
+	}
+	if _, err := ParseAddress(c.Address); err != nil {
+		return err
+	}
+	// 名称不能与地址混淆，否则按名称解析时会有歧义
+	if _, err := ParseAddress(c.Name); err == nil {
+		return fmt.Errorf("name %q must not be an address", c.Name)
+	}
+	if c.ExpectedOwner != "" {
+		if _, err := ParseAddress(c.ExpectedOwner); err != nil {
+			return fmt.Errorf("invalid expected owner: %w", err)
+		}
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now().UTC()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range b.contacts {
+		if e.Network != c.Network {
+			continue
+		}
+		if e.Name == c.Name {
+			return fmt.Errorf("contact %q already exists on %s", c.Name, c.Network)
+		}
+		if e.Address == c.Address {
+			return fmt.Errorf("address %s is already saved as %q on %s", c.Address, e.Name, c.Network)
+		}
+	}
+	b.contacts = append(b.contacts, c)
+	return b.save()
+}
+
+// List 按名称排序列出联系人，network 非空时只返回该网络的联系人
+func (b *AddressBook) List(network string) []Contact {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	contacts := make([]Contact, 0, len(b.contacts))
+	for _, c := range b.contacts {
+		if network == "" || c.Network == network {
+			contacts = append(contacts, c)
+		}
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		if contacts[i].Network != contacts[j].Network {
+			return contacts[i].Network < contacts[j].Network
+		}
+		return contacts[i].Name < contacts[j].Name
+	})
+	return contacts
+}
+
+// Lookup 按名称查找指定网络的联系人
+func (b *AddressBook) Lookup(network string, name string) (Contact, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, c := range b.contacts {
+		if c.Network == network && c.Name == name {
+			return c, nil
+		}
+	}
+	return Contact{}, fmt.Errorf("%w: %q on %s", ErrContactNotFound, name, network)
+}
+
+// LookupAddress 按地址查找指定网络的联系人
+func (b *AddressBook) LookupAddress(network string, address string) (Contact, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, c := range b.contacts {
+		if c.Network == network && c.Address == address {
+			return c, nil
+		}
+	}
+	return Contact{}, fmt.Errorf("%w: %s on %s", ErrContactNotFound, address, network)
+}
+
+// Remove 删除指定网络的联系人
+func (b *AddressBook) Remove(network string, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, c := range b.contacts {
+		if c.Network == network && c.Name == name {
+			b.contacts = append(b.contacts[:i], b.contacts[i+1:]...)
+			return b.save()
+		}
+	}
+	return fmt.Errorf("%w: %q on %s", ErrContactNotFound, name, network)
+}
+
+// HasSent 判断是否在指定网络上向该地址转账过
+func (b *AddressBook) HasSent(network string, address string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sent[network][address]
+}
+
+// MarkSent 记录向该地址转账过，之后不再提示首次转账
+func (b *AddressBook) MarkSent(network string, address string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sent[network][address] {
+		return nil
+	}
+	b.markSent(network, address)
+	return b.save()
+}
+
+func (b *AddressBook) markSent(network string, address string) {
+	if b.sent[network] == nil {
+		b.sent[network] = map[string]bool{}
+	}
+	b.sent[network][address] = true
+}
+
+// save 原子写入地址簿文件，调用方需持有锁
+func (b *AddressBook) save() error {
+	file := addressBookFile{Contacts: b.contacts, Sent: map[string][]string{}}
+	if file.Contacts == nil {
+		file.Contacts = []Contact{}
+	}
+	for network, addresses := range b.sent {
+		for addr := range addresses {
+			file.Sent[network] = append(file.Sent[network], addr)
+		}
+		sort.Strings(file.Sent[network])
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(b.path, data, 0o644, true); err != nil {
+		return fmt.Errorf("failed to write address book: %w", err)
+	}
+	return nil
+}
+
+// WithAddressBook 转账时按地址簿解析联系人名称，并提示首次转账的地址
+func WithAddressBook(b *AddressBook) ManagerOption {
+	return func(wm *WalletManager) {
+		wm.AddressBook = b
+	}
+}
+
+// CheckRecipient 检查收款地址。nameOrAddress 可以是当前网络地址簿中的联系人名称。
+// 地址不合法返回 ErrInvalidAddress，账户所属程序与联系人的 ExpectedOwner 不一致返回 ErrUnexpectedOwner；
+// PDA、代币账户和首次转账的地址只返回警告。
+func (wm *WalletManager) CheckRecipient(ctx context.Context, nameOrAddress string) (*RecipientCheck, error) {
+	check := &RecipientCheck{Address: nameOrAddress}
+	if wm.AddressBook != nil {
+		if c, err := wm.AddressBook.Lookup(wm.Network, nameOrAddress); err == nil {
+			check.Address = c.Address
+			check.Contact = &c
+		} else if c, err := wm.AddressBook.LookupAddress(wm.Network, nameOrAddress); err == nil {
+			check.Contact = &c
+		}
+	}
+
+	pubkey, err := ParseAddress(check.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsOnCurve(pubkey) {
+		check.Warnings = append(check.Warnings, RecipientWarning{Code: WarningOffCurve,
+			Message: fmt.Sprintf("%s is off the ed25519 curve (a program derived address); no private key can sign for it", check.Address)})
+	}
+
+	// 账户不存在（新钱包）或查询失败时跳过所属程序检查
+	info, err := getAccountInfo(ctx, wm.Client, check.Address)
+	switch {
+	case err == nil:
+		check.Owner = info.Owner.ToBase58()
+		if isTokenAccount(info.Owner, info.Data) {
+			check.Warnings = append(check.Warnings, RecipientWarning{Code: WarningTokenAccount,
+				Message: fmt.Sprintf("%s is a token account, not a wallet", check.Address)})
+		}
+	case !errors.Is(err, ErrAccountNotFound):
+		wm.logger().DebugContext(ctx, "failed to inspect recipient", "network", wm.Network, "to", check.Address, "error", err)
+	}
+	if check.Contact != nil && check.Contact.ExpectedOwner != "" && check.Owner != "" && check.Owner != check.Contact.ExpectedOwner {
+		return nil, fmt.Errorf("%w: %q is owned by %s, expected %s",
+			ErrUnexpectedOwner, check.Contact.Name, check.Owner, check.Contact.ExpectedOwner)
+	}
+
+	if wm.AddressBook != nil && check.Contact == nil && !wm.AddressBook.HasSent(wm.Network, check.Address) {
+		check.Warnings = append(check.Warnings, RecipientWarning{Code: WarningFirstTime,
+			Message: fmt.Sprintf("%s is not in the address book and has never received a transfer on %s", check.Address, wm.Network)})
+	}
+	return check, nil
+}
+
+// resolveRecipient 检查收款地址并记录警告日志，返回解析后的地址
+func (wm *WalletManager) resolveRecipient(ctx context.Context, operation string, nameOrAddress string) (common.PublicKey, error) {
+	check, err := wm.CheckRecipient(ctx, nameOrAddress)
+	if err != nil {
+		return common.PublicKey{}, fmt.Errorf("invalid recipient: %w", err)
+	}
+	for _, w := range check.Warnings {
+		wm.logger().WarnContext(ctx, "recipient warning", "operation", operation, "network", wm.Network,
+			"to", check.Address, "warning", w.Code, "message", w.Message)
+	}
+	return ParseAddress(check.Address)
+}
+
+// isTokenAccount 判断账户是否为 SPL Token 代币账户。
+// Token-2022 带扩展的账户在基础数据之后用一个字节标记账户类型（2 为代币账户）。
+func isTokenAccount(owner common.PublicKey, data []byte) bool {
+	if owner != common.TokenProgramID && owner != common.Token2022ProgramID {
+		return false
+	}
+	return len(data) == token.TokenAccountSize || (len(data) > token.TokenAccountSize && data[token.TokenAccountSize] == 2)
+}
@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	addr := types.NewAccount().PublicKey
+	parsed, err := ParseAddress(addr.ToBase58())
+	require.NoError(t, err)
+	assert.Equal(t, addr, parsed)
+
+	for _, bad := range []string{"", "not-base58!", "abc", addr.ToBase58() + "1"} {
+		_, err := ParseAddress(bad)
+		assert.ErrorIs(t, err, ErrInvalidAddress, bad)
+	}
+}
+
+func TestAddressBook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addressbook.json")
+	book, err := OpenAddressBook(path)
+	require.NoError(t, err)
+
+	alice := types.NewAccount().PublicKey.ToBase58()
+	require.NoError(t, book.Add(Contact{Name: "alice", Address: alice, Network: "devnet"}))
+	require.NoError(t, book.Add(Contact{Name: "alice", Address: alice, Network: "mainnet"}))
+	assert.Error(t, book.Add(Contact{Name: "alice", Address: types.NewAccount().PublicKey.ToBase58(), Network: "devnet"}))
+	assert.Error(t, book.Add(Contact{Name: "alice2", Address: alice, Network: "devnet"}))
+	assert.ErrorIs(t, book.Add(Contact{Name: "bob", Address: "bad", Network: "devnet"}), ErrInvalidAddress)
+	assert.Error(t, book.Add(Contact{Name: alice, Address: types.NewAccount().PublicKey.ToBase58(), Network: "devnet"}))
+	require.NoError(t, book.MarkSent("devnet", alice))
+
+	reopened, err := OpenAddressBook(path)
+	require.NoError(t, err)
+	assert.Len(t, reopened.List(""), 2)
+	assert.Len(t, reopened.List("devnet"), 1)
+	assert.True(t, reopened.HasSent("devnet", alice))
+	assert.False(t, reopened.HasSent("mainnet", alice))
+
+	require.NoError(t, reopened.Remove("devnet", "alice"))
+	_, err = reopened.Lookup("devnet", "alice")
+	assert.ErrorIs(t, err, ErrContactNotFound)
+	_, err = reopened.Lookup("mainnet", "alice")
+	assert.NoError(t, err)
+}
+
+func TestCheckRecipient(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	book, err := OpenAddressBook(filepath.Join(t.TempDir(), "addressbook.json"))
+	require.NoError(t, err)
+	wm.AddressBook = book
+
+	tokenAccount := types.NewAccount().PublicKey.ToBase58()
+	wallet := types.NewAccount().PublicKey.ToBase58()
+	stub.on("getAccountInfo", func(params []json.RawMessage) any {
+		var addr string
+		_ = json.Unmarshal(params[0], &addr)
+		switch addr {
+		case tokenAccount:
+			return withContext(accountInfo(common.TokenProgramID.ToBase58(), 2039280, make([]byte, token.TokenAccountSize)))
+		case wallet:
+			return withContext(accountInfo(common.SystemProgramID.ToBase58(), 1_000_000, nil))
+		}
+		return withContext(nil)
+	})
+
+	check, err := wm.CheckRecipient(context.Background(), tokenAccount)
+	require.NoError(t, err)
+	assert.True(t, check.HasWarning(WarningTokenAccount))
+	assert.True(t, check.HasWarning(WarningFirstTime))
+	assert.Equal(t, common.TokenProgramID.ToBase58(), check.Owner)
+
+	pda, _, err := common.FindProgramAddress([][]byte{[]byte("vault")}, common.TokenProgramID)
+	require.NoError(t, err)
+	check, err = wm.CheckRecipient(context.Background(), pda.ToBase58())
+	require.NoError(t, err)
+	assert.True(t, check.HasWarning(WarningOffCurve))
+
+	// 联系人按名称解析，不提示首次转账
+	require.NoError(t, book.Add(Contact{Name: "bob", Address: wallet, Network: "devnet", ExpectedOwner: common.SystemProgramID.ToBase58()}))
+	check, err = wm.CheckRecipient(context.Background(), "bob")
+	require.NoError(t, err)
+	assert.Equal(t, wallet, check.Address)
+	assert.Empty(t, check.Warnings)
+
+	require.NoError(t, book.Add(Contact{Name: "vault", Address: tokenAccount, Network: "devnet", ExpectedOwner: common.SystemProgramID.ToBase58()}))
+	_, err = wm.CheckRecipient(context.Background(), "vault")
+	assert.ErrorIs(t, err, ErrUnexpectedOwner)
+
+	_, err = wm.CheckRecipient(context.Background(), "carol")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+func TestTransferSOLRecipientValidation(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	book, err := OpenAddressBook(filepath.Join(t.TempDir(), "addressbook.json"))
+	require.NoError(t, err)
+	wm.AddressBook = book
+
+	_, err = wm.TransferSOL(context.Background(), "not-an-address", 1000)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Equal(t, 0, stub.callCount("sendTransaction"))
+
+	to := types.NewAccount().PublicKey.ToBase58()
+	_, err = wm.TransferSOL(context.Background(), to, 1000)
+	require.NoError(t, err)
+	assert.True(t, book.HasSent("devnet", to))
+
+	check, err := wm.CheckRecipient(context.Background(), to)
+	require.NoError(t, err)
+	assert.False(t, check.HasWarning(WarningFirstTime))
+}
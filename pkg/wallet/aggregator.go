@@ -0,0 +1,265 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// RaydiumTradeAPI Raydium 交易接口，按 Raydium 自己的池子计算路由
+	RaydiumTradeAPI = "https://transaction-v1.raydium.io"
+	// DefaultQuoteTimeout 每个报价来源的默认超时
+	DefaultQuoteTimeout = 5 * time.Second
+	// DefaultMaxPriceImpactPct 默认允许的最大价格影响（百分比）
+	DefaultMaxPriceImpactPct = 5.0
+)
+
+// ErrNoQuote 所有报价来源都失败或被排除
+var ErrNoQuote = errors.New("no usable quote")
+
+// QuoteRequest 报价请求，Amount 为输入代币的最小单位
+type QuoteRequest struct {
+	InputMint   string
+	OutputMint  string
+	Amount      uint64
+	SlippageBps int
+}
+
+// ProviderQuote 某个报价来源的报价
+type ProviderQuote struct {
+	Provider       string
+	InAmount       uint64
+	OutAmount      uint64   // 实际到手的输出数量，已扣除池子手续费和平台费，比较报价的依据
+	FeeAmount      uint64   // 其中的平台费（以输出代币计），仅供展示
+	PriceImpactPct float64  // 价格影响（百分比）
+	Route          []string // 经过的池子或 AMM
+	// Jupiter 报价来源为 Jupiter 时的原始报价
+	Jupiter *QuoteResponse
+}
+
+// QuoteProvider 报价来源
+type QuoteProvider interface {
+	Name() string
+	Quote(ctx context.Context, req QuoteRequest) (*ProviderQuote, error)
+}
+
+// QuoteComparison 比价结果
+type QuoteComparison struct {
+	Best   *ProviderQuote
+	Quotes []ProviderQuote  // 所有成功的报价，按 OutAmount 从高到低排列
+	Errors map[string]error // 失败的报价来源
+	Reason string           // 选择 Best 的理由，列出每个来源的结果
+}
+
+// QuoteAggregator 并行向多个来源询价，选出扣除费用后输出最多、价格影响在限制内的报价
+type QuoteAggregator struct {
+	Providers []QuoteProvider
+	// Timeout 每个来源的超时，默认 DefaultQuoteTimeout；超时的来源计入 Errors
+	Timeout time.Duration
+	// MaxPriceImpactPct 价格影响超过该值的报价不参与选择，默认 DefaultMaxPriceImpactPct
+	MaxPriceImpactPct float64
+}
+
+// NewQuoteAggregator 使用 providers 创建比价器，选择规则为默认值
+func NewQuoteAggregator(providers ...QuoteProvider) *QuoteAggregator {
+	return &QuoteAggregator{Providers: providers, Timeout: DefaultQuoteTimeout, MaxPriceImpactPct: DefaultMaxPriceImpactPct}
+}
+
+// Compare 并行获取所有来源的报价并选出最优报价。没有可用报价时返回 ErrNoQuote，结果中仍包含各来源的错误。
+func (a *QuoteAggregator) Compare(ctx context.Context, req QuoteRequest) (*QuoteComparison, error) {
+	if len(a.Providers) == 0 {
+		return nil, errors.New("no quote provider")
+	}
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = DefaultQuoteTimeout
+	}
+	maxImpact := a.MaxPriceImpactPct
+	if maxImpact <= 0 {
+		maxImpact = DefaultMaxPriceImpactPct
+	}
+
+	quotes := make([]*ProviderQuote, len(a.Providers))
+	errs := make([]error, len(a.Providers))
+	var wg sync.WaitGroup
+	for i, p := range a.Providers {
+		wg.Add(1)
+		go func(i int, p QuoteProvider) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			quotes[i], errs[i] = p.Quote(ctx, req)
+			if errs[i] == nil && quotes[i] == nil {
+				errs[i] = errors.New("provider returned no quote")
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	result := &QuoteComparison{Errors: map[string]error{}}
+	var lines []string
+	for i, p := range a.Providers {
+		if errs[i] != nil {
+			result.Errors[p.Name()] = errs[i]
+			lines = append(lines, fmt.Sprintf("%s: failed: %v", p.Name(), errs[i]))
+			continue
+		}
+		result.Quotes = append(result.Quotes, *quotes[i])
+	}
+	// 稳定排序：输出相同时价格影响小的优先，再相同时按来源顺序
+	sort.SliceStable(result.Quotes, func(i, j int) bool {
+		qi, qj := result.Quotes[i], result.Quotes[j]
+		if qi.OutAmount != qj.OutAmount {
+			return qi.OutAmount > qj.OutAmount
+		}
+		return qi.PriceImpactPct < qj.PriceImpactPct
+	})
+	for i := range result.Quotes {
+		q := &result.Quotes[i]
+		line := fmt.Sprintf("%s: out %d (platform fee %d), price impact %.4f%%", q.Provider, q.OutAmount, q.FeeAmount, q.PriceImpactPct)
+		switch {
+		case q.PriceImpactPct > maxImpact:
+			line += fmt.Sprintf(", excluded: price impact above %.2f%%", maxImpact)
+		case result.Best == nil:
+			result.Best = q
+			line += ", selected"
+		default:
+			line += fmt.Sprintf(", %d less than %s", result.Best.OutAmount-q.OutAmount, result.Best.Provider)
+		}
+		lines = append(lines, line)
+	}
+	result.Reason = strings.Join(lines, "\n")
+	if result.Best == nil {
+		return result, fmt.Errorf("%w:\n%s", ErrNoQuote, result.Reason)
+	}
+	return result, nil
+}
+
+// JupiterQuoteProvider 通过 WalletManager.GetQuoteContext 获取 Jupiter 报价
+type JupiterQuoteProvider struct {
+	Wallet   *WalletManager
+	QuoteAPI string // 为空时使用 JupiterQuoteAPI
+}
+
+// Name 返回来源名称
+func (p *JupiterQuoteProvider) Name() string { return "jupiter" }
+
+// Quote 获取 Jupiter 报价，outAmount 已扣除平台费
+func (p *JupiterQuoteProvider) Quote(ctx context.Context, req QuoteRequest) (*ProviderQuote, error) {
+	api := p.QuoteAPI
+	if api == "" {
+		api = JupiterQuoteAPI
+	}
+	quote, err := p.Wallet.GetQuoteContext(ctx, quoteURL(api, req.InputMint, req.OutputMint, req.Amount, req.SlippageBps))
+	if err != nil {
+		return nil, err
+	}
+	q := &ProviderQuote{Provider: p.Name(), Jupiter: quote}
+	if q.InAmount, err = strconv.ParseUint(quote.InAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid quote inAmount %q: %w", quote.InAmount, err)
+	}
+	if q.OutAmount, err = strconv.ParseUint(quote.OutAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid quote outAmount %q: %w", quote.OutAmount, err)
+	}
+	if quote.PriceImpactPct != "" {
+		// Jupiter 的 priceImpactPct 是比例（0.01 表示 1%）
+		impact, err := strconv.ParseFloat(quote.PriceImpactPct, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quote priceImpactPct %q: %w", quote.PriceImpactPct, err)
+		}
+		q.PriceImpactPct = impact * 100
+	}
+	if quote.PlatformFee != nil {
+		var fee struct {
+			Amount string `json:"amount"`
+		}
+		if raw, err := json.Marshal(quote.PlatformFee); err == nil && json.Unmarshal(raw, &fee) == nil && fee.Amount != "" {
+			if q.FeeAmount, err = strconv.ParseUint(fee.Amount, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid quote platformFee %q: %w", fee.Amount, err)
+			}
+		}
+	}
+	for _, r := range quote.RoutePlan {
+		q.Route = append(q.Route, r.SwapInfo.Label)
+	}
+	return q, nil
+}
+
+// RaydiumQuoteProvider 通过 Raydium 交易接口（compute/swap-base-in）获取报价
+type RaydiumQuoteProvider struct {
+	BaseURL string // 为空时使用 RaydiumTradeAPI
+}
+
+// Name 返回来源名称
+func (p *RaydiumQuoteProvider) Name() string { return "raydium" }
+
+// Quote 获取 Raydium 报价，输出数量已扣除池子手续费
+func (p *RaydiumQuoteProvider) Quote(ctx context.Context, req QuoteRequest) (*ProviderQuote, error) {
+	base := p.BaseURL
+	if base == "" {
+		base = RaydiumTradeAPI
+	}
+	query := url.Values{}
+	query.Set("inputMint", req.InputMint)
+	query.Set("outputMint", req.OutputMint)
+	query.Set("amount", strconv.FormatUint(req.Amount, 10))
+	query.Set("slippageBps", strconv.Itoa(req.SlippageBps))
+	query.Set("txVersion", "V0")
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(base, "/")+"/compute/swap-base-in?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote url: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("quote request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quote response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("quote failed: %s", truncate(string(body), maxLoggedBody))
+	}
+
+	var result struct {
+		Success bool   `json:"success"`
+		Msg     string `json:"msg"`
+		Data    struct {
+			InputAmount    string  `json:"inputAmount"`
+			OutputAmount   string  `json:"outputAmount"`
+			PriceImpactPct float64 `json:"priceImpactPct"`
+			RoutePlan      []struct {
+				PoolID string `json:"poolId"`
+			} `json:"routePlan"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode quote: %w", err)
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("quote failed: %s", result.Msg)
+	}
+	q := &ProviderQuote{Provider: p.Name(), PriceImpactPct: result.Data.PriceImpactPct}
+	if q.InAmount, err = strconv.ParseUint(result.Data.InputAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid quote inputAmount %q: %w", result.Data.InputAmount, err)
+	}
+	if q.OutAmount, err = strconv.ParseUint(result.Data.OutputAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid quote outputAmount %q: %w", result.Data.OutputAmount, err)
+	}
+	for _, r := range result.Data.RoutePlan {
+		q.Route = append(q.Route, r.PoolID)
+	}
+	return q, nil
+}
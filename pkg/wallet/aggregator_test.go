@@ -0,0 +1,130 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowQuoteProvider 在 ctx 结束前不返回报价
+type slowQuoteProvider struct{}
+
+func (slowQuoteProvider) Name() string { return "slow" }
+
+func (slowQuoteProvider) Quote(ctx context.Context, _ QuoteRequest) (*ProviderQuote, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// emptyQuoteProvider 既不返回报价也不返回错误
+type emptyQuoteProvider struct{}
+
+func (emptyQuoteProvider) Name() string { return "empty" }
+
+func (emptyQuoteProvider) Quote(context.Context, QuoteRequest) (*ProviderQuote, error) {
+	return nil, nil
+}
+
+// newQuoteServers 本地 Jupiter 和 Raydium 报价接口，返回给定的输出数量和价格影响
+func newQuoteServers(t *testing.T, jupiterOut string, jupiterImpact string, raydiumOut string, raydiumImpact float64) (string, string) {
+	t.Helper()
+	jupiter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "250", r.URL.Query().Get("slippageBps"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"inputMint": r.URL.Query().Get("inputMint"), "inAmount": r.URL.Query().Get("amount"),
+			"outputMint": r.URL.Query().Get("outputMint"), "outAmount": jupiterOut,
+			"priceImpactPct": jupiterImpact,
+			"platformFee":    map[string]any{"amount": "30", "feeBps": 20},
+			"routePlan":      []any{map[string]any{"swapInfo": map[string]any{"label": "Orca"}, "percent": 100}},
+		})
+	}))
+	t.Cleanup(jupiter.Close)
+	raydium := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/compute/swap-base-in", r.URL.Path)
+		assert.Equal(t, "250", r.URL.Query().Get("slippageBps"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"success": true,
+			"data": map[string]any{
+				"inputAmount": r.URL.Query().Get("amount"), "outputAmount": raydiumOut,
+				"priceImpactPct": raydiumImpact,
+				"routePlan":      []any{map[string]any{"poolId": "pool1"}},
+			},
+		})
+	}))
+	t.Cleanup(raydium.Close)
+	return jupiter.URL, raydium.URL
+}
+
+func TestQuoteAggregatorSelectsBest(t *testing.T) {
+	wm, _ := newErrorTestWallet(t, rpcStubError{})
+	jupiterAPI, raydiumAPI := newQuoteServers(t, "1000", "0.001", "1010", 0.2)
+	a := NewQuoteAggregator(
+		&JupiterQuoteProvider{Wallet: wm, QuoteAPI: jupiterAPI},
+		&RaydiumQuoteProvider{BaseURL: raydiumAPI},
+		slowQuoteProvider{},
+	)
+	a.Timeout = 50 * time.Millisecond
+	req := QuoteRequest{InputMint: SOL_MINT_ADDR, OutputMint: "mint", Amount: 500, SlippageBps: 250}
+
+	result, err := a.Compare(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, result.Best)
+	assert.Equal(t, "raydium", result.Best.Provider)
+	assert.Equal(t, []string{"pool1"}, result.Best.Route)
+	require.Len(t, result.Quotes, 2)
+	jupiter := result.Quotes[1]
+	assert.Equal(t, uint64(1000), jupiter.OutAmount)
+	assert.Equal(t, uint64(30), jupiter.FeeAmount)
+	assert.InDelta(t, 0.1, jupiter.PriceImpactPct, 1e-9)
+	assert.Equal(t, []string{"Orca"}, jupiter.Route)
+	require.NotNil(t, jupiter.Jupiter)
+	assert.ErrorIs(t, result.Errors["slow"], context.DeadlineExceeded)
+	assert.Equal(t, strings.Join([]string{
+		"slow: failed: context deadline exceeded",
+		"raydium: out 1010 (platform fee 0), price impact 0.2000%, selected",
+		"jupiter: out 1000 (platform fee 30), price impact 0.1000%, 10 less than raydium",
+	}, "\n"), result.Reason)
+
+	// 价格影响超过限制的报价被排除
+	a.MaxPriceImpactPct = 0.15
+	result, err = a.Compare(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "jupiter", result.Best.Provider)
+	assert.Contains(t, result.Reason, "raydium: out 1010 (platform fee 0), price impact 0.2000%, excluded: price impact above 0.15%")
+}
+
+func TestQuoteAggregatorNoQuote(t *testing.T) {
+	wm, _ := newErrorTestWallet(t, rpcStubError{})
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/compute") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"success": false, "msg": "ROUTE_NOT_FOUND"})
+			return
+		}
+		http.Error(w, "no route", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+
+	a := NewQuoteAggregator(&JupiterQuoteProvider{Wallet: wm, QuoteAPI: failing.URL}, &RaydiumQuoteProvider{BaseURL: failing.URL})
+	result, err := a.Compare(context.Background(), QuoteRequest{InputMint: SOL_MINT_ADDR, OutputMint: "mint", Amount: 1})
+	assert.ErrorIs(t, err, ErrNoQuote)
+	require.NotNil(t, result)
+	assert.Len(t, result.Errors, 2)
+	assert.ErrorContains(t, result.Errors["raydium"], "ROUTE_NOT_FOUND")
+
+	_, err = NewQuoteAggregator().Compare(context.Background(), QuoteRequest{})
+	assert.Error(t, err)
+
+	// 没有报价也没有错误的来源按失败处理
+	result, err = NewQuoteAggregator(emptyQuoteProvider{}).Compare(context.Background(), QuoteRequest{})
+	assert.ErrorIs(t, err, ErrNoQuote)
+	require.NotNil(t, result)
+	assert.Empty(t, result.Quotes)
+	assert.ErrorContains(t, result.Errors["empty"], "provider returned no quote")
+}
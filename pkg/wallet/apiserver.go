@@ -0,0 +1,612 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+// API 服务相关常量
+const (
+	// IdempotencyKeyHeader 修改类请求必须携带的幂等键请求头
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayHeader 返回缓存结果时设置的响应头
+	idempotentReplayHeader = "Idempotent-Replayed"
+
+	defaultIdempotencyTTL = 24 * time.Hour
+	maxAPIRequestBody     = 1 << 20
+	maxSlippageBps        = 10_000
+)
+
+// APIServerConfig HTTP API 服务配置
+type APIServerConfig struct {
+	// APIKeys 允许访问的 API key，客户端通过 Authorization: Bearer <key> 或 X-API-Key 请求头发送。
+	// 为空时拒绝所有请求。
+	APIKeys []string
+	// IdempotencyTTL 幂等键对应结果的保留时间，为 0 时使用 24 小时
+	IdempotencyTTL time.Duration
+}
+
+// apiErrorBody API 错误响应格式
+type apiErrorBody struct {
+	Error apiErrorDetail `json:"error"`
+}
+
+type apiErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// API 请求和响应格式，字段含义见 cmd/server/openapi.yaml
+type (
+	walletResponse struct {
+		PublicKey string `json:"publicKey"`
+		Network   string `json:"network"`
+	}
+	balanceResponse struct {
+		Mint   string `json:"mint"`
+		Amount uint64 `json:"amount"`
+		// 仅 SOL：amount 为原生余额，wrapped 为 wSOL 账户余额
+		Wrapped *uint64 `json:"wrapped,omitempty"`
+	}
+	transferSOLRequest struct {
+		To       string `json:"to"`
+		Lamports uint64 `json:"lamports"`
+		Memo     string `json:"memo,omitempty"`
+	}
+	transferTokenRequest struct {
+		Mint        string `json:"mint"`
+		Source      string `json:"source,omitempty"` // 为空时使用当前账户的关联代币账户
+		Destination string `json:"destination"`
+		Amount      uint64 `json:"amount"`
+		Decimals    uint8  `json:"decimals"`
+		Memo        string `json:"memo,omitempty"`
+	}
+	tokenAccountRequest struct {
+		Mint string `json:"mint"`
+	}
+	tokenAccountResponse struct {
+		Address string `json:"address"`
+	}
+	mintResponse struct {
+		Mint      string `json:"mint"`
+		Signature string `json:"signature"`
+	}
+	swapRequest struct {
+		InputMint   string `json:"inputMint"`
+		OutputMint  string `json:"outputMint"`
+		Amount      uint64 `json:"amount"`
+		SlippageBps *int   `json:"slippageBps,omitempty"`
+		// WrapAndUnwrapSOL 为 false 时兑换直接使用 wSOL 账户，见 WithWrapAndUnwrapSOL
+		WrapAndUnwrapSOL *bool `json:"wrapAndUnwrapSol,omitempty"`
+	}
+	swapResponse struct {
+		Signature string         `json:"signature"`
+		Quote     *QuoteResponse `json:"quote"`
+	}
+	signatureResponse struct {
+		Signature string `json:"signature"`
+	}
+)
+
+// apiServer 把 WalletManager 的操作暴露为 REST 接口
+type apiServer struct {
+	wm          *WalletManager
+	apiKeys     apiKeySet
+	idempotency *idempotencyStore
+}
+
+// NewAPIServer 返回钱包 REST API 的 HTTP handler。
+// 所有 /v1 接口都需要 API key，POST 接口还必须携带 Idempotency-Key：
+// 相同 API key 和幂等键的重试直接返回第一次的结果（包括错误），不会重复发送交易。
+func NewAPIServer(wm *WalletManager, cfg APIServerConfig) http.Handler {
+	ttl := cfg.IdempotencyTTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	s := &apiServer{wm: wm, apiKeys: newAPIKeySet(cfg.APIKeys), idempotency: newIdempotencyStore(ttl)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /v1/wallet", s.auth(s.handleWallet))
+	mux.Handle("GET /v1/balances/{mint}", s.auth(s.handleBalance))
+	mux.Handle("GET /v1/quote", s.auth(s.handleQuote))
+	mux.Handle("POST /v1/transfers/sol", s.auth(s.idempotent(s.handleTransferSOL)))
+	mux.Handle("POST /v1/transfers/token", s.auth(s.idempotent(s.handleTransferToken)))
+	mux.Handle("POST /v1/token-accounts", s.auth(s.idempotent(s.handleCreateTokenAccount)))
+	mux.Handle("POST /v1/mints", s.auth(s.idempotent(s.handleCreateMint)))
+	mux.Handle("POST /v1/swaps", s.auth(s.idempotent(s.handleSwap)))
+	return mux
+}
+
+// auth 校验 API key，通过后把 key 传给 next，幂等键按调用方隔离
+func (s *apiServer) auth(next func(http.ResponseWriter, *http.Request, string)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			key = bearer
+		}
+		if !s.apiKeys.valid(key) {
+			writeAPIError(w, http.StatusUnauthorized, "unauthorized", "missing or invalid API key")
+			return
+		}
+		next(w, r, key)
+	})
+}
+
+// apiKeySet REST 和 gRPC 服务允许的 API key
+type apiKeySet [][]byte
+
+func newAPIKeySet(keys []string) apiKeySet {
+	var set apiKeySet
+	for _, key := range keys {
+		if key != "" {
+			set = append(set, []byte(key))
+		}
+	}
+	return set
+}
+
+// valid 以固定时间比较 API key
+func (set apiKeySet) valid(key string) bool {
+	if key == "" {
+		return false
+	}
+	valid := 0
+	for _, k := range set {
+		valid |= subtle.ConstantTimeCompare(k, []byte(key))
+	}
+	return valid == 1
+}
+
+// idempotent 要求请求携带幂等键，重复请求返回第一次的响应
+func (s *apiServer) idempotent(next func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request, string) {
+	return func(w http.ResponseWriter, r *http.Request, apiKey string) {
+		idemKey := r.Header.Get(IdempotencyKeyHeader)
+		if idemKey == "" || len(idemKey) > 255 {
+			writeAPIError(w, http.StatusBadRequest, "invalid_request", IdempotencyKeyHeader+" header is required (at most 255 characters)")
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAPIRequestBody))
+		if err != nil {
+			writeAPIError(w, http.StatusRequestEntityTooLarge, "invalid_request", "request body too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := sha256.Sum256([]byte(r.Method + " " + r.URL.Path + "\n" + string(body)))
+		entry, replay, err := s.idempotency.begin(apiKey+"\x00"+idemKey, fingerprint)
+		switch {
+		case errors.Is(err, errIdempotencyKeyReused):
+			writeAPIError(w, http.StatusUnprocessableEntity, "idempotency_key_reused", err.Error())
+			return
+		case errors.Is(err, errIdempotencyInProgress):
+			writeAPIError(w, http.StatusConflict, "request_in_progress", err.Error())
+			return
+		}
+		if replay {
+			w.Header().Set(idempotentReplayHeader, "true")
+			writeAPIRaw(w, entry.status, entry.body)
+			return
+		}
+
+		// handler panic 或没有保存结果时释放幂等键，否则同一个键会一直返回 409
+		saved := false
+		defer func() {
+			if !saved {
+				s.idempotency.abandon(entry)
+			}
+		}()
+		ctx, broadcast := withBroadcastTracker(r.Context())
+		rec := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+		next(rec, r.WithContext(ctx))
+		// 交易还没有广播时，服务端错误和客户端断开都不是最终结果，不缓存，客户端可以用同一个键重试
+		if broadcast.Load() || (rec.status < http.StatusInternalServerError && r.Context().Err() == nil) {
+			s.idempotency.finish(entry, rec.status, rec.body.Bytes())
+			saved = true
+		}
+		writeAPIRaw(w, rec.status, rec.body.Bytes())
+	}
+}
+
+func (s *apiServer) handleWallet(w http.ResponseWriter, r *http.Request, _ string) {
+	writeAPIJSON(w, http.StatusOK, walletResponse{PublicKey: s.wm.PublicKey().ToBase58(), Network: s.wm.Network})
+}
+
+func (s *apiServer) handleBalance(w http.ResponseWriter, r *http.Request, _ string) {
+	mint := r.PathValue("mint")
+	if strings.EqualFold(mint, "sol") {
+		mint = SOL_MINT_ADDR
+	}
+	if err := validatePublicKey("mint", mint); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if mint == SOL_MINT_ADDR {
+		balance, err := s.wm.SOLBalances(r.Context())
+		if err != nil {
+			writeWalletError(w, err)
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, balanceResponse{Mint: mint, Amount: balance.Native, Wrapped: &balance.Wrapped})
+		return
+	}
+	amount, err := s.wm.CheckAmount(r.Context(), mint)
+	if err != nil {
+		writeWalletError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, balanceResponse{Mint: mint, Amount: amount})
+}
+
+func (s *apiServer) handleQuote(w http.ResponseWriter, r *http.Request, _ string) {
+	query := r.URL.Query()
+	req := swapRequest{InputMint: query.Get("inputMint"), OutputMint: query.Get("outputMint")}
+	amount, err := strconv.ParseUint(query.Get("amount"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", "amount must be a positive integer")
+		return
+	}
+	req.Amount = amount
+	if raw := query.Get("slippageBps"); raw != "" {
+		bps, err := strconv.Atoi(raw)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid_request", "slippageBps must be an integer")
+			return
+		}
+		req.SlippageBps = &bps
+	}
+	if err := req.validate(); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	quote, err := s.wm.GetQuoteContext(r.Context(), QuoteURL(req.InputMint, req.OutputMint, req.Amount, req.slippage()))
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "quote_failed", err.Error())
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, quote)
+}
+
+func (s *apiServer) handleTransferSOL(w http.ResponseWriter, r *http.Request) {
+	var req transferSOLRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := validatePublicKey("to", req.To); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if req.Lamports == 0 {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", "lamports must be greater than 0")
+		return
+	}
+	opts, ok := memoOpts(w, req.Memo)
+	if !ok {
+		return
+	}
+	txhash, err := s.wm.TransferSOL(r.Context(), req.To, req.Lamports, opts...)
+	if err != nil {
+		writeWalletError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, signatureResponse{Signature: txhash})
+}
+
+// memoOpts 校验请求中的 memo，不合法时写入 400 响应并返回 false
+func memoOpts(w http.ResponseWriter, memo string) ([]TxOption, bool) {
+	if memo == "" {
+		return nil, true
+	}
+	if err := ValidateMemo(memo); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return nil, false
+	}
+	return []TxOption{WithMemo(memo)}, true
+}
+
+func (s *apiServer) handleTransferToken(w http.ResponseWriter, r *http.Request) {
+	var req transferTokenRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := validatePublicKey("mint", req.Mint); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if err := validatePublicKey("destination", req.Destination); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if req.Source == "" {
+		ata, _, err := common.FindAssociatedTokenAddress(s.wm.PublicKey(), common.PublicKeyFromString(req.Mint))
+		if err != nil {
+			writeWalletError(w, err)
+			return
+		}
+		req.Source = ata.ToBase58()
+	} else if err := validatePublicKey("source", req.Source); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if req.Amount == 0 {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", "amount must be greater than 0")
+		return
+	}
+
+	opts, ok := memoOpts(w, req.Memo)
+	if !ok {
+		return
+	}
+
+	// 源代币账户的所有者是当前钱包
+	txhash, err := s.wm.TransferTokensChecked(r.Context(), req.Mint, s.wm.signer(), req.Source, req.Destination, req.Amount, req.Decimals, opts...)
+	if err != nil {
+		writeWalletError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, signatureResponse{Signature: txhash})
+}
+
+func (s *apiServer) handleCreateTokenAccount(w http.ResponseWriter, r *http.Request) {
+	var req tokenAccountRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := validatePublicKey("mint", req.Mint); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	ata, err := s.wm.CreateTokenAccount(r.Context(), req.Mint)
+	if err != nil {
+		writeWalletError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, tokenAccountResponse{Address: ata})
+}
+
+func (s *apiServer) handleCreateMint(w http.ResponseWriter, r *http.Request) {
+	var req struct{}
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	mint, txhash, err := s.wm.CreateMint(r.Context(), s.wm.signer())
+	if err != nil {
+		writeWalletError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, mintResponse{Mint: mint, Signature: txhash})
+}
+
+func (s *apiServer) handleSwap(w http.ResponseWriter, r *http.Request) {
+	var req swapRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	var opts []SwapOption
+	if req.WrapAndUnwrapSOL != nil {
+		opts = append(opts, WithWrapAndUnwrapSOL(*req.WrapAndUnwrapSOL))
+	}
+	quote, txhash, err := s.wm.Swap(r.Context(), req.InputMint, req.OutputMint, req.Amount, req.slippage(), opts...)
+	if err != nil {
+		writeWalletError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, swapResponse{Signature: txhash, Quote: quote})
+}
+
+func (req swapRequest) validate() error {
+	if err := validatePublicKey("inputMint", req.InputMint); err != nil {
+		return err
+	}
+	if err := validatePublicKey("outputMint", req.OutputMint); err != nil {
+		return err
+	}
+	if req.InputMint == req.OutputMint {
+		return errors.New("inputMint and outputMint must differ")
+	}
+	if req.Amount == 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if req.SlippageBps != nil && (*req.SlippageBps < 0 || *req.SlippageBps > maxSlippageBps) {
+		return fmt.Errorf("slippageBps must be between 0 and %d", maxSlippageBps)
+	}
+	return nil
+}
+
+func (req swapRequest) slippage() int {
+	if req.SlippageBps == nil {
+		return defaultSlippageBps
+	}
+	return *req.SlippageBps
+}
+
+// validatePublicKey 检查字段是否为 base58 编码的 32 字节公钥
+func validatePublicKey(field string, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	if _, err := ParseAddress(value); err != nil {
+		return fmt.Errorf("%s is not a valid base58 public key", field)
+	}
+	return nil
+}
+
+// decodeAPIRequest 解析 JSON 请求体，拒绝未知字段，失败时写入 400 响应
+func decodeAPIRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeWalletError 按错误类型选择状态码：余额不足等业务错误为 422，RPC 节点错误为 502
+func writeWalletError(w http.ResponseWriter, err error) {
+	var rpcErr *RPCError
+	var programErr *ProgramError
+	var violation *PolicyViolation
+	switch {
+	case errors.As(err, &violation):
+		writeAPIError(w, http.StatusForbidden, "policy_violation", err.Error())
+	case errors.Is(err, ErrInvalidAddress), errors.Is(err, ErrInvalidMemo):
+		writeAPIError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	case errors.Is(err, ErrUnexpectedOwner):
+		writeAPIError(w, http.StatusUnprocessableEntity, "unexpected_owner", err.Error())
+	case errors.Is(err, ErrInsufficientFunds):
+		writeAPIError(w, http.StatusUnprocessableEntity, "insufficient_funds", err.Error())
+	case errors.Is(err, ErrBelowRentExempt):
+		writeAPIError(w, http.StatusUnprocessableEntity, "below_rent_exempt", err.Error())
+	case errors.Is(err, ErrAccountNotFound):
+		writeAPIError(w, http.StatusNotFound, "account_not_found", err.Error())
+	case errors.Is(err, ErrSlippageExceeded):
+		writeAPIError(w, http.StatusUnprocessableEntity, "slippage_exceeded", err.Error())
+	case errors.Is(err, ErrBlockhashExpired):
+		writeAPIError(w, http.StatusServiceUnavailable, "blockhash_expired", err.Error())
+	case errors.As(err, &programErr):
+		writeAPIError(w, http.StatusUnprocessableEntity, "program_error", err.Error())
+	case errors.As(err, &rpcErr):
+		writeAPIError(w, http.StatusBadGateway, "rpc_error", err.Error())
+	default:
+		writeAPIError(w, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, code string, message string) {
+	writeAPIJSON(w, status, apiErrorBody{Error: apiErrorDetail{Code: code, Message: message}})
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body = []byte(`{"error":{"code":"internal_error","message":"failed to encode response"}}`)
+	}
+	writeAPIRaw(w, status, append(body, '\n'))
+}
+
+func writeAPIRaw(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// responseRecorder 记录 handler 的响应，用于保存幂等结果
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header         { return r.header }
+func (r *responseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *responseRecorder) WriteHeader(status int)      { r.status = status }
+
+var (
+	errIdempotencyKeyReused  = errors.New("idempotency key was already used with a different request")
+	errIdempotencyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// idempotencyStore 内存中的幂等键结果，服务重启后清空
+type idempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]*idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	key         string
+	fingerprint [sha256.Size]byte
+	done        bool
+	status      int
+	body        []byte
+	expires     time.Time // 完成后为 ttl 之后，未完成的请求不过期
+}
+
+func newIdempotencyStore(ttl time.Duration) *idempotencyStore {
+	return &idempotencyStore{ttl: ttl, now: time.Now, entries: map[string]*idempotencyEntry{}}
+}
+
+// begin 登记一次请求。已有完成的结果时返回该结果和 replay=true
+func (s *idempotencyStore) begin(key string, fingerprint [sha256.Size]byte) (*idempotencyEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	// 进行中的请求（例如还在等待确认）不能过期，否则重试会再执行一次；handler 返回时一定会 finish 或 abandon
+	for k, e := range s.entries {
+		if e.done && now.After(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+
+	if e, ok := s.entries[key]; ok {
+		if e.fingerprint != fingerprint {
+			return nil, false, errIdempotencyKeyReused
+		}
+		if !e.done {
+			return nil, false, errIdempotencyInProgress
+		}
+		return e, true, nil
+	}
+	e := &idempotencyEntry{key: key, fingerprint: fingerprint}
+	s.entries[key] = e
+	return e, false, nil
+}
+
+// finish 保存请求结果
+func (s *idempotencyStore) finish(e *idempotencyEntry, status int, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.done = true
+	e.status = status
+	e.body = append([]byte(nil), body...)
+	e.expires = s.now().Add(s.ttl)
+}
+
+// abandon 删除未完成的请求，之后同一个键按新请求处理
+func (s *idempotencyStore) abandon(e *idempotencyEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !e.done && s.entries[e.key] == e {
+		delete(s.entries, e.key)
+	}
+}
+
+// broadcastTrackerKey 请求 ctx 中记录交易是否已经开始广播
+type broadcastTrackerKey struct{}
+
+// withBroadcastTracker 返回带广播标记的 ctx，sendTransaction 开始广播时设置该标记
+func withBroadcastTracker(ctx context.Context) (context.Context, *atomic.Bool) {
+	broadcast := &atomic.Bool{}
+	return context.WithValue(ctx, broadcastTrackerKey{}, broadcast), broadcast
+}
+
+// markBroadcast 标记交易已开始广播，之后请求的结果不能再当作未执行
+func markBroadcast(ctx context.Context) {
+	if broadcast, ok := ctx.Value(broadcastTrackerKey{}).(*atomic.Bool); ok {
+		broadcast.Store(true)
+	}
+}
@@ -0,0 +1,204 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAPIKey = "test-key"
+
+// newTestAPIServer 返回余额为 1 SOL、发送交易总是成功的 API 服务
+func newTestAPIServer(t *testing.T) (*httptest.Server, *rpcStub) {
+	t.Helper()
+	stub, c := newRPCStub(t)
+	stub.on("getBalance", func([]json.RawMessage) any { return withContext(uint64(1_000_000_000)) })
+	stub.on("getLatestBlockhash", func([]json.RawMessage) any {
+		return withContext(map[string]any{"blockhash": testBlockhash, "lastValidBlockHeight": 100})
+	})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	// wSOL 账户不存在
+	stub.on("getAccountInfo", func([]json.RawMessage) any { return withContext(nil) })
+
+	wm := &WalletManager{Client: c, Network: "devnet", Account: types.NewAccount(), Logger: NewLogger(io.Discard, nil)}
+	server := httptest.NewServer(NewAPIServer(wm, APIServerConfig{APIKeys: []string{testAPIKey}}))
+	t.Cleanup(server.Close)
+	return server, stub
+}
+
+func apiRequest(t *testing.T, method string, url string, body string, headers map[string]string) (*http.Response, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return resp, decoded
+}
+
+func errorCode(body map[string]any) string {
+	detail, _ := body["error"].(map[string]any)
+	code, _ := detail["code"].(string)
+	return code
+}
+
+func TestAPIServerAuth(t *testing.T) {
+	server, _ := newTestAPIServer(t)
+
+	resp, body := apiRequest(t, http.MethodGet, server.URL+"/v1/balances/sol", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "unauthorized", errorCode(body))
+
+	resp, _ = apiRequest(t, http.MethodGet, server.URL+"/v1/balances/sol", "", map[string]string{"Authorization": "Bearer wrong"})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, body = apiRequest(t, http.MethodGet, server.URL+"/v1/balances/sol", "", map[string]string{"X-API-Key": testAPIKey})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, SOL_MINT_ADDR, body["mint"])
+	assert.Equal(t, float64(1_000_000_000), body["amount"])
+	assert.Equal(t, float64(0), body["wrapped"])
+
+	resp, _ = apiRequest(t, http.MethodGet, server.URL+"/healthz", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestAPIServerValidation(t *testing.T) {
+	server, stub := newTestAPIServer(t)
+	headers := map[string]string{"Authorization": "Bearer " + testAPIKey, IdempotencyKeyHeader: "v1"}
+	to := types.NewAccount().PublicKey.ToBase58()
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"invalid address", "/v1/transfers/sol", `{"to":"not-a-key","lamports":1}`},
+		{"zero amount", "/v1/transfers/sol", `{"to":"` + to + `","lamports":0}`},
+		{"unknown field", "/v1/transfers/sol", `{"to":"` + to + `","lamports":1,"from":"x"}`},
+		{"malformed json", "/v1/transfers/sol", `{"to":`},
+		{"memo too long", "/v1/transfers/sol", `{"to":"` + to + `","lamports":1,"memo":"` + strings.Repeat("x", MaxMemoLength+1) + `"}`},
+		{"same mints", "/v1/swaps", `{"inputMint":"` + SOL_MINT_ADDR + `","outputMint":"` + SOL_MINT_ADDR + `","amount":1}`},
+		{"slippage out of range", "/v1/swaps", `{"inputMint":"` + SOL_MINT_ADDR + `","outputMint":"` + GOAT_MINT_ADDR + `","amount":1,"slippageBps":20000}`},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers[IdempotencyKeyHeader] = "validation-" + string(rune('a'+i))
+			resp, body := apiRequest(t, http.MethodPost, server.URL+tt.path, tt.body, headers)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Equal(t, "invalid_request", errorCode(body))
+		})
+	}
+
+	delete(headers, IdempotencyKeyHeader)
+	resp, _ := apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", `{"to":"`+to+`","lamports":1}`, headers)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, 0, stub.callCount("sendTransaction"))
+}
+
+func TestAPIServerIdempotency(t *testing.T) {
+	server, stub := newTestAPIServer(t)
+	headers := map[string]string{"Authorization": "Bearer " + testAPIKey, IdempotencyKeyHeader: "transfer-1"}
+	body := `{"to":"` + types.NewAccount().PublicKey.ToBase58() + `","lamports":1000}`
+
+	resp, first := apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", body, headers)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "5Sig", first["signature"])
+	assert.Empty(t, resp.Header.Get(idempotentReplayHeader))
+
+	// 相同幂等键的重试返回第一次的结果，不重复发送交易
+	resp, second := apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", body, headers)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, first, second)
+	assert.Equal(t, "true", resp.Header.Get(idempotentReplayHeader))
+	assert.Equal(t, 1, stub.callCount("sendTransaction"))
+
+	// 同一个幂等键用于不同的请求体
+	resp, conflict := apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol",
+		`{"to":"`+types.NewAccount().PublicKey.ToBase58()+`","lamports":1000}`, headers)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "idempotency_key_reused", errorCode(conflict))
+
+	// 错误结果同样会被保存
+	headers[IdempotencyKeyHeader] = "transfer-2"
+	tooMuch := `{"to":"` + types.NewAccount().PublicKey.ToBase58() + `","lamports":2000000000}`
+	resp, failed := apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", tooMuch, headers)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "insufficient_funds", errorCode(failed))
+	resp, _ = apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", tooMuch, headers)
+	assert.Equal(t, "true", resp.Header.Get(idempotentReplayHeader))
+	assert.Equal(t, 1, stub.callCount("sendTransaction"))
+}
+
+func TestAPIServerIdempotencyRetriesUnbroadcastFailures(t *testing.T) {
+	server, stub := newTestAPIServer(t)
+	headers := map[string]string{"Authorization": "Bearer " + testAPIKey, IdempotencyKeyHeader: "transfer-1"}
+	body := `{"to":"` + types.NewAccount().PublicKey.ToBase58() + `","lamports":1000}`
+
+	// 广播前 RPC 失败的结果不缓存，同一个键可以重试
+	stub.on("getLatestBlockhash", func([]json.RawMessage) any { return rpcStubError{Code: -32005, Message: "node is behind"} })
+	resp, failed := apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", body, headers)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, "rpc_error", errorCode(failed))
+	stub.on("getLatestBlockhash", func([]json.RawMessage) any {
+		return withContext(map[string]any{"blockhash": testBlockhash, "lastValidBlockHeight": 100})
+	})
+	resp, ok := apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", body, headers)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(idempotentReplayHeader))
+	assert.Equal(t, "5Sig", ok["signature"])
+
+	// 广播时失败的结果可能已经上链，必须缓存
+	headers[IdempotencyKeyHeader] = "transfer-2"
+	stub.on("sendTransaction", func([]json.RawMessage) any { return rpcStubError{Code: -32603, Message: "internal error"} })
+	resp, _ = apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", body, headers)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	resp, _ = apiRequest(t, http.MethodPost, server.URL+"/v1/transfers/sol", body, headers)
+	assert.Equal(t, "true", resp.Header.Get(idempotentReplayHeader))
+	assert.Equal(t, 2, stub.callCount("sendTransaction"))
+}
+
+func TestIdempotencyStoreReleasesUnfinishedEntries(t *testing.T) {
+	store := newIdempotencyStore(time.Hour)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	fingerprint := sha256.Sum256([]byte("request"))
+
+	// handler panic 后释放幂等键
+	s := &apiServer{idempotency: store}
+	handler := s.idempotent(func(http.ResponseWriter, *http.Request) { panic("boom") })
+	req := httptest.NewRequest(http.MethodPost, "/v1/transfers/sol", strings.NewReader("request"))
+	req.Header.Set(IdempotencyKeyHeader, "panic")
+	assert.Panics(t, func() { handler(httptest.NewRecorder(), req, testAPIKey) })
+	assert.Empty(t, store.entries)
+
+	// 进行中的请求不会过期，结果的保留时间从完成时开始计算
+	e, _, err := store.begin("slow", fingerprint)
+	require.NoError(t, err)
+	now = now.Add(2 * time.Hour)
+	_, _, err = store.begin("slow", fingerprint)
+	assert.ErrorIs(t, err, errIdempotencyInProgress)
+	store.finish(e, http.StatusOK, []byte("done"))
+	now = now.Add(30 * time.Minute)
+	_, replay, err := store.begin("slow", fingerprint)
+	require.NoError(t, err)
+	assert.True(t, replay)
+	now = now.Add(time.Hour)
+	_, replay, err = store.begin("slow", fingerprint)
+	require.NoError(t, err)
+	assert.False(t, replay)
+}
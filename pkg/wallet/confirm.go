@@ -0,0 +1,70 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/rpc"
+)
+
+// confirmPollInterval 查询交易状态的间隔
+var confirmPollInterval = 500 * time.Millisecond
+
+// WaitForConfirmation 轮询交易状态直到达到 confirmed，交易执行失败时返回解析后的错误
+// （例如 *ProgramError）。ctx 用于控制最长等待时间，建议在发送后立即调用。
+func (wm *WalletManager) WaitForConfirmation(ctx context.Context, signature string) (err error) {
+	ctx, span := wm.startOperation(ctx, "WaitForConfirmation", attrSignature.String(signature))
+	defer func() { endSpan(span, err) }()
+
+	start := time.Now()
+	ticker := time.NewTicker(confirmPollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := wm.Client.GetSignatureStatus(ctx, signature)
+		if err != nil {
+			return fmt.Errorf("failed to get signature status: %w", wrapRPCError(err, nil))
+		}
+		if status != nil && status.Err != nil {
+			txErr := parseTransactionError(status.Err, nil)
+			if txErr == nil {
+				txErr = fmt.Errorf("unrecognized transaction error %v", status.Err)
+			}
+			wm.Metrics.observeConfirmation(wm.Network, time.Since(start), txErr)
+			wm.logger().ErrorContext(ctx, "transaction failed on chain", "operation", "confirm", "network", wm.Network,
+				"signature", signature, "latency", time.Since(start), "error", txErr)
+			// 上链但执行失败的交易同样扣了手续费
+			wm.recordFee(ctx, signature)
+			return fmt.Errorf("transaction %s failed: %w", signature, txErr)
+		}
+		if status != nil && status.ConfirmationStatus != nil &&
+			(*status.ConfirmationStatus == rpc.CommitmentConfirmed || *status.ConfirmationStatus == rpc.CommitmentFinalized) {
+			elapsed := time.Since(start)
+			wm.Metrics.observeConfirmation(wm.Network, elapsed, nil)
+			wm.logger().InfoContext(ctx, "transaction confirmed", "operation", "confirm", "network", wm.Network,
+				"signature", signature, "slot", status.Slot, "latency", elapsed)
+			wm.recordFee(ctx, signature)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("transaction %s not confirmed: %w", signature, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordFee 启用指标时查询已上链交易（包括执行失败的）的手续费
+func (wm *WalletManager) recordFee(ctx context.Context, signature string) {
+	if wm.Metrics == nil {
+		return
+	}
+	tx, err := wm.Client.GetTransaction(ctx, signature)
+	if err != nil || tx == nil || tx.Meta == nil {
+		wm.logger().DebugContext(ctx, "failed to get transaction fee", "signature", signature, "error", err)
+		return
+	}
+	wm.Metrics.observeFee(wm.Network, tx.Meta.Fee)
+}
@@ -0,0 +1,389 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+)
+
+const (
+	// DefaultDepositConfirmations 默认的确认深度。32 个 slot 后交易基本达到 finalized，不会因分叉回滚
+	DefaultDepositConfirmations = 32
+	// DefaultDepositInterval 默认的轮询间隔
+	DefaultDepositInterval = 10 * time.Second
+	// signaturesPageSize getSignaturesForAddress 每页的最大数量
+	signaturesPageSize = 1000
+)
+
+// Deposit 一笔到账
+type Deposit struct {
+	Address   string     // 被监控的钱包地址
+	Account   string     // 收款账户：钱包本身（SOL）或它的代币账户
+	Mint      string     // SOL 为 SOL_MINT_ADDR
+	Amount    uint64     // lamports 或代币最小单位
+	Sender    string     // 转出方钱包地址，无法确定时为空
+	Signature string     // 交易签名
+	Slot      uint64     // 交易所在的 slot
+	BlockTime *time.Time // 节点没有记录出块时间时为空
+}
+
+// DepositWatcherConfig 到账监控配置
+type DepositWatcherConfig struct {
+	Addresses []string // 监控的钱包地址，同时监控它们名下的所有代币账户（包括 Token-2022）
+	// CheckpointPath 保存处理进度的 JSON 文件，重启后从上次的位置继续；为空时只保存在内存中
+	CheckpointPath string
+	// Confirmations 交易所在 slot 落后最新 confirmed slot 至少这么多个 slot 后才上报，默认 DefaultDepositConfirmations
+	Confirmations uint64
+	Interval      time.Duration // 轮询间隔，默认 DefaultDepositInterval
+}
+
+// DepositWatcher 轮询监控钱包及其代币账户的到账，每笔到账只上报一次。
+// 第一次监控某个地址时从已达到确认深度的最新交易开始，不上报更早的交易；自己签名的交易不算 SOL 到账；之后新出现的代币账户从它的第一笔交易开始处理。
+type DepositWatcher struct {
+	wm  *WalletManager
+	cfg DepositWatcherConfig
+
+	mu         sync.Mutex
+	checkpoint depositCheckpoint
+}
+
+// depositCheckpoint 每个钱包地址下每个账户最后处理的交易签名，空字符串表示从头处理
+type depositCheckpoint struct {
+	Addresses map[string]map[string]string `json:"addresses"`
+}
+
+// NewDepositWatcher 创建到账监控，CheckpointPath 存在时读取其中的进度
+func (wm *WalletManager) NewDepositWatcher(cfg DepositWatcherConfig) (*DepositWatcher, error) {
+	if len(cfg.Addresses) == 0 {
+		return nil, errors.New("no address to watch")
+	}
+	for _, addr := range cfg.Addresses {
+		if _, err := ParseAddress(addr); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Confirmations == 0 {
+		cfg.Confirmations = DefaultDepositConfirmations
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultDepositInterval
+	}
+
+	w := &DepositWatcher{wm: wm, cfg: cfg, checkpoint: depositCheckpoint{Addresses: map[string]map[string]string{}}}
+	if cfg.CheckpointPath == "" {
+		return w, nil
+	}
+	data, err := os.ReadFile(cfg.CheckpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deposit checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &w.checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse deposit checkpoint %s: %w", cfg.CheckpointPath, err)
+	}
+	if w.checkpoint.Addresses == nil {
+		w.checkpoint.Addresses = map[string]map[string]string{}
+	}
+	return w, nil
+}
+
+// Run 每隔 Interval 调用一次 Poll，直到 ctx 取消或 onDeposit 返回错误。
+// 节点请求失败时记录日志并在下一轮重试，进度没有前进，不会漏报。
+func (w *DepositWatcher) Run(ctx context.Context, onDeposit func(Deposit) error) error {
+	var handlerErr error
+	handle := func(d Deposit) error {
+		handlerErr = onDeposit(d)
+		return handlerErr
+	}
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Poll(ctx, handle); err != nil {
+			if handlerErr != nil || ctx.Err() != nil {
+				return err
+			}
+			w.wm.logger().WarnContext(ctx, "deposit poll failed", "operation", "watch_deposits", "network", w.wm.Network, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll 检查一轮新到账，按交易先后顺序回调 onDeposit。
+// 每笔到账回调成功后立即保存进度；onDeposit 返回错误时停止，该笔到账下次会重新上报。
+// 进程恰好在回调成功和保存进度之间退出时会重复上报一次，onDeposit 应按 Signature + Account 去重。
+func (w *DepositWatcher) Poll(ctx context.Context, onDeposit func(Deposit) error) (err error) {
+	ctx, span := w.wm.startOperation(ctx, "PollDeposits")
+	defer func() { endSpan(span, err) }()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	slot, err := w.wm.Client.GetSlotWithConfig(ctx, client.GetSlotConfig{Commitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return fmt.Errorf("failed to get slot: %w", wrapRPCError(err, nil))
+	}
+	var safeSlot uint64
+	if slot > w.cfg.Confirmations {
+		safeSlot = slot - w.cfg.Confirmations
+	}
+
+	for _, address := range w.cfg.Addresses {
+		if err := w.pollAddress(ctx, address, safeSlot, onDeposit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pollAddress 处理一个钱包地址及其代币账户的新交易
+func (w *DepositWatcher) pollAddress(ctx context.Context, address string, safeSlot uint64, onDeposit func(Deposit) error) error {
+	accounts := []string{address}
+	tokenAccounts, err := w.wm.Client.GetTokenAccountsByOwnerByProgram(ctx, address, common.TokenProgramID.ToBase58())
+	if err != nil {
+		return fmt.Errorf("failed to list token accounts: %w", wrapRPCError(err, nil))
+	}
+	token2022Accounts, err := getToken2022Accounts(ctx, w.wm.Client, address)
+	if err != nil {
+		return err
+	}
+	for _, a := range append(tokenAccounts, token2022Accounts...) {
+		accounts = append(accounts, a.PublicKey.ToBase58())
+	}
+	sort.Strings(accounts[1:])
+
+	progress, known := w.checkpoint.Addresses[address]
+	if !known {
+		// 第一次监控该地址：记录每个账户已达到确认深度的最新交易，不上报历史
+		progress = map[string]string{}
+		for _, account := range accounts {
+			latest, err := w.latestSafeSignature(ctx, account, safeSlot)
+			if err != nil {
+				return err
+			}
+			progress[account] = latest
+		}
+		w.checkpoint.Addresses[address] = progress
+		return w.save()
+	}
+
+	for _, account := range accounts {
+		// 新出现的代币账户没有进度记录，从第一笔交易开始
+		signatures, err := w.newSignatures(ctx, account, progress[account], safeSlot)
+		if err != nil {
+			return err
+		}
+		for _, s := range signatures {
+			if s.Err == nil {
+				deposit, ok, err := w.deposit(ctx, address, account, s)
+				if err != nil {
+					return err
+				}
+				if ok {
+					if err := onDeposit(deposit); err != nil {
+						return err
+					}
+				}
+			}
+			progress[account] = s.Signature
+			if err := w.save(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// latestSafeSignature 返回 slot 不超过 safeSlot 的最新交易签名，没有时返回空字符串。
+// 确认深度不够的交易可能因分叉回滚，不能作为进度，它们在达到确认深度后照常上报。
+func (w *DepositWatcher) latestSafeSignature(ctx context.Context, account string, safeSlot uint64) (string, error) {
+	before := ""
+	for {
+		page, err := w.wm.Client.GetSignaturesForAddressWithConfig(ctx, account, client.GetSignaturesForAddressConfig{
+			Limit:      signaturesPageSize,
+			Before:     before,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get signatures: %w", wrapRPCError(err, nil))
+		}
+		for _, s := range page {
+			if s.Slot <= safeSlot {
+				return s.Signature, nil
+			}
+		}
+		if len(page) < signaturesPageSize {
+			return "", nil
+		}
+		before = page[len(page)-1].Signature
+	}
+}
+
+// newSignatures 返回 until 之后、slot 不超过 safeSlot 的交易，按时间正序排列
+func (w *DepositWatcher) newSignatures(ctx context.Context, account string, until string, safeSlot uint64) ([]rpc.SignatureWithStatus, error) {
+	var all []rpc.SignatureWithStatus
+	before := ""
+	for {
+		page, err := w.wm.Client.GetSignaturesForAddressWithConfig(ctx, account, client.GetSignaturesForAddressConfig{
+			Limit:      signaturesPageSize,
+			Before:     before,
+			Until:      until,
+			Commitment: rpc.CommitmentConfirmed,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get signatures: %w", wrapRPCError(err, nil))
+		}
+		all = append(all, page...)
+		if len(page) < signaturesPageSize {
+			break
+		}
+		before = page[len(page)-1].Signature
+	}
+
+	// 节点按时间倒序返回；确认深度不够的交易都比已处理的新，留到下一轮
+	var result []rpc.SignatureWithStatus
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].Slot > safeSlot {
+			break
+		}
+		result = append(result, all[i])
+	}
+	return result, nil
+}
+
+// deposit 读取交易并计算 account 的到账，余额没有增加时返回 false
+func (w *DepositWatcher) deposit(ctx context.Context, address string, account string, s rpc.SignatureWithStatus) (Deposit, bool, error) {
+	tx, err := w.wm.Client.GetTransactionWithConfig(ctx, s.Signature, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return Deposit{}, false, fmt.Errorf("failed to get transaction %s: %w", s.Signature, wrapRPCError(err, nil))
+	}
+	if tx == nil || tx.Meta == nil {
+		return Deposit{}, false, fmt.Errorf("transaction %s is not available yet", s.Signature)
+	}
+
+	d := Deposit{Address: address, Account: account, Signature: s.Signature, Slot: s.Slot}
+	if tx.BlockTime != nil {
+		t := time.Unix(*tx.BlockTime, 0)
+		d.BlockTime = &t
+	}
+	if account == address {
+		amount, sender := solDeposit(tx, account)
+		d.Mint, d.Amount, d.Sender = SOL_MINT_ADDR, amount, sender
+	} else {
+		d.Mint, d.Amount, d.Sender = tokenDeposit(tx, account)
+	}
+	return d, d.Amount > 0, nil
+}
+
+// solDeposit 账户的 lamports 增加量，转出方取余额减少最多的账户。
+// 账户自己签名的交易（关闭代币账户、兑换等）不算到账。
+func solDeposit(tx *client.Transaction, account string) (uint64, string) {
+	message := tx.Transaction.Message
+	signers := message.Accounts[:min(int(message.Header.NumRequireSignatures), len(message.Accounts))]
+	for _, signer := range signers {
+		if signer.ToBase58() == account {
+			return 0, ""
+		}
+	}
+	var amount uint64
+	var sender string
+	var largest int64
+	for i, key := range tx.AccountKeys {
+		if i >= len(tx.Meta.PreBalances) || i >= len(tx.Meta.PostBalances) {
+			break
+		}
+		diff := tx.Meta.PostBalances[i] - tx.Meta.PreBalances[i]
+		switch {
+		case key.ToBase58() == account:
+			if diff > 0 {
+				amount = uint64(diff)
+			}
+		case -diff > largest:
+			largest, sender = -diff, key.ToBase58()
+		}
+	}
+	return amount, sender
+}
+
+// tokenDeposit 代币账户的余额增加量，转出方取同一 mint 下余额减少最多的账户的所有者，
+// 减少量相同时取账户下标最小的，同一笔交易每次得到相同的结果
+func tokenDeposit(tx *client.Transaction, account string) (string, uint64, string) {
+	balances := func(list []rpc.TransactionMetaTokenBalance) map[uint64]rpc.TransactionMetaTokenBalance {
+		m := make(map[uint64]rpc.TransactionMetaTokenBalance, len(list))
+		for _, b := range list {
+			m[b.AccountIndex] = b
+		}
+		return m
+	}
+	amountOf := func(b rpc.TransactionMetaTokenBalance) uint64 {
+		amount, _ := strconv.ParseUint(b.UITokenAmount.Amount, 10, 64)
+		return amount
+	}
+	pre, post := balances(tx.Meta.PreTokenBalances), balances(tx.Meta.PostTokenBalances)
+
+	var mint string
+	var amount uint64
+	for i, key := range tx.AccountKeys {
+		if key.ToBase58() != account {
+			continue
+		}
+		after, ok := post[uint64(i)]
+		if !ok {
+			return "", 0, ""
+		}
+		// 账户在这笔交易中创建时没有交易前的余额记录
+		before := amountOf(pre[uint64(i)])
+		mint = after.Mint
+		if amountOf(after) > before {
+			amount = amountOf(after) - before
+		}
+	}
+	if amount == 0 {
+		return mint, 0, ""
+	}
+	indexes := make([]uint64, 0, len(pre))
+	for index := range pre {
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+	var sender string
+	var largest uint64
+	for _, index := range indexes {
+		before, after := amountOf(pre[index]), amountOf(post[index])
+		if pre[index].Mint == mint && after < before && before-after > largest {
+			largest, sender = before-after, pre[index].Owner
+		}
+	}
+	return mint, amount, sender
+}
+
+// save 保存进度，调用方需持有锁
+func (w *DepositWatcher) save() error {
+	if w.cfg.CheckpointPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(w.checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(w.cfg.CheckpointPath, data, 0o644, true); err != nil {
+		return fmt.Errorf("failed to write deposit checkpoint: %w", err)
+	}
+	return nil
+}
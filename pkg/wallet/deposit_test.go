@@ -0,0 +1,321 @@
+package wallet
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// depositLedger 模拟链上记录：每个账户的交易签名（时间正序）和每笔交易的返回内容
+type depositLedger struct {
+	mu         sync.Mutex
+	slot       uint64
+	signatures map[string][]map[string]any
+	txs        map[string]any
+}
+
+// add 记录一笔交易，accounts 为涉及的账户
+func (l *depositLedger) add(signature string, slot uint64, failed bool, tx any, accounts ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := map[string]any{"signature": signature, "slot": slot, "err": nil}
+	if failed {
+		entry["err"] = map[string]any{"InstructionError": []any{0, "Custom"}}
+	}
+	for _, account := range accounts {
+		l.signatures[account] = append(l.signatures[account], entry)
+	}
+	l.txs[signature] = tx
+}
+
+func newDepositTestWallet(t *testing.T) (*WalletManager, *rpcStub, *depositLedger) {
+	t.Helper()
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	ledger := &depositLedger{slot: 100, signatures: map[string][]map[string]any{}, txs: map[string]any{}}
+	stub.on("getSlot", func([]json.RawMessage) any {
+		ledger.mu.Lock()
+		defer ledger.mu.Unlock()
+		return ledger.slot
+	})
+	stub.on("getTokenAccountsByOwner", func([]json.RawMessage) any { return withContext([]any{}) })
+	stub.on("getSignaturesForAddress", func(params []json.RawMessage) any {
+		var address string
+		_ = json.Unmarshal(params[0], &address)
+		var cfg struct {
+			Limit  int    `json:"limit"`
+			Before string `json:"before"`
+			Until  string `json:"until"`
+		}
+		_ = json.Unmarshal(params[1], &cfg)
+
+		ledger.mu.Lock()
+		defer ledger.mu.Unlock()
+		result := []any{}
+		all := ledger.signatures[address]
+		started := cfg.Before == ""
+		for i := len(all) - 1; i >= 0 && (cfg.Limit == 0 || len(result) < cfg.Limit); i-- {
+			signature := all[i]["signature"]
+			if signature == cfg.Until {
+				break
+			}
+			if started {
+				result = append(result, all[i])
+			}
+			started = started || signature == cfg.Before
+		}
+		return result
+	})
+	stub.on("getTransaction", func(params []json.RawMessage) any {
+		var signature string
+		_ = json.Unmarshal(params[0], &signature)
+		ledger.mu.Lock()
+		defer ledger.mu.Unlock()
+		return ledger.txs[signature]
+	})
+	return wm, stub, ledger
+}
+
+// depositTransaction 构造 getTransaction 返回值，balances 按账户给出交易前后的余额
+func depositTransaction(t *testing.T, slot uint64, payer types.Account, ins types.Instruction, balances map[common.PublicKey][2]int64, tokenBalances []any) any {
+	t.Helper()
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        payer.PublicKey,
+			RecentBlockhash: "GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi",
+			Instructions:    []types.Instruction{ins},
+		}),
+		Signers: []types.Account{payer},
+	})
+	require.NoError(t, err)
+	raw, err := tx.Serialize()
+	require.NoError(t, err)
+
+	pre := make([]int64, len(tx.Message.Accounts))
+	post := make([]int64, len(tx.Message.Accounts))
+	for i, key := range tx.Message.Accounts {
+		pre[i], post[i] = balances[key][0], balances[key][1]
+	}
+	var preToken, postToken []any
+	for _, b := range tokenBalances {
+		b := b.(map[string]any)
+		index := -1
+		for i, key := range tx.Message.Accounts {
+			if key.ToBase58() == b["account"] {
+				index = i
+			}
+		}
+		entry := func(amount uint64) any {
+			return map[string]any{
+				"accountIndex": index,
+				"mint":         b["mint"],
+				"owner":        b["owner"],
+				"uiTokenAmount": map[string]any{
+					"amount":         strconv.FormatUint(amount, 10),
+					"decimals":       6,
+					"uiAmountString": "",
+				},
+			}
+		}
+		preToken = append(preToken, entry(b["pre"].(uint64)))
+		postToken = append(postToken, entry(b["post"].(uint64)))
+	}
+	return map[string]any{
+		"slot":      slot,
+		"blockTime": 1_700_000_000,
+		"meta": map[string]any{
+			"fee": 5000, "err": nil,
+			"preBalances": pre, "postBalances": post,
+			"preTokenBalances": preToken, "postTokenBalances": postToken,
+		},
+		"transaction": []string{base64.StdEncoding.EncodeToString(raw), "base64"},
+	}
+}
+
+func TestDepositWatcherSOL(t *testing.T) {
+	wm, stub, ledger := newDepositTestWallet(t)
+	owner := wm.PublicKey()
+	sender := types.NewAccount()
+	checkpoint := filepath.Join(t.TempDir(), "deposits.json")
+	transfer := func(lamports uint64) any {
+		return depositTransaction(t, 0, sender, system.Transfer(system.TransferParam{
+			From: sender.PublicKey, To: owner, Amount: lamports,
+		}), map[common.PublicKey][2]int64{
+			sender.PublicKey: {10_000_000_000, 10_000_000_000 - int64(lamports) - 5000},
+			owner:            {0, int64(lamports)},
+		}, nil)
+	}
+
+	// 开始监控前的交易不上报
+	ledger.add("old", 10, false, transfer(1), owner.ToBase58())
+	cfg := DepositWatcherConfig{Addresses: []string{owner.ToBase58()}, CheckpointPath: checkpoint, Confirmations: 5}
+	w, err := wm.NewDepositWatcher(cfg)
+	require.NoError(t, err)
+	var deposits []Deposit
+	record := func(d Deposit) error {
+		deposits = append(deposits, d)
+		return nil
+	}
+	require.NoError(t, w.Poll(context.Background(), record))
+	assert.Empty(t, deposits)
+
+	ledger.add("failed", 90, true, nil, owner.ToBase58())
+	ledger.add("first", 94, false, transfer(1_000), owner.ToBase58())
+	ledger.add("recent", 97, false, transfer(2_000), owner.ToBase58())
+	require.NoError(t, w.Poll(context.Background(), record))
+	require.Len(t, deposits, 1)
+	assert.Equal(t, Deposit{
+		Address:   owner.ToBase58(),
+		Account:   owner.ToBase58(),
+		Mint:      SOL_MINT_ADDR,
+		Amount:    1_000,
+		Sender:    sender.PublicKey.ToBase58(),
+		Signature: "first",
+		Slot:      94,
+		BlockTime: deposits[0].BlockTime,
+	}, deposits[0])
+	require.NotNil(t, deposits[0].BlockTime)
+	assert.Equal(t, 1, stub.callCount("getTransaction"))
+
+	// 确认深度足够后上报；重新打开的监控从保存的进度继续
+	ledger.slot = 102
+	w, err = wm.NewDepositWatcher(cfg)
+	require.NoError(t, err)
+	require.NoError(t, w.Poll(context.Background(), record))
+	require.NoError(t, w.Poll(context.Background(), record))
+	require.Len(t, deposits, 2)
+	assert.Equal(t, "recent", deposits[1].Signature)
+	assert.Equal(t, uint64(2_000), deposits[1].Amount)
+}
+
+func TestDepositWatcherSkipsUnconfirmedAndOwnTransactions(t *testing.T) {
+	wm, _, ledger := newDepositTestWallet(t)
+	owner := wm.PublicKey()
+	sender := types.NewAccount()
+	transfer := depositTransaction(t, 0, sender, system.Transfer(system.TransferParam{
+		From: sender.PublicKey, To: owner, Amount: 1_000,
+	}), map[common.PublicKey][2]int64{
+		sender.PublicKey: {10_000_000_000, 10_000_000_000 - 1_000 - 5000},
+		owner:            {0, 1_000},
+	}, nil)
+	// 钱包自己关闭代币账户，余额增加但不是到账
+	tokenAccount := types.NewAccount().PublicKey
+	closeAccount := depositTransaction(t, 0, wm.Account, token.CloseAccount(token.CloseAccountParam{
+		Account: tokenAccount, Auth: owner, To: owner,
+	}), map[common.PublicKey][2]int64{
+		owner:        {1_000_000, 1_000_000 + 2_039_280 - 5000},
+		tokenAccount: {2_039_280, 0},
+	}, nil)
+
+	// 第一次监控时确认深度不够的交易不作为进度，达到确认深度后照常上报
+	ledger.add("old", 10, false, transfer, owner.ToBase58())
+	ledger.add("pending", 98, false, transfer, owner.ToBase58())
+	w, err := wm.NewDepositWatcher(DepositWatcherConfig{Addresses: []string{owner.ToBase58()}, Confirmations: 5})
+	require.NoError(t, err)
+	var deposits []Deposit
+	record := func(d Deposit) error {
+		deposits = append(deposits, d)
+		return nil
+	}
+	require.NoError(t, w.Poll(context.Background(), record))
+	assert.Empty(t, deposits)
+
+	ledger.add("close", 99, false, closeAccount, owner.ToBase58())
+	ledger.slot = 110
+	require.NoError(t, w.Poll(context.Background(), record))
+	require.Len(t, deposits, 1)
+	assert.Equal(t, "pending", deposits[0].Signature)
+	assert.Equal(t, "close", w.checkpoint.Addresses[owner.ToBase58()][owner.ToBase58()])
+}
+
+func TestDepositWatcherToken(t *testing.T) {
+	// Token-2022 账户同样监控
+	for name, program := range map[string]common.PublicKey{"token": common.TokenProgramID, "token-2022": common.Token2022ProgramID} {
+		t.Run(name, func(t *testing.T) {
+			wm, stub, ledger := newDepositTestWallet(t)
+			owner := wm.PublicKey()
+			sender := types.NewAccount()
+			mint := types.NewAccount().PublicKey
+			from := types.NewAccount().PublicKey
+			ata, _, err := common.FindAssociatedTokenAddress(owner, mint)
+			require.NoError(t, err)
+
+			w, err := wm.NewDepositWatcher(DepositWatcherConfig{Addresses: []string{owner.ToBase58()}, Confirmations: 1})
+			require.NoError(t, err)
+			require.NoError(t, w.Poll(context.Background(), func(Deposit) error { return nil }))
+
+			// 监控开始后才创建的代币账户，从第一笔交易开始处理
+			stub.on("getTokenAccountsByOwner", tokenAccountsByProgram(map[common.PublicKey][]any{program: {map[string]any{
+				"pubkey":  ata.ToBase58(),
+				"account": accountInfo(program.ToBase58(), testRentExempt(token.TokenAccountSize), tokenAccountData(mint, owner, 250, 1, nil)),
+			}}}))
+			tx := depositTransaction(t, 50, sender, token.TransferChecked(token.TransferCheckedParam{
+				From: from, To: ata, Mint: mint, Auth: sender.PublicKey, Amount: 250, Decimals: 6,
+			}), nil, []any{
+				map[string]any{"account": from.ToBase58(), "mint": mint.ToBase58(), "owner": sender.PublicKey.ToBase58(), "pre": uint64(1_000), "post": uint64(750)},
+				map[string]any{"account": ata.ToBase58(), "mint": mint.ToBase58(), "owner": owner.ToBase58(), "pre": uint64(0), "post": uint64(250)},
+			})
+			ledger.add("token", 50, false, tx, ata.ToBase58())
+
+			errHandler := errors.New("handler failed")
+			err = w.Poll(context.Background(), func(Deposit) error { return errHandler })
+			assert.ErrorIs(t, err, errHandler)
+
+			// 回调失败的到账下一轮重新上报
+			var deposits []Deposit
+			require.NoError(t, w.Poll(context.Background(), func(d Deposit) error {
+				deposits = append(deposits, d)
+				return nil
+			}))
+			require.Len(t, deposits, 1)
+			assert.Equal(t, ata.ToBase58(), deposits[0].Account)
+			assert.Equal(t, mint.ToBase58(), deposits[0].Mint)
+			assert.Equal(t, uint64(250), deposits[0].Amount)
+			assert.Equal(t, sender.PublicKey.ToBase58(), deposits[0].Sender)
+			assert.Equal(t, uint64(50), deposits[0].Slot)
+		})
+	}
+}
+
+func TestTokenDepositSender(t *testing.T) {
+	account := types.NewAccount().PublicKey
+	mint := types.NewAccount().PublicKey.ToBase58()
+	owners := []string{"", "first", "second", "third"}
+	keys := []common.PublicKey{account, types.NewAccount().PublicKey, types.NewAccount().PublicKey, types.NewAccount().PublicKey}
+	balance := func(index uint64, amount string) rpc.TransactionMetaTokenBalance {
+		return rpc.TransactionMetaTokenBalance{AccountIndex: index, Mint: mint, Owner: owners[index],
+			UITokenAmount: rpc.TokenAccountBalance{Amount: amount}}
+	}
+	// 两个账户减少量相同且最多，取下标较小的；余额列表的顺序不影响结果
+	tx := &client.Transaction{AccountKeys: keys, Meta: &client.TransactionMeta{
+		PreTokenBalances:  []rpc.TransactionMetaTokenBalance{balance(3, "100"), balance(2, "300"), balance(1, "300")},
+		PostTokenBalances: []rpc.TransactionMetaTokenBalance{balance(3, "0"), balance(2, "0"), balance(1, "0"), balance(0, "700")},
+	}}
+	for i := 0; i < 10; i++ {
+		gotMint, amount, sender := tokenDeposit(tx, account.ToBase58())
+		assert.Equal(t, mint, gotMint)
+		assert.Equal(t, uint64(700), amount)
+		assert.Equal(t, "first", sender)
+	}
+}
+
+func TestNewDepositWatcherInvalid(t *testing.T) {
+	wm, _, _ := newDepositTestWallet(t)
+	_, err := wm.NewDepositWatcher(DepositWatcherConfig{})
+	assert.Error(t, err)
+	_, err = wm.NewDepositWatcher(DepositWatcherConfig{Addresses: []string{"bad"}})
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+}
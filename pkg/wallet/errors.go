@@ -0,0 +1,249 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// 可用 errors.Is 判断的错误原因
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrAccountNotFound   = errors.New("account not found")
+	ErrBlockhashExpired  = errors.New("blockhash expired")
+	ErrSlippageExceeded  = errors.New("slippage tolerance exceeded")
+)
+
+// JupiterV6ProgramID Jupiter v6 聚合器程序
+var JupiterV6ProgramID = common.PublicKeyFromString("JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4")
+
+// rpcCodeInvalidParams JSON-RPC 参数错误，查询不存在的代币账户时返回
+const rpcCodeInvalidParams = -32602
+
+// RPCError RPC 节点返回的错误。交易预检失败时 Logs 为模拟执行日志，
+// 可以解析出原因的（例如 *ProgramError、ErrBlockhashExpired）可通过 errors.Is/As 取得。
+type RPCError struct {
+	Code    int
+	Message string
+	Data    any
+	Logs    []string
+
+	cause error
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Unwrap 返回解析出的错误原因
+func (e *RPCError) Unwrap() error {
+	return e.cause
+}
+
+// ProgramError 交易中某条指令执行失败。
+// 自定义错误码会按 System、Token、Associated Token Account 和 Jupiter 程序的定义解码为 Name。
+type ProgramError struct {
+	InstructionIndex int
+	ProgramID        string // 无法确定时为空
+	Custom           bool   // true 表示程序自定义错误，Code 有效
+	Code             uint32
+	Name             string // 解码后的错误名，例如 InsufficientFunds
+
+	cause error
+}
+
+func (e *ProgramError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "instruction %d", e.InstructionIndex)
+	if e.ProgramID != "" {
+		fmt.Fprintf(&b, " (program %s)", e.ProgramID)
+	}
+	b.WriteString(" failed: ")
+	if e.Custom {
+		fmt.Fprintf(&b, "custom program error 0x%x", e.Code)
+		if e.Name != "" {
+			fmt.Fprintf(&b, " (%s)", e.Name)
+		}
+	} else {
+		b.WriteString(e.Name)
+	}
+	return b.String()
+}
+
+// Unwrap 返回对应的通用错误（例如 ErrInsufficientFunds），没有时为 nil
+func (e *ProgramError) Unwrap() error {
+	return e.cause
+}
+
+// programErrorCode 程序自定义错误码的名称和对应的通用错误
+type programErrorCode struct {
+	name  string
+	cause error
+}
+
+// programErrors 各程序的自定义错误码
+var programErrors = map[common.PublicKey]map[uint32]programErrorCode{
+	common.SystemProgramID: {
+		0: {"AccountAlreadyInUse", nil},
+		1: {"ResultWithNegativeLamports", ErrInsufficientFunds},
+		2: {"InvalidProgramId", nil},
+		3: {"InvalidAccountDataLength", nil},
+		4: {"MaxSeedLengthExceeded", nil},
+		5: {"AddressWithSeedMismatch", nil},
+		6: {"NonceNoRecentBlockhashes", nil},
+		7: {"NonceBlockhashNotExpired", nil},
+		8: {"NonceUnexpectedBlockhashValue", ErrBlockhashExpired},
+	},
+	common.TokenProgramID: {
+		0:  {"NotRentExempt", ErrInsufficientFunds},
+		1:  {"InsufficientFunds", ErrInsufficientFunds},
+		2:  {"InvalidMint", nil},
+		3:  {"MintMismatch", nil},
+		4:  {"OwnerMismatch", nil},
+		5:  {"FixedSupply", nil},
+		6:  {"AlreadyInUse", nil},
+		7:  {"InvalidNumberOfProvidedSigners", nil},
+		8:  {"InvalidNumberOfRequiredSigners", nil},
+		9:  {"UninitializedState", ErrAccountNotFound},
+		10: {"NativeNotSupported", nil},
+		11: {"NonNativeHasBalance", nil},
+		12: {"InvalidInstruction", nil},
+		13: {"InvalidState", nil},
+		14: {"Overflow", nil},
+		15: {"AuthorityTypeNotSupported", nil},
+		16: {"MintCannotFreeze", nil},
+		17: {"AccountFrozen", nil},
+		18: {"MintDecimalsMismatch", nil},
+		19: {"NonNativeNotSupported", nil},
+	},
+	common.SPLAssociatedTokenAccountProgramID: {
+		0: {"InvalidOwner", nil},
+	},
+	JupiterV6ProgramID: {
+		6001: {"SlippageToleranceExceeded", ErrSlippageExceeded},
+	},
+}
+
+// builtinInstructionErrors 运行时内置的指令错误
+var builtinInstructionErrors = map[string]error{
+	"InsufficientFunds":        ErrInsufficientFunds,
+	"UninitializedAccount":     ErrAccountNotFound,
+	"AccountNotRentExempt":     ErrInsufficientFunds,
+	"InsufficientFundsForRent": ErrInsufficientFunds,
+}
+
+// transactionErrors 交易级别的错误
+var transactionErrors = map[string]error{
+	"AccountNotFound":          ErrAccountNotFound,
+	"ProgramAccountNotFound":   ErrAccountNotFound,
+	"InsufficientFundsForFee":  ErrInsufficientFunds,
+	"InsufficientFundsForRent": ErrInsufficientFunds,
+	"BlockhashNotFound":        ErrBlockhashExpired,
+}
+
+// wrapRPCError 把 SDK 返回的 JSON-RPC 错误转换为 *RPCError，其他错误原样返回。
+// tx 非空时用来确定失败指令所属的程序。
+func wrapRPCError(err error, tx *types.Transaction) error {
+	var rpcErr *rpc.JsonRpcError
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+	e := &RPCError{Code: rpcErr.Code, Message: rpcErr.Message, Data: rpcErr.Data}
+
+	data, _ := rpcErr.Data.(map[string]any)
+	if logs, ok := data["logs"].([]any); ok {
+		for _, l := range logs {
+			if s, ok := l.(string); ok {
+				e.Logs = append(e.Logs, s)
+			}
+		}
+	}
+	if txErr, ok := data["err"]; ok && txErr != nil {
+		e.cause = parseTransactionError(txErr, tx)
+	}
+	if e.cause == nil {
+		switch {
+		case e.Code == rpcCodeInvalidParams && strings.Contains(e.Message, "could not find account"):
+			e.cause = ErrAccountNotFound
+		case strings.Contains(e.Message, "Blockhash not found"):
+			e.cause = ErrBlockhashExpired
+		}
+	}
+	return e
+}
+
+// parseTransactionError 解析 RPC 返回的 TransactionError，
+// 形如 "BlockhashNotFound"、{"InstructionError":[0,{"Custom":1}]} 或 {"InsufficientFundsForRent":{"account_index":0}}
+func parseTransactionError(txErr any, tx *types.Transaction) error {
+	var name string
+	switch v := txErr.(type) {
+	case string:
+		name = v
+	case map[string]any:
+		if ie, ok := v["InstructionError"].([]any); ok && len(ie) == 2 {
+			return parseInstructionError(ie, tx)
+		}
+		for k := range v {
+			name = k
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	if cause, ok := transactionErrors[name]; ok {
+		return fmt.Errorf("%w: %s", cause, name)
+	}
+	return errors.New(name)
+}
+
+// parseInstructionError 解析 [指令序号, 错误] 形式的指令错误
+func parseInstructionError(ie []any, tx *types.Transaction) error {
+	index, _ := ie[0].(float64)
+	e := &ProgramError{InstructionIndex: int(index)}
+	if tx != nil && e.InstructionIndex < len(tx.Message.Instructions) {
+		programIndex := tx.Message.Instructions[e.InstructionIndex].ProgramIDIndex
+		if programIndex < len(tx.Message.Accounts) {
+			e.ProgramID = tx.Message.Accounts[programIndex].ToBase58()
+		}
+	}
+
+	switch v := ie[1].(type) {
+	case string:
+		e.Name = v
+		e.cause = builtinInstructionErrors[v]
+	case map[string]any:
+		if code, ok := v["Custom"].(float64); ok {
+			e.Custom = true
+			e.Code = uint32(code)
+			if known, ok := programErrors[common.PublicKeyFromString(e.ProgramID)][e.Code]; ok && e.ProgramID != "" {
+				e.Name = known.name
+				e.cause = known.cause
+			}
+			break
+		}
+		for name := range v {
+			e.Name = name
+			e.cause = builtinInstructionErrors[name]
+		}
+	}
+	return e
+}
+
+// getAccountInfo 读取账户信息，账户不存在时返回 ErrAccountNotFound
+func getAccountInfo(ctx context.Context, c *client.Client, address string) (client.AccountInfo, error) {
+	info, err := c.GetAccountInfo(ctx, address)
+	if err != nil {
+		return client.AccountInfo{}, wrapRPCError(err, nil)
+	}
+	// SDK 对不存在的账户返回零值
+	if info.Lamports == 0 && info.Owner == (common.PublicKey{}) && len(info.Data) == 0 {
+		return client.AccountInfo{}, fmt.Errorf("%w: %s", ErrAccountNotFound, address)
+	}
+	return info, nil
+}
@@ -0,0 +1,150 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBlockhash = "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N"
+
+// newErrorTestWallet 返回余额充足、sendTransaction 按 sendErr 失败的钱包
+func newErrorTestWallet(t *testing.T, sendErr rpcStubError) (*WalletManager, *rpcStub) {
+	t.Helper()
+	stub, c := newRPCStub(t)
+	stub.on("getBalance", func([]json.RawMessage) any { return withContext(uint64(1_000_000_000)) })
+	stub.on("getLatestBlockhash", func([]json.RawMessage) any {
+		return withContext(map[string]any{"blockhash": testBlockhash, "lastValidBlockHeight": 100})
+	})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return sendErr })
+	return &WalletManager{Client: c, Network: "devnet", Account: types.NewAccount(), Logger: NewLogger(io.Discard, nil)}, stub
+}
+
+func TestSendTransactionDecodesTokenProgramError(t *testing.T) {
+	wm, _ := newErrorTestWallet(t, rpcStubError{
+		Code:    -32002,
+		Message: "Transaction simulation failed: Error processing Instruction 0: custom program error: 0x1",
+		Data: map[string]any{
+			"err":  map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}},
+			"logs": []string{"Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [1]", "Program log: Error: insufficient funds"},
+		},
+	})
+	authority := NewAccountSigner(types.NewAccount())
+	_, err := wm.TransferTokensChecked(context.Background(), types.NewAccount().PublicKey.ToBase58(), authority,
+		types.NewAccount().PublicKey.ToBase58(), types.NewAccount().PublicKey.ToBase58(), 10, 8)
+	require.Error(t, err)
+
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	var rpcErr *RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, -32002, rpcErr.Code)
+	assert.Len(t, rpcErr.Logs, 2)
+
+	var programErr *ProgramError
+	require.True(t, errors.As(err, &programErr))
+	assert.Equal(t, 0, programErr.InstructionIndex)
+	assert.Equal(t, common.TokenProgramID.ToBase58(), programErr.ProgramID)
+	assert.True(t, programErr.Custom)
+	assert.Equal(t, uint32(1), programErr.Code)
+	assert.Equal(t, "InsufficientFunds", programErr.Name)
+}
+
+func TestTransferTokensCheckedHonorsContext(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := wm.TransferTokensChecked(ctx, types.NewAccount().PublicKey.ToBase58(), wm.signer(),
+		types.NewAccount().PublicKey.ToBase58(), types.NewAccount().PublicKey.ToBase58(), 10, 8)
+	// SDK 把 ctx 错误转成字符串，这里只能按内容判断
+	assert.ErrorContains(t, err, "context canceled")
+	assert.Zero(t, stub.callCount("sendTransaction"))
+}
+
+func TestTransferTokensCheckedRejectsInvalidAddresses(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	valid := types.NewAccount().PublicKey.ToBase58()
+	for _, addrs := range [][3]string{{"bad", valid, valid}, {valid, "bad", valid}, {valid, valid, "bad"}} {
+		_, err := wm.TransferTokensChecked(context.Background(), addrs[0], wm.signer(), addrs[1], addrs[2], 10, 8)
+		assert.ErrorIs(t, err, ErrInvalidAddress, addrs)
+	}
+	assert.Zero(t, stub.callCount("getLatestBlockhash"))
+}
+
+func TestSendTransactionBlockhashExpired(t *testing.T) {
+	wm, _ := newErrorTestWallet(t, rpcStubError{
+		Code:    -32002,
+		Message: "Transaction simulation failed: Blockhash not found",
+		Data:    map[string]any{"err": "BlockhashNotFound", "logs": []string{}},
+	})
+	_, err := wm.TransferSOL(context.Background(), types.NewAccount().PublicKey.ToBase58(), 1000)
+	assert.ErrorIs(t, err, ErrBlockhashExpired)
+	assert.False(t, errors.Is(err, ErrInsufficientFunds))
+}
+
+func TestTransferSOLInsufficientFunds(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	_, err := wm.TransferSOL(context.Background(), types.NewAccount().PublicKey.ToBase58(), 2_000_000_000)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Equal(t, 0, stub.callCount("sendTransaction"))
+}
+
+func TestAccountNotFound(t *testing.T) {
+	stub, c := newRPCStub(t)
+	stub.on("getTokenAccountBalance", func([]json.RawMessage) any {
+		return rpcStubError{Code: -32602, Message: "Invalid param: could not find account"}
+	})
+	stub.on("getAccountInfo", func([]json.RawMessage) any { return withContext(nil) })
+	wm := &WalletManager{Client: c, Network: "devnet", Account: types.NewAccount()}
+
+	_, err := wm.CheckAmount(context.Background(), types.NewAccount().PublicKey.ToBase58())
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	var rpcErr *RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, -32602, rpcErr.Code)
+
+	_, err = wm.GetNonce(context.Background(), types.NewAccount().PublicKey.ToBase58())
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestParseInstructionError(t *testing.T) {
+	tests := []struct {
+		name      string
+		programID common.PublicKey
+		err       any
+		wantName  string
+		wantCause error
+	}{
+		{"system negative lamports", common.SystemProgramID, map[string]any{"Custom": float64(1)}, "ResultWithNegativeLamports", ErrInsufficientFunds},
+		{"ata invalid owner", common.SPLAssociatedTokenAccountProgramID, map[string]any{"Custom": float64(0)}, "InvalidOwner", nil},
+		{"jupiter slippage", JupiterV6ProgramID, map[string]any{"Custom": float64(6001)}, "SlippageToleranceExceeded", ErrSlippageExceeded},
+		{"unknown program", common.MemoProgramID, map[string]any{"Custom": float64(1)}, "", nil},
+		{"builtin", common.TokenProgramID, "InsufficientFunds", "InsufficientFunds", ErrInsufficientFunds},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &types.Transaction{Message: types.NewMessage(types.NewMessageParam{
+				FeePayer:        types.NewAccount().PublicKey,
+				RecentBlockhash: testBlockhash,
+				Instructions:    []types.Instruction{{ProgramID: tt.programID}},
+			})}
+			err := parseInstructionError([]any{float64(0), tt.err}, tx)
+
+			var programErr *ProgramError
+			require.True(t, errors.As(err, &programErr))
+			assert.Equal(t, tt.programID.ToBase58(), programErr.ProgramID)
+			assert.Equal(t, tt.wantName, programErr.Name)
+			if tt.wantCause != nil {
+				assert.ErrorIs(t, err, tt.wantCause)
+			} else {
+				assert.Nil(t, errors.Unwrap(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package wallet
+
+// lockFile 在不支持 flock 的平台上不加锁，只依靠调用方的进程内互斥
+func lockFile(string) (func(), error) {
+	return func() {}, nil
+}
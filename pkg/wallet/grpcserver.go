@@ -0,0 +1,454 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	"github.com/paxzhu/go-solana/pkg/walletpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// WatchBalance 的查询间隔
+const (
+	defaultBalancePollInterval = 2 * time.Second
+	minBalancePollInterval     = 500 * time.Millisecond
+)
+
+// GRPCServerConfig gRPC 服务配置
+type GRPCServerConfig struct {
+	// APIKeys 允许访问的 API key，客户端在 metadata 中以 authorization: Bearer <key> 或 x-api-key 发送。
+	// 为空时拒绝所有请求。
+	APIKeys []string
+	// Registry CreateAccount 登记新钱包、LoadAccount 按标签查找钱包使用的登记表，为空时不支持标签
+	Registry *Registry
+	// KeyDir CreateAccount 保存私钥文件的目录，为空时使用 DefaultKeyDir
+	KeyDir string
+	// Passphrase LoadAccount 加载加密 keystore 时读取密码
+	Passphrase PassphraseFunc
+}
+
+// grpcServer 实现 walletpb.WalletServiceServer。
+// 除启动时的默认钱包外，通过 CreateAccount / LoadAccount 加载的钱包按公钥保存，请求的 wallet 字段选择使用哪个钱包。
+type grpcServer struct {
+	walletpb.UnimplementedWalletServiceServer
+
+	base    *WalletManager
+	cfg     GRPCServerConfig
+	apiKeys apiKeySet
+
+	mu      sync.RWMutex
+	wallets map[string]*WalletManager
+	labels  map[string]string // 公钥 -> 标签
+}
+
+// NewGRPCServer 创建注册了 WalletService 的 gRPC 服务，wm 为默认钱包
+func NewGRPCServer(wm *WalletManager, cfg GRPCServerConfig, opts ...grpc.ServerOption) *grpc.Server {
+	s := &grpcServer{
+		base:    wm,
+		cfg:     cfg,
+		apiKeys: newAPIKeySet(cfg.APIKeys),
+		wallets: map[string]*WalletManager{},
+		labels:  map[string]string{},
+	}
+	if s.cfg.KeyDir == "" {
+		s.cfg.KeyDir = DefaultKeyDir
+	}
+
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := s.authorize(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := s.authorize(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	server := grpc.NewServer(opts...)
+	walletpb.RegisterWalletServiceServer(server, s)
+	return server
+}
+
+// authorize 校验 metadata 中的 API key
+func (s *grpcServer) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var key string
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		key = values[0]
+	}
+	if values := md.Get("authorization"); len(values) > 0 {
+		if bearer, ok := strings.CutPrefix(values[0], "Bearer "); ok {
+			key = bearer
+		}
+	}
+	if !s.apiKeys.valid(key) {
+		return status.Error(codes.Unauthenticated, "missing or invalid API key")
+	}
+	return nil
+}
+
+// wallet 按公钥选择已加载的钱包，为空时返回默认钱包
+func (s *grpcServer) wallet(publicKey string) (*WalletManager, error) {
+	if publicKey == "" || publicKey == s.base.PublicKey().ToBase58() {
+		return s.base, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	wm, ok := s.wallets[publicKey]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "wallet %s is not loaded", publicKey)
+	}
+	return wm, nil
+}
+
+// addWallet 用默认钱包的 RPC client、日志、指标和 trace 配置创建使用 signer 的钱包。
+// 策略相同但支出记录独立，一个钱包的支出不占用其他钱包的限额；地址簿是同一份联系人，继续共用。
+// 已加载的钱包保留原来的策略引擎，重新加载不会清空支出记录。
+func (s *grpcServer) addWallet(signer Signer, label string) *walletpb.Account {
+	publicKey := signer.PublicKey().ToBase58()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.wallets[publicKey]; !ok {
+		wm := *s.base
+		wm.Account = types.Account{}
+		wm.Signer = signer
+		wm.Policy = s.base.Policy.clone()
+		s.wallets[publicKey] = &wm
+	}
+	if label != "" {
+		s.labels[publicKey] = label
+	}
+	return &walletpb.Account{PublicKey: publicKey, Label: label}
+}
+
+func (s *grpcServer) CreateAccount(_ context.Context, req *walletpb.CreateAccountRequest) (*walletpb.Account, error) {
+	if req.Label != "" && s.cfg.Registry == nil {
+		return nil, status.Error(codes.FailedPrecondition, "server has no wallet registry, create the account without a label")
+	}
+	account := types.NewAccount()
+	path, err := SaveWallet(s.cfg.KeyDir, account)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save key file: %v", err)
+	}
+	if req.Label != "" {
+		err := s.cfg.Registry.Add(WalletEntry{Label: req.Label, Path: path, Networks: []string{s.base.Network}})
+		if err != nil {
+			return nil, status.Errorf(codes.AlreadyExists, "key file %s saved but not registered: %v", path, err)
+		}
+	}
+	s.base.logger().Info("account created", "operation", "create_account", "pubkey", account.PublicKey.ToBase58(), "path", path)
+	return s.addWallet(NewAccountSigner(account), req.Label), nil
+}
+
+func (s *grpcServer) LoadAccount(_ context.Context, req *walletpb.LoadAccountRequest) (*walletpb.Account, error) {
+	if s.cfg.Registry == nil {
+		return nil, status.Error(codes.FailedPrecondition, "server has no wallet registry")
+	}
+	entry, err := s.cfg.Registry.Lookup(req.Label)
+	if errors.Is(err, ErrWalletNotFound) {
+		return nil, status.Errorf(codes.NotFound, "wallet %q is not registered", req.Label)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !entry.AllowsNetwork(s.base.Network) {
+		return nil, status.Errorf(codes.FailedPrecondition, "wallet %q is not tagged for network %s", req.Label, s.base.Network)
+	}
+
+	var signer Signer
+	if IsEncryptedKeystore(entry.Path) {
+		if s.cfg.Passphrase == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "wallet %q is an encrypted keystore and the server has no passphrase", req.Label)
+		}
+		signer, err = NewKeystoreSigner(entry.Path, s.cfg.Passphrase)
+	} else {
+		var account types.Account
+		account, err = LoadKeypair(entry.Path)
+		signer = NewAccountSigner(account)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load wallet %q: %v", req.Label, err)
+	}
+	if signer.PublicKey().ToBase58() != entry.PublicKey {
+		return nil, status.Errorf(codes.FailedPrecondition, "key file %s no longer matches registered public key %s", entry.Path, entry.PublicKey)
+	}
+	return s.addWallet(signer, req.Label), nil
+}
+
+func (s *grpcServer) ListAccounts(context.Context, *walletpb.ListAccountsRequest) (*walletpb.ListAccountsResponse, error) {
+	resp := &walletpb.ListAccountsResponse{DefaultPublicKey: s.base.PublicKey().ToBase58()}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for publicKey := range s.wallets {
+		resp.Accounts = append(resp.Accounts, &walletpb.Account{PublicKey: publicKey, Label: s.labels[publicKey]})
+	}
+	return resp, nil
+}
+
+func (s *grpcServer) GetBalance(ctx context.Context, req *walletpb.GetBalanceRequest) (*walletpb.Balance, error) {
+	wm, mint, err := s.balanceTarget(req.Wallet, req.Mint)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := wm.CheckAmount(ctx, mint)
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &walletpb.Balance{Mint: mint, Amount: amount}, nil
+}
+
+func (s *grpcServer) TransferSOL(ctx context.Context, req *walletpb.TransferSOLRequest) (*walletpb.TransactionResult, error) {
+	wm, err := s.wallet(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePublicKey("to", req.To); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Lamports == 0 {
+		return nil, status.Error(codes.InvalidArgument, "lamports must be greater than 0")
+	}
+	opts, err := grpcMemoOpts(req.Memo)
+	if err != nil {
+		return nil, err
+	}
+	txhash, err := wm.TransferSOL(ctx, req.To, req.Lamports, opts...)
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &walletpb.TransactionResult{Signature: txhash}, nil
+}
+
+func (s *grpcServer) TransferTokens(ctx context.Context, req *walletpb.TransferTokensRequest) (*walletpb.TransactionResult, error) {
+	wm, err := s.wallet(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	for field, value := range map[string]string{"mint": req.Mint, "destination": req.Destination} {
+		if err := validatePublicKey(field, value); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	source := req.Source
+	if source == "" {
+		ata, _, err := common.FindAssociatedTokenAddress(wm.PublicKey(), common.PublicKeyFromString(req.Mint))
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		source = ata.ToBase58()
+	} else if err := validatePublicKey("source", source); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Amount == 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be greater than 0")
+	}
+	if req.Decimals > 255 {
+		return nil, status.Error(codes.InvalidArgument, "decimals must be at most 255")
+	}
+	opts, err := grpcMemoOpts(req.Memo)
+	if err != nil {
+		return nil, err
+	}
+
+	txhash, err := wm.TransferTokensChecked(ctx, req.Mint, wm.signer(), source, req.Destination, req.Amount, uint8(req.Decimals), opts...)
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &walletpb.TransactionResult{Signature: txhash}, nil
+}
+
+// grpcMemoOpts 校验请求中的 memo，与 REST 接口的 memoOpts 相同
+func grpcMemoOpts(memo string) ([]TxOption, error) {
+	if memo == "" {
+		return nil, nil
+	}
+	if err := ValidateMemo(memo); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return []TxOption{WithMemo(memo)}, nil
+}
+
+func (s *grpcServer) CreateTokenAccount(ctx context.Context, req *walletpb.CreateTokenAccountRequest) (*walletpb.CreateTokenAccountResponse, error) {
+	wm, err := s.wallet(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePublicKey("mint", req.Mint); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ata, err := wm.CreateTokenAccount(ctx, req.Mint)
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &walletpb.CreateTokenAccountResponse{Address: ata}, nil
+}
+
+func (s *grpcServer) CreateMint(ctx context.Context, req *walletpb.CreateMintRequest) (*walletpb.CreateMintResponse, error) {
+	wm, err := s.wallet(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	mint, txhash, err := wm.CreateMint(ctx, wm.signer())
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &walletpb.CreateMintResponse{Mint: mint, Signature: txhash}, nil
+}
+
+func (s *grpcServer) GetQuote(ctx context.Context, req *walletpb.QuoteRequest) (*walletpb.Quote, error) {
+	swap := newSwapRequest(req.InputMint, req.OutputMint, req.Amount, req.SlippageBps)
+	if err := swap.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	quote, err := s.base.GetQuoteContext(ctx, QuoteURL(swap.InputMint, swap.OutputMint, swap.Amount, swap.slippage()))
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return quoteToProto(quote), nil
+}
+
+func (s *grpcServer) Swap(ctx context.Context, req *walletpb.SwapRequest) (*walletpb.SwapResponse, error) {
+	wm, err := s.wallet(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	swap := newSwapRequest(req.InputMint, req.OutputMint, req.Amount, req.SlippageBps)
+	if err := swap.validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	quote, txhash, err := wm.Swap(ctx, swap.InputMint, swap.OutputMint, swap.Amount, swap.slippage())
+	if err != nil {
+		return nil, grpcStatus(err)
+	}
+	return &walletpb.SwapResponse{Signature: txhash, Quote: quoteToProto(quote)}, nil
+}
+
+func (s *grpcServer) WatchConfirmation(req *walletpb.WatchConfirmationRequest, stream walletpb.WalletService_WatchConfirmationServer) error {
+	if decoded, err := base58.Decode(req.Signature); err != nil || len(decoded) != 64 {
+		return status.Error(codes.InvalidArgument, "signature is not a valid base58 transaction signature")
+	}
+	err := s.base.WatchSignature(stream.Context(), req.Signature, func(u SignatureUpdate) error {
+		msg := &walletpb.ConfirmationStatus{Signature: u.Signature, Status: confirmationStatusToProto(u.Status), Slot: u.Slot}
+		if u.Err != nil {
+			msg.Error = u.Err.Error()
+		}
+		return stream.Send(msg)
+	})
+	return grpcStatus(err)
+}
+
+func (s *grpcServer) WatchBalance(req *walletpb.WatchBalanceRequest, stream walletpb.WalletService_WatchBalanceServer) error {
+	wm, mint, err := s.balanceTarget(req.Wallet, req.Mint)
+	if err != nil {
+		return err
+	}
+	interval := defaultBalancePollInterval
+	if req.PollIntervalMs != 0 {
+		interval = max(time.Duration(req.PollIntervalMs)*time.Millisecond, minBalancePollInterval)
+	}
+	err = wm.WatchBalance(stream.Context(), mint, interval, func(amount uint64) error {
+		return stream.Send(&walletpb.Balance{Mint: mint, Amount: amount})
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return grpcStatus(err)
+}
+
+// balanceTarget 解析余额查询的钱包和 mint，mint 为空或 "sol" 时查询 SOL
+func (s *grpcServer) balanceTarget(publicKey string, mint string) (*WalletManager, string, error) {
+	wm, err := s.wallet(publicKey)
+	if err != nil {
+		return nil, "", err
+	}
+	if mint == "" || strings.EqualFold(mint, "sol") {
+		mint = SOL_MINT_ADDR
+	}
+	if err := validatePublicKey("mint", mint); err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return wm, mint, nil
+}
+
+func newSwapRequest(inputMint string, outputMint string, amount uint64, slippageBps *uint32) swapRequest {
+	req := swapRequest{InputMint: inputMint, OutputMint: outputMint, Amount: amount}
+	if slippageBps != nil {
+		bps := int(*slippageBps)
+		req.SlippageBps = &bps
+	}
+	return req
+}
+
+func quoteToProto(q *QuoteResponse) *walletpb.Quote {
+	return &walletpb.Quote{
+		InputMint:            q.InputMint,
+		InAmount:             q.InAmount,
+		OutputMint:           q.OutputMint,
+		OutAmount:            q.OutAmount,
+		OtherAmountThreshold: q.OtherAmountThreshold,
+		SlippageBps:          uint32(q.SlippageBps),
+		PriceImpactPct:       q.PriceImpactPct,
+	}
+}
+
+func confirmationStatusToProto(s string) walletpb.ConfirmationStatus_Status {
+	switch s {
+	case SignaturePending:
+		return walletpb.ConfirmationStatus_STATUS_PENDING
+	case SignatureProcessed:
+		return walletpb.ConfirmationStatus_STATUS_PROCESSED
+	case SignatureConfirmed:
+		return walletpb.ConfirmationStatus_STATUS_CONFIRMED
+	case SignatureFinalized:
+		return walletpb.ConfirmationStatus_STATUS_FINALIZED
+	case SignatureFailed:
+		return walletpb.ConfirmationStatus_STATUS_FAILED
+	}
+	return walletpb.ConfirmationStatus_STATUS_UNSPECIFIED
+}
+
+// grpcStatus 把钱包错误转换为 gRPC 状态码，对应关系与 REST 接口的 HTTP 状态码一致
+func grpcStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var rpcErr *RPCError
+	var programErr *ProgramError
+	var violation *PolicyViolation
+	code := codes.Internal
+	switch {
+	case errors.As(err, &violation):
+		code = codes.PermissionDenied
+	case errors.Is(err, ErrInvalidAddress), errors.Is(err, ErrInvalidMemo):
+		code = codes.InvalidArgument
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, ErrInsufficientFunds), errors.Is(err, ErrSlippageExceeded), errors.Is(err, ErrUnexpectedOwner),
+		errors.Is(err, ErrBelowRentExempt), errors.As(err, &programErr):
+		code = codes.FailedPrecondition
+	case errors.Is(err, ErrAccountNotFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrBlockhashExpired), errors.As(err, &rpcErr):
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,214 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	"github.com/paxzhu/go-solana/pkg/walletpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGRPCClient 启动内存中的 gRPC 服务，返回客户端和带 API key 的 context
+func newTestGRPCClient(t *testing.T, wm *WalletManager, cfg GRPCServerConfig) (walletpb.WalletServiceClient, context.Context) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(wm, cfg)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testAPIKey)
+	return walletpb.NewWalletServiceClient(conn), ctx
+}
+
+func TestGRPCServerTransfer(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	client, ctx := newTestGRPCClient(t, wm, GRPCServerConfig{APIKeys: []string{testAPIKey}})
+
+	_, err := client.GetBalance(context.Background(), &walletpb.GetBalanceRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	balance, err := client.GetBalance(ctx, &walletpb.GetBalanceRequest{Mint: "sol"})
+	require.NoError(t, err)
+	assert.Equal(t, SOL_MINT_ADDR, balance.Mint)
+	assert.Equal(t, uint64(1_000_000_000), balance.Amount)
+
+	to := types.NewAccount().PublicKey.ToBase58()
+	result, err := client.TransferSOL(ctx, &walletpb.TransferSOLRequest{To: to, Lamports: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, "5Sig", result.Signature)
+
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{To: "bad", Lamports: 1000})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{To: to, Lamports: 2_000_000_000})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{Wallet: to, To: to, Lamports: 1000})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, 1, stub.callCount("sendTransaction"))
+
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{To: to, Lamports: 1000, Memo: "invoice 42"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invoice 42"}, MessageMemos(sentTransaction(t, stub, 1).Message))
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{To: to, Lamports: 1000, Memo: strings.Repeat("m", MaxMemoLength+1)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 2, stub.callCount("sendTransaction"))
+}
+
+func TestGRPCServerAccounts(t *testing.T) {
+	dir := t.TempDir()
+	registry, err := OpenRegistry(filepath.Join(dir, "registry.json"))
+	require.NoError(t, err)
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	client, ctx := newTestGRPCClient(t, wm, GRPCServerConfig{APIKeys: []string{testAPIKey}, Registry: registry, KeyDir: dir})
+
+	created, err := client.CreateAccount(ctx, &walletpb.CreateAccountRequest{Label: "hot"})
+	require.NoError(t, err)
+	entry, err := registry.Lookup("hot")
+	require.NoError(t, err)
+	assert.Equal(t, created.PublicKey, entry.PublicKey)
+	assert.Equal(t, []string{"devnet"}, entry.Networks)
+
+	loaded, err := client.LoadAccount(ctx, &walletpb.LoadAccountRequest{Label: "hot"})
+	require.NoError(t, err)
+	assert.Equal(t, created.PublicKey, loaded.PublicKey)
+	_, err = client.LoadAccount(ctx, &walletpb.LoadAccountRequest{Label: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	list, err := client.ListAccounts(ctx, &walletpb.ListAccountsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, wm.PublicKey().ToBase58(), list.DefaultPublicKey)
+	require.Len(t, list.Accounts, 1)
+	assert.Equal(t, "hot", list.Accounts[0].Label)
+
+	// 新钱包作为手续费支付账户签名
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{Wallet: created.PublicKey, To: wm.PublicKey().ToBase58(), Lamports: 1000})
+	require.NoError(t, err)
+	var encoded string
+	require.NoError(t, json.Unmarshal(stub.calls["sendTransaction"][0][0], &encoded))
+	tx, err := DecodeTransaction(encoded, EncodingBase64)
+	require.NoError(t, err)
+	assert.Equal(t, created.PublicKey, tx.Message.Accounts[0].ToBase58())
+}
+
+func TestGRPCServerStreams(t *testing.T) {
+	defer func(d time.Duration) { confirmPollInterval = d }(confirmPollInterval)
+	confirmPollInterval = 10 * time.Millisecond
+
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	statuses := []any{nil, "processed", "processed", "confirmed", "finalized"}
+	stub.on("getSignatureStatuses", func([]json.RawMessage) any {
+		s := statuses[min(stub.callCount("getSignatureStatuses"), len(statuses))-1]
+		if s == nil {
+			return withContext([]any{nil})
+		}
+		return withContext([]any{map[string]any{"slot": 10, "confirmations": 0, "confirmationStatus": s, "err": nil}})
+	})
+	balances := []uint64{100, 100, 250}
+	stub.on("getBalance", func([]json.RawMessage) any {
+		return withContext(balances[min(stub.callCount("getBalance"), len(balances))-1])
+	})
+	client, ctx := newTestGRPCClient(t, wm, GRPCServerConfig{APIKeys: []string{testAPIKey}})
+
+	signature := base58.Encode(make([]byte, 64))
+	confirmations, err := client.WatchConfirmation(ctx, &walletpb.WatchConfirmationRequest{Signature: signature})
+	require.NoError(t, err)
+	var got []walletpb.ConfirmationStatus_Status
+	for {
+		msg, err := confirmations.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, msg.Status)
+	}
+	assert.Equal(t, []walletpb.ConfirmationStatus_Status{
+		walletpb.ConfirmationStatus_STATUS_PENDING,
+		walletpb.ConfirmationStatus_STATUS_PROCESSED,
+		walletpb.ConfirmationStatus_STATUS_CONFIRMED,
+		walletpb.ConfirmationStatus_STATUS_FINALIZED,
+	}, got)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	updates, err := client.WatchBalance(watchCtx, &walletpb.WatchBalanceRequest{PollIntervalMs: 1})
+	require.NoError(t, err)
+	first, err := updates.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), first.Amount)
+	second, err := updates.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(250), second.Amount)
+}
+
+func TestGRPCServerPolicyPerWallet(t *testing.T) {
+	dir := t.TempDir()
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	engine, err := NewPolicyEngine(Policy{Mints: map[string]MintLimit{"SOL": {MaxPerWindow: uint64Ptr(1500)}}}, nil)
+	require.NoError(t, err)
+	wm.Policy = engine
+	client, ctx := newTestGRPCClient(t, wm, GRPCServerConfig{APIKeys: []string{testAPIKey}, KeyDir: dir})
+
+	created, err := client.CreateAccount(ctx, &walletpb.CreateAccountRequest{})
+	require.NoError(t, err)
+	to := types.NewAccount().PublicKey.ToBase58()
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{To: to, Lamports: 1000})
+	require.NoError(t, err)
+
+	// 新钱包的限额不受默认钱包支出的影响
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{Wallet: created.PublicKey, To: to, Lamports: 1000})
+	require.NoError(t, err)
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{To: to, Lamports: 1000})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, uint64(1000), engine.Spent(SOL_MINT_ADDR))
+}
+
+func TestGRPCServerReloadKeepsPolicy(t *testing.T) {
+	dir := t.TempDir()
+	registry, err := OpenRegistry(filepath.Join(dir, "registry.json"))
+	require.NoError(t, err)
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	engine, err := NewPolicyEngine(Policy{Mints: map[string]MintLimit{"SOL": {MaxPerWindow: uint64Ptr(1500)}}}, nil)
+	require.NoError(t, err)
+	wm.Policy = engine
+	client, ctx := newTestGRPCClient(t, wm, GRPCServerConfig{APIKeys: []string{testAPIKey}, Registry: registry, KeyDir: dir})
+
+	created, err := client.CreateAccount(ctx, &walletpb.CreateAccountRequest{Label: "hot"})
+	require.NoError(t, err)
+	_, err = client.LoadAccount(ctx, &walletpb.LoadAccountRequest{Label: "hot"})
+	require.NoError(t, err)
+	to := types.NewAccount().PublicKey.ToBase58()
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{Wallet: created.PublicKey, To: to, Lamports: 1000})
+	require.NoError(t, err)
+
+	// 重新加载不会重置窗口内的支出
+	_, err = client.LoadAccount(ctx, &walletpb.LoadAccountRequest{Label: "hot"})
+	require.NoError(t, err)
+	_, err = client.TransferSOL(ctx, &walletpb.TransferSOLRequest{Wallet: created.PublicKey, To: to, Lamports: 1000})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "policy")
+	assert.Equal(t, 1, stub.callCount("sendTransaction"))
+}
@@ -0,0 +1,143 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// DefaultKeyDir 默认的私钥文件目录
+const DefaultKeyDir = "assets"
+
+// 私钥文件和目录权限，只有当前用户可读写
+const (
+	keyFilePerm = 0o600
+	keyDirPerm  = 0o700
+)
+
+// ErrKeyFileExists 目标私钥文件已存在，拒绝覆盖
+var ErrKeyFileExists = errors.New("key file already exists")
+
+// SaveWallet 将私钥保存到 dir/wallet_<公钥前10位>.json。
+// 前缀与其他钱包冲突时改用完整公钥作为文件名，任何情况下都不会覆盖已有文件。
+func SaveWallet(dir string, account types.Account) (string, error) {
+	publicKey := account.PublicKey.ToBase58()
+	filename := filepath.Join(dir, fmt.Sprintf("wallet_%s.json", publicKey[:10]))
+
+	err := SaveKeypair(filename, account)
+	if errors.Is(err, ErrKeyFileExists) {
+		filename = filepath.Join(dir, fmt.Sprintf("wallet_%s.json", publicKey))
+		err = SaveKeypair(filename, account)
+	}
+	if err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// saveWalletIfMissing 与 SaveWallet 相同，但目录中已有同一私钥的钱包文件时直接返回该文件，
+// existed 为 true。用于从助记词恢复，重复执行不会生成重复的文件。
+func saveWalletIfMissing(dir string, account types.Account) (filename string, existed bool, err error) {
+	publicKey := account.PublicKey.ToBase58()
+	for _, name := range []string{publicKey[:10], publicKey} {
+		filename := filepath.Join(dir, fmt.Sprintf("wallet_%s.json", name))
+		loaded, err := LoadKeypair(filename)
+		if err == nil && loaded.PublicKey == account.PublicKey {
+			return filename, true, nil
+		}
+	}
+	filename, err = SaveWallet(dir, account)
+	return filename, false, err
+}
+
+// SaveKeypair 以 0600 权限原子写入私钥文件，目录不存在时自动创建，文件已存在时返回 ErrKeyFileExists。
+// 写入后重新读取校验，确保文件能被 LoadAccount 正确加载。
+func SaveKeypair(path string, account types.Account) error {
+	data, err := json.Marshal(account.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("unable to encode private key: %w", err)
+	}
+	if err := writeFileAtomic(path, append(data, '\n'), keyFilePerm, false); err != nil {
+		return err
+	}
+
+	loaded, err := LoadKeypair(path)
+	if err != nil || loaded.PublicKey != account.PublicKey {
+		os.Remove(path)
+		if err == nil {
+			err = fmt.Errorf("public key mismatch: wrote %s, read back %s", account.PublicKey.ToBase58(), loaded.PublicKey.ToBase58())
+		}
+		return fmt.Errorf("key file verification failed: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic 先写同目录下的临时文件并 fsync，再移动到目标路径，
+// 进程崩溃时不会留下写了一半的文件。overwrite 为 false 时用硬链接实现不覆盖的原子移动。
+func writeFileAtomic(path string, data []byte, perm os.FileMode, overwrite bool) error {
+	dir := filepath.Dir(path)
+	dirPerm := os.FileMode(0o755)
+	if perm&0o077 == 0 {
+		dirPerm = keyDirPerm
+	}
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return fmt.Errorf("unable to create directory %s: %w", dir, err)
+	}
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%w: %s", ErrKeyFileExists, path)
+		}
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("unable to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to set file permissions: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write to file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to sync file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to close file: %w", err)
+	}
+
+	if overwrite {
+		err = os.Rename(tmpName, path)
+	} else {
+		// Link 在目标已存在时失败，避免检查和移动之间被其他进程抢先写入
+		err = os.Link(tmpName, path)
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrKeyFileExists, path)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("unable to move file into place: %w", err)
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir 刷新目录项，确保重命名在断电后仍然生效（部分平台不支持，忽略错误）
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	_ = d.Sync()
+}
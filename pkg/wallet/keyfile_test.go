@@ -0,0 +1,88 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveKeypair(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "keys")
+	path := filepath.Join(dir, "key.json")
+	account := types.NewAccount()
+
+	// 目录不存在时自动创建，目录和文件都只有当前用户可访问
+	require.NoError(t, SaveKeypair(path, account))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	dirInfo, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), dirInfo.Mode().Perm())
+
+	loaded, err := LoadKeypair(path)
+	require.NoError(t, err)
+	assert.Equal(t, account.PublicKey, loaded.PublicKey)
+
+	// 拒绝覆盖已有文件，原文件保持不变
+	err = SaveKeypair(path, types.NewAccount())
+	assert.ErrorIs(t, err, ErrKeyFileExists)
+	loaded, err = LoadKeypair(path)
+	require.NoError(t, err)
+	assert.Equal(t, account.PublicKey, loaded.PublicKey)
+
+	// 不残留临时文件
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestSaveWallet(t *testing.T) {
+	dir := t.TempDir()
+	account := types.NewAccount()
+	publicKey := account.PublicKey.ToBase58()
+
+	filename, err := SaveWallet(dir, account)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "wallet_"+publicKey[:10]+".json"), filename)
+
+	// 前缀冲突时改用完整公钥作为文件名
+	filename, err = SaveWallet(dir, account)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "wallet_"+publicKey+".json"), filename)
+
+	// 两个文件名都被占用时报错
+	_, err = SaveWallet(dir, account)
+	assert.ErrorIs(t, err, ErrKeyFileExists)
+}
+
+func TestSaveWalletIfMissing(t *testing.T) {
+	dir := t.TempDir()
+	account := types.NewAccount()
+
+	filename, existed, err := saveWalletIfMissing(dir, account)
+	require.NoError(t, err)
+	assert.False(t, existed)
+
+	// 再次恢复同一钱包时复用已有文件
+	again, existed, err := saveWalletIfMissing(dir, account)
+	require.NoError(t, err)
+	assert.True(t, existed)
+	assert.Equal(t, filename, again)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// 文件名被其他私钥占用时仍按 SaveWallet 的规则改用完整公钥
+	dir = t.TempDir()
+	publicKey := account.PublicKey.ToBase58()
+	require.NoError(t, SaveKeypair(filepath.Join(dir, "wallet_"+publicKey[:10]+".json"), types.NewAccount()))
+	filename, existed, err = saveWalletIfMissing(dir, account)
+	require.NoError(t, err)
+	assert.False(t, existed)
+	assert.Equal(t, filepath.Join(dir, "wallet_"+publicKey+".json"), filename)
+}
@@ -0,0 +1,240 @@
+package wallet
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt 参数
+const (
+	keystoreVersion = 1
+	scryptN         = 1 << 15
+	scryptR         = 8
+	scryptP         = 1
+	scryptKeyLen    = 32
+	// 读取 keystore 时允许的 scrypt 参数上限，防止损坏或恶意的文件耗尽内存和 CPU
+	maxScryptN = 1 << 20
+	maxScryptR = 32
+	maxScryptP = 16
+)
+
+// encryptedKeystore 加密 keystore 文件格式，私钥使用 scrypt + AES-256-GCM 加密
+type encryptedKeystore struct {
+	Version    int    `json:"version"`
+	PublicKey  string `json:"publicKey"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       string `json:"salt"`
+	Cipher     string `json:"cipher"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// PassphraseFunc 在每次签名时提供 keystore 密码
+type PassphraseFunc func() ([]byte, error)
+
+// DefaultPassphraseEnv 命令行工具和服务默认从该环境变量读取 keystore 密码
+const DefaultPassphraseEnv = "WALLET_KEYSTORE_PASSPHRASE"
+
+// EnvPassphrase 返回从环境变量 name 读取 keystore 密码的 PassphraseFunc，变量未设置或为空时返回错误
+func EnvPassphrase(name string) PassphraseFunc {
+	return func() ([]byte, error) {
+		passphrase, ok := os.LookupEnv(name)
+		if !ok || passphrase == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", name)
+		}
+		return []byte(passphrase), nil
+	}
+}
+
+// KeystoreSigner 使用加密 keystore 签名。
+// 私钥只在签名时解密，签名后立即清零，不会常驻内存。
+type KeystoreSigner struct {
+	path       string
+	publicKey  common.PublicKey
+	passphrase PassphraseFunc
+}
+
+// SaveEncryptedKeystore 用密码加密账户私钥并写入文件
+func SaveEncryptedKeystore(path string, account types.Account, passphrase []byte) error {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	publicKey := account.PublicKey.ToBase58()
+	ciphertext := gcm.Seal(nil, nonce, account.PrivateKey, []byte(publicKey))
+
+	data, err := json.MarshalIndent(encryptedKeystore{
+		Version:    keystoreVersion,
+		PublicKey:  publicKey,
+		KDF:        "scrypt",
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Cipher:     "aes-256-gcm",
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, keyFilePerm, false)
+}
+
+// NewKeystoreSigner 打开加密 keystore，只读取公钥，私钥在签名时才解密
+func NewKeystoreSigner(path string, passphrase PassphraseFunc) (*KeystoreSigner, error) {
+	ks, err := readKeystore(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ks.checkScryptParams(path); err != nil {
+		return nil, err
+	}
+	return &KeystoreSigner{
+		path:       path,
+		publicKey:  common.PublicKeyFromString(ks.PublicKey),
+		passphrase: passphrase,
+	}, nil
+}
+
+// PublicKey 返回 keystore 中账户的公钥
+func (s *KeystoreSigner) PublicKey() common.PublicKey {
+	return s.publicKey
+}
+
+// SignMessage 解密私钥、签名并清零私钥
+func (s *KeystoreSigner) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keystore passphrase: %w", err)
+	}
+	privateKey, err := decryptKeystore(s.path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(privateKey)
+
+	return ed25519.Sign(privateKey, message), nil
+}
+
+// DecryptKeystore 解密 keystore 并返回账户，用于导出或迁移
+func DecryptKeystore(path string, passphrase []byte) (types.Account, error) {
+	privateKey, err := decryptKeystore(path, passphrase)
+	if err != nil {
+		return types.Account{}, err
+	}
+	return types.AccountFromBytes(privateKey)
+}
+
+// IsEncryptedKeystore 判断文件是否为加密 keystore（而不是明文私钥数组）
+func IsEncryptedKeystore(path string) bool {
+	_, err := readKeystore(path)
+	return err == nil
+}
+
+func readKeystore(path string) (*encryptedKeystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	var ks encryptedKeystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore: %w", err)
+	}
+	if ks.Version != keystoreVersion || ks.KDF != "scrypt" || ks.Cipher != "aes-256-gcm" {
+		return nil, fmt.Errorf("unsupported keystore format in %s", path)
+	}
+	return &ks, nil
+}
+
+// checkScryptParams 拒绝超出上限的 scrypt 参数，在派生密钥前调用
+func (ks *encryptedKeystore) checkScryptParams(path string) error {
+	if ks.N < 2 || ks.N > maxScryptN || ks.N&(ks.N-1) != 0 || ks.R < 1 || ks.R > maxScryptR || ks.P < 1 || ks.P > maxScryptP {
+		return fmt.Errorf("unsupported scrypt parameters in %s: n=%d r=%d p=%d (n must be a power of two up to %d, r at most %d, p at most %d)",
+			path, ks.N, ks.R, ks.P, maxScryptN, maxScryptR, maxScryptP)
+	}
+	return nil
+}
+
+func decryptKeystore(path string, passphrase []byte) (ed25519.PrivateKey, error) {
+	ks, err := readKeystore(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ks.checkScryptParams(path); err != nil {
+		return nil, err
+	}
+	salt, err := base64.StdEncoding.DecodeString(ks.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(ks.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(ks.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+
+	key, err := scrypt.Key(passphrase, salt, ks.N, ks.R, ks.P, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	defer clear(key)
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	// gcm.Open 在 nonce 长度不对时 panic，手工改动或截断的文件需要在这里拦下
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("failed to decrypt keystore: corrupted file: nonce has %d bytes, want %d", len(nonce), gcm.NonceSize())
+	}
+	privateKey, err := gcm.Open(nil, nonce, ciphertext, []byte(ks.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: wrong passphrase or corrupted file")
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("keystore private key has invalid length %d", len(privateKey))
+	}
+	return privateKey, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+	return gcm, nil
+}
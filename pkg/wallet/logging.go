@@ -0,0 +1,137 @@
+package wallet
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// redacted 替换敏感字段的占位符
+	redacted = "REDACTED"
+	// maxLoggedBody 日志中记录的 HTTP 请求/响应体的最大长度
+	maxLoggedBody = 2048
+)
+
+// sensitiveKeys 日志字段名或 URL 参数名包含这些词时值会被隐藏
+var sensitiveKeys = []string{
+	"private", "secret", "passphrase", "password", "mnemonic", "seed",
+	"apikey", "api-key", "api_key", "authtoken", "auth_token", "access_token", "authorization",
+}
+
+// NewLogger 创建输出 JSON 的 slog.Logger，自动隐藏私钥、密码、助记词和 URL 中的 API key。
+// opts 可为空，opts.ReplaceAttr 会在脱敏之后调用。
+func NewLogger(w io.Writer, opts *slog.HandlerOptions) *slog.Logger {
+	var o slog.HandlerOptions
+	if opts != nil {
+		o = *opts
+	}
+	next := o.ReplaceAttr
+	o.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		a = RedactAttr(groups, a)
+		if next != nil {
+			a = next(groups, a)
+		}
+		return a
+	}
+	return slog.New(slog.NewJSONHandler(w, &o))
+}
+
+// RedactAttr 可用作 slog.HandlerOptions.ReplaceAttr：
+// 字段名看起来是敏感信息时隐藏其值，字符串值是 URL 时隐藏其中的凭据
+func RedactAttr(_ []string, a slog.Attr) slog.Attr {
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	if a.Value.Kind() == slog.KindString {
+		if s := a.Value.String(); strings.Contains(s, "://") {
+			return slog.String(a.Key, redactURL(s))
+		}
+	}
+	return a
+}
+
+// logger 返回 WalletManager 使用的日志记录器，未设置时使用 slog.Default()
+func (wm *WalletManager) logger() *slog.Logger {
+	if wm.Logger != nil {
+		return wm.Logger
+	}
+	return slog.Default()
+}
+
+// sendTransaction 广播交易并记录结果：成功为 Info，失败为 Error
+func (wm *WalletManager) sendTransaction(ctx context.Context, operation string, tx types.Transaction, attrs ...any) (string, error) {
+	parent := trace.SpanFromContext(ctx)
+	ctx, span := startSpan(ctx, "wallet.send")
+	start := time.Now()
+	markBroadcast(ctx)
+	txhash, err := wm.Client.SendTransaction(ctx, tx)
+	wm.Metrics.observeSend(wm.Network, operation, err)
+
+	attrs = append([]any{
+		"operation", operation,
+		"network", wm.Network,
+		"pubkey", tx.Message.Accounts[0].ToBase58(),
+		"latency", time.Since(start),
+	}, attrs...)
+	if err != nil {
+		// 交易没有发出，撤销签名前记录的策略支出
+		wm.releasePolicy(tx.Message)
+		err = wrapRPCError(err, &tx)
+		endSpan(span, err)
+		wm.logger().ErrorContext(ctx, "transaction failed", append(attrs, "error", err)...)
+		return "", err
+	}
+	span.SetAttributes(attrSignature.String(txhash))
+	parent.SetAttributes(attrSignature.String(txhash))
+	span.End()
+	wm.logger().InfoContext(ctx, "transaction sent", append(attrs, "signature", txhash)...)
+	return txhash, nil
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL 隐藏 URL 中的用户名密码和敏感查询参数（例如 RPC 节点的 api-key）
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if u.User != nil {
+		u.User = url.User(redacted)
+	}
+	query := u.Query()
+	changed := false
+	for key := range query {
+		if isSensitiveKey(key) || strings.EqualFold(key, "key") {
+			query.Set(key, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// truncate 截断过长的字符串
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "...(truncated)"
+}
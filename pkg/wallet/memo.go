@@ -0,0 +1,156 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/memo"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// MaxMemoLength memo 的最大字节数。交易总大小限制为 1232 字节，
+// 一笔普通转账加上 memo 指令后剩余的空间约为 566 字节。
+const MaxMemoLength = 566
+
+// ErrInvalidMemo memo 为空、超过 MaxMemoLength 字节或不是合法的 UTF-8
+var ErrInvalidMemo = errors.New("invalid memo")
+
+// memoV1ProgramID 旧版 Memo 程序，解析历史交易时一并识别
+var memoV1ProgramID = common.PublicKeyFromString("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
+// HistoryEntry 钱包的一条交易记录
+type HistoryEntry struct {
+	Signature string
+	Slot      uint64
+	BlockTime *time.Time // 节点没有记录出块时间时为空
+	Err       error      // 交易执行失败时的错误
+	Memos     []string
+}
+
+// ValidateMemo 检查 memo 能否附加到交易上
+func ValidateMemo(memo string) error {
+	if memo == "" {
+		return fmt.Errorf("%w: memo is empty", ErrInvalidMemo)
+	}
+	if len(memo) > MaxMemoLength {
+		return fmt.Errorf("%w: %d bytes, the maximum is %d", ErrInvalidMemo, len(memo), MaxMemoLength)
+	}
+	if !utf8.ValidString(memo) {
+		return fmt.Errorf("%w: not valid UTF-8", ErrInvalidMemo)
+	}
+	return nil
+}
+
+// WithMemo 在交易末尾加入一条 SPL Memo 指令，由手续费支付账户签名。
+// 所有构建交易的方法都支持，memo 在构建时校验，不合法时返回 ErrInvalidMemo。
+func WithMemo(memo string) TxOption {
+	return func(o *txOptions) {
+		o.memo = &memo
+	}
+}
+
+// memoInstruction 由 signer 签名的 memo 指令
+func memoInstruction(signer common.PublicKey, text string) (types.Instruction, error) {
+	if err := ValidateMemo(text); err != nil {
+		return types.Instruction{}, err
+	}
+	return memo.BuildMemo(memo.BuildMemoParam{
+		SignerPubkeys: []common.PublicKey{signer},
+		Memo:          []byte(text),
+	}), nil
+}
+
+// memoInstructions 设置了 memo 时返回 buildMessage 将追加的 Memo 指令，
+// 批量操作按交易大小打包时需要计入
+func (o txOptions) memoInstructions(feePayer common.PublicKey) ([]types.Instruction, error) {
+	if o.memo == nil {
+		return nil, nil
+	}
+	ins, err := memoInstruction(feePayer, *o.memo)
+	if err != nil {
+		return nil, err
+	}
+	return []types.Instruction{ins}, nil
+}
+
+// MessageMemos 返回交易消息中所有 Memo 指令的内容
+func MessageMemos(message types.Message) []string {
+	var memos []string
+	for _, ins := range message.Instructions {
+		programID, _, ok := compiledAccounts(message, ins)
+		if ok && (programID == common.MemoProgramID || programID == memoV1ProgramID) {
+			memos = append(memos, string(ins.Data))
+		}
+	}
+	return memos
+}
+
+// History 按时间倒序返回 address 最近的 limit 条交易，address 为空时使用当前钱包。
+// before 不为空时从该签名之前开始（用于翻页）。memo 来自节点对 Memo 指令的索引，不需要逐笔读取交易。
+func (wm *WalletManager) History(ctx context.Context, address string, limit int, before string) (_ []HistoryEntry, err error) {
+	ctx, span := wm.startOperation(ctx, "History")
+	defer func() { endSpan(span, err) }()
+
+	if address == "" {
+		address = wm.PublicKey().ToBase58()
+	} else if _, err := ParseAddress(address); err != nil {
+		return nil, err
+	}
+	signatures, err := wm.Client.GetSignaturesForAddressWithConfig(ctx, address, client.GetSignaturesForAddressConfig{
+		Limit:  limit,
+		Before: before,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signatures: %w", wrapRPCError(err, nil))
+	}
+	entries := make([]HistoryEntry, 0, len(signatures))
+	for _, s := range signatures {
+		entry := HistoryEntry{Signature: s.Signature, Slot: s.Slot}
+		if s.BlockTime != nil {
+			t := time.Unix(*s.BlockTime, 0)
+			entry.BlockTime = &t
+		}
+		if s.Err != nil {
+			entry.Err = parseTransactionError(s.Err, nil)
+		}
+		if s.Memo != nil {
+			entry.Memos = parseRPCMemos(*s.Memo)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseRPCMemos 解析 getSignaturesForAddress 返回的 memo 字段。
+// 节点把每条 memo 格式化为 "[字节数] 内容"，多条之间用 "; " 连接；按字节数切分，内容中的 "; " 不受影响。
+// 格式不符时把整个字段作为一条 memo 返回。
+func parseRPCMemos(field string) []string {
+	var memos []string
+	rest := field
+	for rest != "" {
+		end := strings.Index(rest, "] ")
+		if !strings.HasPrefix(rest, "[") || end < 0 {
+			return []string{field}
+		}
+		n, err := strconv.Atoi(rest[1:end])
+		if err != nil || n < 0 || end+2+n > len(rest) {
+			return []string{field}
+		}
+		memos = append(memos, rest[end+2:end+2+n])
+		rest = rest[end+2+n:]
+		if rest != "" {
+			if !strings.HasPrefix(rest, "; ") {
+				return []string{field}
+			}
+			rest = rest[2:]
+		}
+	}
+	return memos
+}
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMemo(t *testing.T) {
+	assert.NoError(t, ValidateMemo("INV-2024-001"))
+	assert.NoError(t, ValidateMemo("发票 2024-001"))
+	assert.NoError(t, ValidateMemo(strings.Repeat("x", MaxMemoLength)))
+	assert.ErrorIs(t, ValidateMemo(""), ErrInvalidMemo)
+	assert.ErrorIs(t, ValidateMemo(strings.Repeat("x", MaxMemoLength+1)), ErrInvalidMemo)
+	assert.ErrorIs(t, ValidateMemo("bad \xff"), ErrInvalidMemo)
+}
+
+func TestTransferSOLWithMemo(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	to := types.NewAccount().PublicKey.ToBase58()
+
+	_, err := wm.TransferSOL(context.Background(), to, 1000, WithMemo("INV-2024-001"))
+	require.NoError(t, err)
+	tx := sentTransaction(t, stub, 0)
+	require.Len(t, tx.Message.Instructions, 2)
+	last := tx.Message.Instructions[1]
+	assert.Equal(t, common.MemoProgramID, tx.Message.Accounts[last.ProgramIDIndex])
+	assert.Equal(t, []string{"INV-2024-001"}, MessageMemos(tx.Message))
+	summary, err := SummarizeTransaction(tx)
+	require.NoError(t, err)
+	assert.Contains(t, summary, `Memo: "INV-2024-001"`)
+
+	_, err = wm.TransferSOL(context.Background(), to, 1000, WithMemo(strings.Repeat("x", MaxMemoLength+1)))
+	assert.ErrorIs(t, err, ErrInvalidMemo)
+	assert.Equal(t, 1, stub.callCount("sendTransaction"))
+}
+
+func TestParseRPCMemos(t *testing.T) {
+	tests := []struct {
+		field string
+		want  []string
+	}{
+		{"[12] INV-2024-001", []string{"INV-2024-001"}},
+		{"[4] a; b; [6] 发票", []string{"a; b", "发票"}},
+		{"not formatted", []string{"not formatted"}},
+		{"[99] short", []string{"[99] short"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, parseRPCMemos(tt.field), tt.field)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("getSignaturesForAddress", func([]json.RawMessage) any {
+		return []any{
+			map[string]any{"signature": "5Sig2", "slot": 20, "blockTime": 1700000000, "err": nil, "memo": "[12] INV-2024-002"},
+			map[string]any{"signature": "5Sig1", "slot": 10, "blockTime": nil, "err": map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}}, "memo": nil},
+		}
+	})
+
+	entries, err := wm.History(context.Background(), "", 2, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"INV-2024-002"}, entries[0].Memos)
+	assert.Equal(t, int64(1700000000), entries[0].BlockTime.Unix())
+	assert.NoError(t, entries[0].Err)
+	assert.Nil(t, entries[1].BlockTime)
+	assert.Error(t, entries[1].Err)
+	assert.Empty(t, entries[1].Memos)
+
+	var address string
+	require.NoError(t, json.Unmarshal(stub.calls["getSignaturesForAddress"][0][0], &address))
+	assert.Equal(t, wm.PublicKey().ToBase58(), address)
+
+	_, err = wm.History(context.Background(), "not-a-key", 2, "")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+}
@@ -0,0 +1,183 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Metrics WalletManager 的 Prometheus 指标。
+// 所有方法都可以在 nil 上调用，未启用指标时不做任何事情。
+type Metrics struct {
+	registry *prometheus.Registry
+
+	rpcRequests     *prometheus.CounterVec
+	rpcDuration     *prometheus.HistogramVec
+	txSent          *prometheus.CounterVec
+	txConfirmed     *prometheus.CounterVec
+	confirmDuration *prometheus.HistogramVec
+	feesPaid        *prometheus.CounterVec
+	balance         *prometheus.GaugeVec
+}
+
+// NewMetrics 创建并注册指标，reg 为空时使用新的 Registry
+func NewMetrics(reg *prometheus.Registry) *Metrics {
+	if reg == nil {
+		reg = prometheus.NewRegistry()
+	}
+	m := &Metrics{
+		registry: reg,
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solana_rpc_requests_total",
+			Help: "JSON-RPC requests by method, endpoint host and HTTP status code.",
+		}, []string{"method", "endpoint", "code"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "solana_rpc_request_duration_seconds",
+			Help:    "JSON-RPC request latency by method and endpoint host.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "endpoint"}),
+		txSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solana_transactions_sent_total",
+			Help: "Transactions submitted by operation, with status ok or error.",
+		}, []string{"network", "operation", "status"}),
+		txConfirmed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solana_transactions_confirmed_total",
+			Help: "Transactions that reached confirmed commitment (status confirmed) or failed on chain (status failed).",
+		}, []string{"network", "status"}),
+		confirmDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "solana_transaction_confirmation_seconds",
+			Help:    "Time from waiting on a signature until it is confirmed.",
+			Buckets: []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 90},
+		}, []string{"network"}),
+		feesPaid: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "solana_transaction_fees_lamports_total",
+			Help: "Transaction fees paid by confirmed transactions, in lamports.",
+		}, []string{"network"}),
+		balance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "solana_wallet_balance",
+			Help: "Last observed wallet balance in base units (lamports for SOL).",
+		}, []string{"network", "pubkey", "mint"}),
+	}
+	reg.MustRegister(m.rpcRequests, m.rpcDuration, m.txSent, m.txConfirmed, m.confirmDuration, m.feesPaid, m.balance)
+	return m
+}
+
+// Handler 返回 /metrics 的 HTTP handler
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Client 创建记录每次 RPC 调用的 client
+func (m *Metrics) Client(endpoint string) *client.Client {
+	return newRPCClient(endpoint, m)
+}
+
+// newRPCClient 创建为每次 RPC 调用记录 span 的 client，m 非空时同时记录指标
+func newRPCClient(endpoint string, m *Metrics) *client.Client {
+	return client.New(rpc.WithEndpoint(endpoint), rpc.WithHTTPClient(&http.Client{
+		Transport: &rpcTransport{metrics: m, endpoint: endpointLabel(endpoint), next: http.DefaultTransport},
+	}))
+}
+
+func (m *Metrics) observeSend(network string, operation string, err error) {
+	if m == nil {
+		return
+	}
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	m.txSent.WithLabelValues(network, operation, status).Inc()
+}
+
+func (m *Metrics) observeConfirmation(network string, elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	if err != nil {
+		m.txConfirmed.WithLabelValues(network, "failed").Inc()
+		return
+	}
+	m.txConfirmed.WithLabelValues(network, "confirmed").Inc()
+	m.confirmDuration.WithLabelValues(network).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) observeFee(network string, lamports uint64) {
+	if m == nil {
+		return
+	}
+	m.feesPaid.WithLabelValues(network).Add(float64(lamports))
+}
+
+func (m *Metrics) observeBalance(network string, pubkey string, mint string, amount uint64) {
+	if m == nil {
+		return
+	}
+	m.balance.WithLabelValues(network, pubkey, mint).Set(float64(amount))
+}
+
+// rpcTransport 从请求体中读取 JSON-RPC 方法名，记录调用次数、延迟和 span
+type rpcTransport struct {
+	metrics  *Metrics // 可为空
+	endpoint string
+	next     http.RoundTripper
+}
+
+func (t *rpcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := "unknown"
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		var payload struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(body, &payload) == nil && payload.Method != "" {
+			method = payload.Method
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	ctx, span := startSpan(req.Context(), "rpc."+method, attrRPCMethod.String(method), attrEndpoint.String(t.endpoint))
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	elapsed := time.Since(start)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	endSpan(span, err)
+
+	if t.metrics != nil {
+		t.metrics.rpcDuration.WithLabelValues(method, t.endpoint).Observe(elapsed.Seconds())
+		t.metrics.rpcRequests.WithLabelValues(method, t.endpoint, code).Inc()
+	}
+	return resp, err
+}
+
+// endpointLabel 只保留 RPC 地址的主机名，避免 API key 出现在指标标签中
+func endpointLabel(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
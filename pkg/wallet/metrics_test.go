@@ -0,0 +1,105 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	defer func(d time.Duration) { confirmPollInterval = d }(confirmPollInterval)
+	confirmPollInterval = 10 * time.Millisecond
+
+	stub, _ := newRPCStub(t)
+	var sentTx string
+	stub.on("getBalance", func([]json.RawMessage) any { return withContext(uint64(1_000_000_000)) })
+	stub.on("getLatestBlockhash", func([]json.RawMessage) any {
+		return withContext(map[string]any{"blockhash": testBlockhash, "lastValidBlockHeight": 100})
+	})
+	stub.on("sendTransaction", func(params []json.RawMessage) any {
+		_ = json.Unmarshal(params[0], &sentTx)
+		return "5Sig"
+	})
+	stub.on("getSignatureStatuses", func([]json.RawMessage) any {
+		// 第一次查询时尚未确认
+		if stub.callCount("getSignatureStatuses") == 1 {
+			return withContext([]any{nil})
+		}
+		return withContext([]any{map[string]any{"slot": 10, "confirmations": 0, "confirmationStatus": "confirmed", "err": nil}})
+	})
+	stub.on("getTransaction", func([]json.RawMessage) any {
+		return map[string]any{
+			"slot":        10,
+			"meta":        map[string]any{"fee": 5000, "err": nil, "preBalances": []int{}, "postBalances": []int{}},
+			"transaction": []string{sentTx, "base64"},
+		}
+	})
+
+	m := NewMetrics(nil)
+	account := types.NewAccount()
+	wm := &WalletManager{Client: m.Client(stub.url), Network: "devnet", Account: account, Metrics: m, Logger: NewLogger(io.Discard, nil)}
+
+	txhash, err := wm.TransferSOL(context.Background(), types.NewAccount().PublicKey.ToBase58(), 1000)
+	require.NoError(t, err)
+	require.NoError(t, wm.WaitForConfirmation(context.Background(), txhash))
+
+	endpoint := endpointLabel(stub.url)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.rpcRequests.WithLabelValues("getBalance", endpoint, "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.rpcRequests.WithLabelValues("sendTransaction", endpoint, "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.rpcRequests.WithLabelValues("getSignatureStatuses", endpoint, "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.txSent.WithLabelValues("devnet", "transfer_sol", "ok")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.txConfirmed.WithLabelValues("devnet", "confirmed")))
+	assert.Equal(t, 5000.0, testutil.ToFloat64(m.feesPaid.WithLabelValues("devnet")))
+	assert.Equal(t, 1e9, testutil.ToFloat64(m.balance.WithLabelValues("devnet", account.PublicKey.ToBase58(), SOL_MINT_ADDR)))
+
+	// /metrics 输出中不包含完整的 RPC 地址
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	assert.Contains(t, body, "solana_transaction_confirmation_seconds_count{network=\"devnet\"} 1")
+	assert.Contains(t, body, "solana_rpc_request_duration_seconds")
+	assert.NotContains(t, body, "http://")
+}
+
+func TestWaitForConfirmationFailed(t *testing.T) {
+	stub, c := newRPCStub(t)
+	var txErr any = map[string]any{"InstructionError": []any{0, map[string]any{"Custom": 1}}}
+	stub.on("getSignatureStatuses", func([]json.RawMessage) any {
+		return withContext([]any{map[string]any{"slot": 10, "confirmationStatus": "processed", "err": txErr}})
+	})
+	tx := newUnsignedTransfer(t, types.NewAccount(), types.NewAccount())
+	encoded, err := EncodeTransaction(tx, EncodingBase64)
+	require.NoError(t, err)
+	stub.on("getTransaction", func([]json.RawMessage) any {
+		return map[string]any{
+			"slot":        10,
+			"meta":        map[string]any{"fee": 5000, "err": txErr, "preBalances": []int{}, "postBalances": []int{}},
+			"transaction": []string{encoded, "base64"},
+		}
+	})
+	m := NewMetrics(nil)
+	wm := &WalletManager{Client: c, Network: "devnet", Metrics: m, Logger: NewLogger(io.Discard, nil)}
+
+	err = wm.WaitForConfirmation(context.Background(), "5Sig")
+	var programErr *ProgramError
+	assert.ErrorAs(t, err, &programErr)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.txConfirmed.WithLabelValues("devnet", "failed")))
+	// 执行失败的交易也扣了手续费
+	assert.Equal(t, 5000.0, testutil.ToFloat64(m.feesPaid.WithLabelValues("devnet")))
+
+	// 无法解析的错误不能变成 nil
+	txErr = 42
+	err = wm.WaitForConfirmation(context.Background(), "5Sig")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "unrecognized transaction error 42")
+	assert.NotNil(t, errors.Unwrap(err))
+}
@@ -0,0 +1,109 @@
+package wallet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/pkg/hdwallet"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// SolanaDerivationPath Solana 标准 BIP44 派生路径（Phantom、Solflare、solana-keygen 的 prompt://?key=n/0），
+// %d 为账户序号
+const SolanaDerivationPath = "m/44'/501'/%d'/0'"
+
+// NewMnemonic 生成 12 或 24 个单词的 BIP39 助记词
+func NewMnemonic(words int) (string, error) {
+	var bitSize int
+	switch words {
+	case 12:
+		bitSize = 128
+	case 24:
+		bitSize = 256
+	default:
+		return "", fmt.Errorf("unsupported mnemonic length %d, use 12 or 24 words", words)
+	}
+
+	entropy, err := bip39.NewEntropy(bitSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate mnemonic: %w", err)
+	}
+	return mnemonic, nil
+}
+
+// ValidateMnemonic 校验助记词的单词表和校验和
+func ValidateMnemonic(mnemonic string) error {
+	mnemonic = normalizeMnemonic(mnemonic)
+	words := len(strings.Fields(mnemonic))
+	if words != 12 && words != 24 {
+		return fmt.Errorf("mnemonic must have 12 or 24 words, got %d", words)
+	}
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return fmt.Errorf("invalid mnemonic: unknown word or bad checksum")
+	}
+	return nil
+}
+
+// DerivationPath 返回第 index 个账户的派生路径
+func DerivationPath(index uint32) string {
+	return fmt.Sprintf(SolanaDerivationPath, index)
+}
+
+// AccountFromMnemonic 按 SLIP-0010 从助记词派生第 index 个账户（m/44'/501'/index'/0'），
+// 与 Phantom 等钱包导入同一助记词得到的地址一致
+func AccountFromMnemonic(mnemonic string, passphrase string, index uint32) (types.Account, error) {
+	return AccountFromMnemonicPath(mnemonic, passphrase, DerivationPath(index))
+}
+
+// AccountFromMnemonicPath 按指定路径从助记词派生账户。
+// path 为空时与 solana-keygen 默认行为一致，直接使用种子的前 32 字节，不做派生。
+func AccountFromMnemonicPath(mnemonic string, passphrase string, path string) (types.Account, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return types.Account{}, err
+	}
+	seed := bip39.NewSeed(normalizeMnemonic(mnemonic), passphrase)
+	if path == "" {
+		return types.AccountFromSeed(seed[:32])
+	}
+
+	derivedKey, err := hdwallet.Derived(path, seed)
+	if err != nil {
+		return types.Account{}, fmt.Errorf("failed to derive key for path %s: %w", path, err)
+	}
+	return types.AccountFromSeed(derivedKey.PrivateKey)
+}
+
+// DeriveAccounts 从助记词批量派生序号 [start, start+count) 的账户
+func DeriveAccounts(mnemonic string, passphrase string, start uint32, count int) ([]types.Account, error) {
+	accounts := make([]types.Account, 0, count)
+	for i := 0; i < count; i++ {
+		account, err := AccountFromMnemonic(mnemonic, passphrase, start+uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// LoadMnemonic 从助记词派生第 index 个账户并设为当前账户
+func (wm *WalletManager) LoadMnemonic(mnemonic string, passphrase string, index uint32) (string, error) {
+	account, err := AccountFromMnemonic(mnemonic, passphrase, index)
+	if err != nil {
+		return "", err
+	}
+
+	wm.Account = account
+	wm.Signer = NewAccountSigner(account)
+	return account.PublicKey.ToBase58(), nil
+}
+
+// normalizeMnemonic 统一小写并合并多余空白
+func normalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
@@ -0,0 +1,48 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountFromMnemonic(t *testing.T) {
+	// Phantom 派生路径，期望值与 Phantom 导入同一助记词得到的地址一致
+	mnemonic := "neither lonely flavor argue grass remind eye tag avocado spot unusual intact"
+	expected := []string{
+		"5vftMkHL72JaJG6ExQfGAsT2uGVHpRR7oTNUPMs68Y2N",
+		"GcXbfQ5yY3uxCyBNDPBbR5FjumHf89E7YHXuULfGDBBv",
+		"7QPgyQwNLqnoSwHEuK8wKy2Y3Ani6EHoZRihTuWkwxbc",
+	}
+
+	accounts, err := DeriveAccounts(mnemonic, "", 0, len(expected))
+	require.NoError(t, err)
+	for i, account := range accounts {
+		assert.Equal(t, expected[i], account.PublicKey.ToBase58(), DerivationPath(uint32(i)))
+	}
+
+	// 大小写和多余空白不影响结果
+	account, err := AccountFromMnemonic("  "+strings.ToUpper(mnemonic)+" ", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, expected[0], account.PublicKey.ToBase58())
+
+	// passphrase 会得到不同的账户
+	account, err = AccountFromMnemonic(mnemonic, "passphrase", 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, expected[0], account.PublicKey.ToBase58())
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, words := range []int{12, 24} {
+		mnemonic, err := NewMnemonic(words)
+		require.NoError(t, err)
+		assert.Len(t, strings.Fields(mnemonic), words)
+		assert.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	_, err := NewMnemonic(15)
+	assert.Error(t, err)
+	assert.Error(t, ValidateMnemonic("neither lonely flavor argue grass remind eye tag avocado spot unusual unusual"))
+}
@@ -0,0 +1,228 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// MultisigInfo SPL Token 原生多签账户信息
+type MultisigInfo struct {
+	Address     string
+	MinRequired uint8
+	Signers     []string
+}
+
+// MultisigTransferParam 以多签账户为权限的代币转账参数
+type MultisigTransferParam struct {
+	FeePayer      string   // 手续费支付账户
+	Mint          string   // mint 地址
+	FromTokenAddr string   // 转出的代币账户，owner 为多签账户
+	ToTokenAddr   string   // 转入的代币账户
+	Multisig      string   // 多签账户地址
+	Signers       []string // 参与本次签名的多签成员，数量不少于 MinRequired
+	Amount        uint64
+	Decimals      uint8
+}
+
+// CreateMultisig 创建 SPL Token 原生多签账户（m-of-n），返回多签账户地址和交易哈希。
+// 多签账户可以作为 mint 权限或代币账户的 owner。
+func (wm *WalletManager) CreateMultisig(ctx context.Context, signers []string, minRequired uint8, opts ...TxOption) (_ string, _ string, err error) {
+	ctx, span := wm.startOperation(ctx, "CreateMultisig")
+	defer func() { endSpan(span, err) }()
+
+	if len(signers) < 1 || len(signers) > token.MaxSigners {
+		return "", "", fmt.Errorf("multisig needs 1 to %d signers, got %d", token.MaxSigners, len(signers))
+	}
+	if minRequired < 1 || int(minRequired) > len(signers) {
+		return "", "", fmt.Errorf("invalid min required signers %d for %d signers", minRequired, len(signers))
+	}
+
+	// 重复的成员会让 m-of-n 实际少于 n 个不同的签名者
+	signerPubkeys := make([]common.PublicKey, 0, len(signers))
+	seen := make(map[common.PublicKey]bool, len(signers))
+	for _, s := range signers {
+		pubkey, err := ParseAddress(s)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid multisig signer: %w", err)
+		}
+		if seen[pubkey] {
+			return "", "", fmt.Errorf("duplicate multisig signer %s", s)
+		}
+		seen[pubkey] = true
+		signerPubkeys = append(signerPubkeys, pubkey)
+	}
+
+	multisig := types.NewAccount()
+	rentExemptionBalance, err := wm.Client.GetMinimumBalanceForRentExemption(ctx, token.MultisigAccountSize)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get min balance for rent exemption: %w", wrapRPCError(err, nil))
+	}
+	initInstruction := token.InitializeMultisig(token.InitializeMultisigParam{
+		Account:     multisig.PublicKey,
+		Signers:     signerPubkeys,
+		MinRequired: minRequired,
+	})
+	// 初始化多签账户时成员不需要签名，SDK 将成员标记为签名者，这里改回只读账户
+	for i := 2; i < len(initInstruction.Accounts); i++ {
+		initInstruction.Accounts[i].IsSigner = false
+	}
+
+	payer := wm.signer()
+	instructions := []types.Instruction{
+		system.CreateAccount(system.CreateAccountParam{
+			From:     payer.PublicKey(),
+			New:      multisig.PublicKey,
+			Owner:    common.TokenProgramID,
+			Lamports: rentExemptionBalance,
+			Space:    token.MultisigAccountSize,
+		}),
+		initInstruction,
+	}
+	if err := wm.ensurePayerRentExempt(ctx, rentExemptionBalance, instructions, opts); err != nil {
+		return "", "", err
+	}
+	tx, err := buildTransaction(ctx, wm.Client, instructions, []Signer{payer, NewAccountSigner(multisig)}, wm.policyOpts("create_multisig", opts)...)
+	if err != nil {
+		return "", "", err
+	}
+
+	txhash, err := wm.sendTransaction(ctx, "create_multisig", tx, "multisig", multisig.PublicKey.ToBase58(), "minRequired", minRequired)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return multisig.PublicKey.ToBase58(), txhash, nil
+}
+
+// GetMultisig 查询多签账户的成员和签名门槛
+func (wm *WalletManager) GetMultisig(ctx context.Context, address string) (*MultisigInfo, error) {
+	accountInfo, err := getAccountInfo(ctx, wm.Client, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get multisig account %s: %w", address, err)
+	}
+	if accountInfo.Owner != common.TokenProgramID {
+		return nil, fmt.Errorf("%s is not owned by the token program", address)
+	}
+	multisig, err := token.MultisigAccountFromData(accountInfo.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse multisig account %s: %w", address, err)
+	}
+	if !multisig.IsInitialized {
+		return nil, fmt.Errorf("multisig account %s is not initialized", address)
+	}
+
+	info := &MultisigInfo{Address: address, MinRequired: multisig.M}
+	for _, s := range multisig.Signers {
+		info.Signers = append(info.Signers, s.ToBase58())
+	}
+	return info, nil
+}
+
+// BuildMultisigTokenTransfer 组装以多签账户为权限的未签名代币转账交易。
+// 交易需要手续费支付账户和 param.Signers 全部签名，各方分别调用 SignTransaction 后用 MergeSignatures 合并。
+func (wm *WalletManager) BuildMultisigTokenTransfer(ctx context.Context, param MultisigTransferParam, opts ...TxOption) (_ types.Transaction, err error) {
+	ctx, span := wm.startOperation(ctx, "BuildMultisigTokenTransfer", attrMint.String(param.Mint), attrAmount.Int64(int64(param.Amount)))
+	defer func() { endSpan(span, err) }()
+
+	multisig, err := wm.GetMultisig(ctx, param.Multisig)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	members := make(map[string]bool, len(multisig.Signers))
+	for _, s := range multisig.Signers {
+		members[s] = true
+	}
+	// 同一成员签两次只算一个签名，链上程序会拒绝签名数不足的交易
+	signerPubkeys := make([]common.PublicKey, 0, len(param.Signers))
+	seen := make(map[common.PublicKey]bool, len(param.Signers))
+	for _, s := range param.Signers {
+		pubkey, err := ParseAddress(s)
+		if err != nil {
+			return types.Transaction{}, fmt.Errorf("invalid multisig signer: %w", err)
+		}
+		if seen[pubkey] {
+			return types.Transaction{}, fmt.Errorf("duplicate multisig signer %s", s)
+		}
+		seen[pubkey] = true
+		if !members[pubkey.ToBase58()] {
+			return types.Transaction{}, fmt.Errorf("%s is not a member of multisig %s", s, param.Multisig)
+		}
+		signerPubkeys = append(signerPubkeys, pubkey)
+	}
+	if len(signerPubkeys) < int(multisig.MinRequired) {
+		return types.Transaction{}, fmt.Errorf("multisig %s requires %d signers, got %d",
+			param.Multisig, multisig.MinRequired, len(signerPubkeys))
+	}
+
+	accounts := make([]common.PublicKey, 4)
+	for i, field := range []struct{ name, address string }{
+		{"source token account", param.FromTokenAddr},
+		{"destination token account", param.ToTokenAddr},
+		{"mint", param.Mint},
+		{"multisig", param.Multisig},
+	} {
+		if accounts[i], err = ParseAddress(field.address); err != nil {
+			return types.Transaction{}, fmt.Errorf("invalid %s: %w", field.name, err)
+		}
+	}
+
+	return wm.BuildUnsignedTransaction(ctx, param.FeePayer, []types.Instruction{
+		token.TransferChecked(token.TransferCheckedParam{
+			From:     accounts[0],
+			To:       accounts[1],
+			Mint:     accounts[2],
+			Auth:     accounts[3],
+			Signers:  signerPubkeys,
+			Amount:   param.Amount,
+			Decimals: param.Decimals,
+		}),
+	}, opts...)
+}
+
+// MergeSignatures 合并多方对同一交易的部分签名。
+// 所有交易的消息必须完全一致，每个签名都会校验，同一槽位的不同签名视为冲突。
+func MergeSignatures(txs ...types.Transaction) (types.Transaction, error) {
+	if len(txs) == 0 {
+		return types.Transaction{}, fmt.Errorf("no transactions to merge")
+	}
+
+	merged := txs[0]
+	merged.Signatures = make([]types.Signature, len(txs[0].Signatures))
+	copy(merged.Signatures, txs[0].Signatures)
+	baseHash, err := MessageHash(merged)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	if _, err := VerifyTransaction(merged); err != nil {
+		return types.Transaction{}, err
+	}
+
+	for i, tx := range txs[1:] {
+		hash, err := MessageHash(tx)
+		if err != nil {
+			return types.Transaction{}, err
+		}
+		if hash != baseHash {
+			return types.Transaction{}, fmt.Errorf("transaction %d has a different message: %s != %s", i+1, hash, baseHash)
+		}
+		if _, err := VerifyTransaction(tx); err != nil {
+			return types.Transaction{}, fmt.Errorf("transaction %d: %w", i+1, err)
+		}
+
+		for j, sig := range tx.Signatures {
+			if bytes.Equal(sig, emptySignature) {
+				continue
+			}
+			if !bytes.Equal(merged.Signatures[j], emptySignature) && !bytes.Equal(merged.Signatures[j], sig) {
+				return types.Transaction{}, fmt.Errorf("conflicting signatures for %s", merged.Message.Accounts[j].ToBase58())
+			}
+			merged.Signatures[j] = sig
+		}
+	}
+	return merged, nil
+}
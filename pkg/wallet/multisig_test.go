@@ -0,0 +1,137 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func multisigAccountData(m uint8, signers ...common.PublicKey) []byte {
+	data := make([]byte, token.MultisigAccountSize)
+	data[0] = m
+	data[1] = uint8(len(signers))
+	data[2] = 1
+	for i, s := range signers {
+		copy(data[3+32*i:], s.Bytes())
+	}
+	return data
+}
+
+func TestMultisigTokenTransferPartialSigning(t *testing.T) {
+	feePayer := types.NewAccount()
+	alice := types.NewAccount()
+	bob := types.NewAccount()
+	carol := types.NewAccount()
+	multisig := types.NewAccount().PublicKey
+
+	stub, c := newRPCStub(t)
+	stub.on("getAccountInfo", func(params []json.RawMessage) any {
+		return withContext(accountInfo(common.TokenProgramID.ToBase58(), 2463840,
+			multisigAccountData(2, alice.PublicKey, bob.PublicKey, carol.PublicKey)))
+	})
+	stub.on("getLatestBlockhash", func(params []json.RawMessage) any {
+		return withContext(map[string]any{"blockhash": types.NewAccount().PublicKey.ToBase58(), "lastValidBlockHeight": 100})
+	})
+	wm := &WalletManager{Client: c}
+
+	param := MultisigTransferParam{
+		FeePayer:      feePayer.PublicKey.ToBase58(),
+		Mint:          types.NewAccount().PublicKey.ToBase58(),
+		FromTokenAddr: types.NewAccount().PublicKey.ToBase58(),
+		ToTokenAddr:   types.NewAccount().PublicKey.ToBase58(),
+		Multisig:      multisig.ToBase58(),
+		Signers:       []string{alice.PublicKey.ToBase58(), carol.PublicKey.ToBase58()},
+		Amount:        1000,
+		Decimals:      6,
+	}
+	tx, err := wm.BuildMultisigTokenTransfer(context.Background(), param)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, tx.Message.Header.NumRequireSignatures)
+
+	// 各方在自己的机器上分别签名
+	sign := func(signer types.Account) types.Transaction {
+		encoded, err := EncodeTransaction(tx, EncodingBase64)
+		require.NoError(t, err)
+		copyTx, err := DecodeTransaction(encoded, EncodingBase64)
+		require.NoError(t, err)
+		require.NoError(t, SignTransaction(context.Background(), &copyTx, NewAccountSigner(signer)))
+		return copyTx
+	}
+	partials := []types.Transaction{sign(feePayer), sign(alice), sign(carol)}
+
+	merged, err := MergeSignatures(partials[:2]...)
+	require.NoError(t, err)
+	missing, err := VerifyTransaction(merged)
+	require.NoError(t, err)
+	assert.Equal(t, []string{carol.PublicKey.ToBase58()}, missing)
+
+	merged, err = MergeSignatures(append([]types.Transaction{merged}, partials[2])...)
+	require.NoError(t, err)
+	missing, err = VerifyTransaction(merged)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	t.Run("not enough signers", func(t *testing.T) {
+		p := param
+		p.Signers = []string{alice.PublicKey.ToBase58()}
+		_, err := wm.BuildMultisigTokenTransfer(context.Background(), p)
+		assert.Error(t, err)
+	})
+
+	t.Run("non member", func(t *testing.T) {
+		p := param
+		p.Signers = []string{alice.PublicKey.ToBase58(), feePayer.PublicKey.ToBase58()}
+		_, err := wm.BuildMultisigTokenTransfer(context.Background(), p)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "is not a member")
+	})
+
+	t.Run("duplicate signer", func(t *testing.T) {
+		p := param
+		p.Signers = []string{alice.PublicKey.ToBase58(), alice.PublicKey.ToBase58()}
+		_, err := wm.BuildMultisigTokenTransfer(context.Background(), p)
+		assert.ErrorContains(t, err, "duplicate multisig signer")
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		for _, mutate := range []func(*MultisigTransferParam){
+			func(p *MultisigTransferParam) { p.FromTokenAddr = "bad" },
+			func(p *MultisigTransferParam) { p.ToTokenAddr = "bad" },
+			func(p *MultisigTransferParam) { p.Mint = "bad" },
+			func(p *MultisigTransferParam) { p.FeePayer = "bad" },
+		} {
+			p := param
+			mutate(&p)
+			_, err := wm.BuildMultisigTokenTransfer(context.Background(), p)
+			assert.ErrorIs(t, err, ErrInvalidAddress)
+		}
+	})
+}
+
+func TestCreateMultisigRejectsDuplicateSigners(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	alice := types.NewAccount().PublicKey.ToBase58()
+	bob := types.NewAccount().PublicKey.ToBase58()
+
+	_, _, err := wm.CreateMultisig(context.Background(), []string{alice, bob, alice}, 2)
+	assert.ErrorContains(t, err, "duplicate multisig signer "+alice)
+	_, _, err = wm.CreateMultisig(context.Background(), []string{alice, "bad"}, 1)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Zero(t, stub.callCount("getMinimumBalanceForRentExemption"))
+}
+
+func TestMergeSignaturesRejectsDifferentMessages(t *testing.T) {
+	from := types.NewAccount()
+	a := newUnsignedTransfer(t, from, types.NewAccount())
+	b := newUnsignedTransfer(t, from, types.NewAccount())
+
+	_, err := MergeSignatures(a, b)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "different message")
+}
@@ -0,0 +1,198 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// nonce 账户状态，0 为未初始化，1 为已初始化
+const nonceStateInitialized = 1
+
+// NonceInfo 持久 nonce 账户信息
+type NonceInfo struct {
+	Address              string
+	Authority            string
+	Nonce                string
+	LamportsPerSignature uint64
+}
+
+// CreateNonceAccount 创建并初始化持久 nonce 账户，返回 nonce 账户地址和交易哈希。
+// authority 为空时使用当前账户作为 nonce 权限账户。
+func (wm *WalletManager) CreateNonceAccount(ctx context.Context, authority string, opts ...TxOption) (_ string, _ string, err error) {
+	ctx, span := wm.startOperation(ctx, "CreateNonceAccount")
+	defer func() { endSpan(span, err) }()
+
+	signer := wm.signer()
+	authPubkey := signer.PublicKey()
+	if authority != "" {
+		if authPubkey, err = ParseAddress(authority); err != nil {
+			return "", "", fmt.Errorf("invalid nonce authority: %w", err)
+		}
+	}
+
+	nonceAccount := types.NewAccount()
+	rentExemptionBalance, err := wm.Client.GetMinimumBalanceForRentExemption(ctx, system.NonceAccountSize)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get min balance for rent exemption: %w", wrapRPCError(err, nil))
+	}
+	instructions := []types.Instruction{
+		system.CreateAccount(system.CreateAccountParam{
+			From:     signer.PublicKey(),
+			New:      nonceAccount.PublicKey,
+			Owner:    common.SystemProgramID,
+			Lamports: rentExemptionBalance,
+			Space:    system.NonceAccountSize,
+		}),
+		system.InitializeNonceAccount(system.InitializeNonceAccountParam{
+			Nonce: nonceAccount.PublicKey,
+			Auth:  authPubkey,
+		}),
+	}
+	if err := wm.ensurePayerRentExempt(ctx, rentExemptionBalance, instructions, opts); err != nil {
+		return "", "", err
+	}
+
+	tx, err := buildTransaction(ctx, wm.Client, instructions, []Signer{signer, NewAccountSigner(nonceAccount)}, wm.policyOpts("create_nonce_account", opts)...)
+	if err != nil {
+		return "", "", err
+	}
+
+	txhash, err := wm.sendTransaction(ctx, "create_nonce_account", tx, "nonceAccount", nonceAccount.PublicKey.ToBase58())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return nonceAccount.PublicKey.ToBase58(), txhash, nil
+}
+
+// GetNonce 查询 nonce 账户当前保存的 nonce 和权限账户
+func (wm *WalletManager) GetNonce(ctx context.Context, nonceAccount string) (*NonceInfo, error) {
+	return getNonceAccount(ctx, wm.Client, nonceAccount)
+}
+
+// AdvanceNonce 推进 nonce，使之前用该 nonce 签名但未上链的交易全部失效
+func (wm *WalletManager) AdvanceNonce(ctx context.Context, nonceAccount string, opts ...TxOption) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "AdvanceNonce")
+	defer func() { endSpan(span, err) }()
+
+	noncePubkey, err := ParseAddress(nonceAccount)
+	if err != nil {
+		return "", fmt.Errorf("invalid nonce account: %w", err)
+	}
+	tx, err := buildTransaction(ctx, wm.Client, []types.Instruction{
+		system.AdvanceNonceAccount(system.AdvanceNonceAccountParam{
+			Nonce: noncePubkey,
+			Auth:  wm.PublicKey(),
+		}),
+	}, []Signer{wm.signer()}, wm.policyOpts("advance_nonce", opts)...)
+	if err != nil {
+		return "", err
+	}
+
+	txhash, err := wm.sendTransaction(ctx, "advance_nonce", tx, "nonceAccount", nonceAccount)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return txhash, nil
+}
+
+// WithdrawNonce 从 nonce 账户提取 lamports，提取全部余额即关闭该账户
+func (wm *WalletManager) WithdrawNonce(ctx context.Context, nonceAccount string, toAddress string, amount uint64, opts ...TxOption) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "WithdrawNonce", attrAmount.Int64(int64(amount)))
+	defer func() { endSpan(span, err) }()
+
+	noncePubkey, err := ParseAddress(nonceAccount)
+	if err != nil {
+		return "", fmt.Errorf("invalid nonce account: %w", err)
+	}
+	to, err := wm.resolveRecipient(ctx, "withdraw_nonce", toAddress)
+	if err != nil {
+		return "", err
+	}
+
+	// nonce 账户要么全部提取（关闭），要么保留免租金额
+	balance, err := wm.Client.GetBalance(ctx, nonceAccount)
+	if err != nil {
+		return "", fmt.Errorf("failed to get nonce account balance: %w", wrapRPCError(err, nil))
+	}
+	if err := wm.ensureRentExempt(ctx, nonceAccount, balance, amount, system.NonceAccountSize); err != nil {
+		return "", err
+	}
+
+	tx, err := buildTransaction(ctx, wm.Client, []types.Instruction{
+		system.WithdrawNonceAccount(system.WithdrawNonceAccountParam{
+			Nonce:  noncePubkey,
+			Auth:   wm.PublicKey(),
+			To:     to,
+			Amount: amount,
+		}),
+	}, []Signer{wm.signer()}, wm.policyOpts("withdraw_nonce", opts)...)
+	if err != nil {
+		return "", err
+	}
+
+	txhash, err := wm.sendTransaction(ctx, "withdraw_nonce", tx, "nonceAccount", nonceAccount)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return txhash, nil
+}
+
+// AuthorizeNonce 将 nonce 账户的权限转移给 newAuthority
+func (wm *WalletManager) AuthorizeNonce(ctx context.Context, nonceAccount string, newAuthority string, opts ...TxOption) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "AuthorizeNonce")
+	defer func() { endSpan(span, err) }()
+
+	noncePubkey, err := ParseAddress(nonceAccount)
+	if err != nil {
+		return "", fmt.Errorf("invalid nonce account: %w", err)
+	}
+	newAuth, err := ParseAddress(newAuthority)
+	if err != nil {
+		return "", fmt.Errorf("invalid nonce authority: %w", err)
+	}
+	tx, err := buildTransaction(ctx, wm.Client, []types.Instruction{
+		system.AuthorizeNonceAccount(system.AuthorizeNonceAccountParam{
+			Nonce:   noncePubkey,
+			Auth:    wm.PublicKey(),
+			NewAuth: newAuth,
+		}),
+	}, []Signer{wm.signer()}, wm.policyOpts("authorize_nonce", opts)...)
+	if err != nil {
+		return "", err
+	}
+
+	txhash, err := wm.sendTransaction(ctx, "authorize_nonce", tx, "nonceAccount", nonceAccount)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return txhash, nil
+}
+
+// getNonceAccount 读取并校验 nonce 账户
+func getNonceAccount(ctx context.Context, c *client.Client, nonceAccount string) (*NonceInfo, error) {
+	accountInfo, err := getAccountInfo(ctx, c, nonceAccount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce account %s: %w", nonceAccount, err)
+	}
+	if accountInfo.Owner != common.SystemProgramID {
+		return nil, fmt.Errorf("%s is not owned by the system program", nonceAccount)
+	}
+	account, err := system.NonceAccountDeserialize(accountInfo.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nonce account %s: %w", nonceAccount, err)
+	}
+	if account.State != nonceStateInitialized {
+		return nil, fmt.Errorf("nonce account %s is not initialized", nonceAccount)
+	}
+	return &NonceInfo{
+		Address:              nonceAccount,
+		Authority:            account.AuthorizedPubkey.ToBase58(),
+		Nonce:                account.Nonce.ToBase58(),
+		LamportsPerSignature: account.FeeCalculator.LamportsPerSignature,
+	}, nil
+}
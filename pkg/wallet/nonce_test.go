@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nonceAccountData(authority common.PublicKey, nonce common.PublicKey) []byte {
+	data := make([]byte, system.NonceAccountSize)
+	binary.LittleEndian.PutUint32(data[0:4], 1)
+	binary.LittleEndian.PutUint32(data[4:8], nonceStateInitialized)
+	copy(data[8:40], authority.Bytes())
+	copy(data[40:72], nonce.Bytes())
+	binary.LittleEndian.PutUint64(data[72:80], 5000)
+	return data
+}
+
+func TestBuildTransactionWithDurableNonce(t *testing.T) {
+	payer := types.NewAccount()
+	nonceAccount := types.NewAccount().PublicKey
+	nonceValue := types.NewAccount().PublicKey
+
+	stub, c := newRPCStub(t)
+	stub.on("getAccountInfo", func(params []json.RawMessage) any {
+		return withContext(accountInfo(common.SystemProgramID.ToBase58(), 1447680, nonceAccountData(payer.PublicKey, nonceValue)))
+	})
+
+	tx, err := buildTransaction(context.Background(), c, []types.Instruction{
+		system.Transfer(system.TransferParam{From: payer.PublicKey, To: nonceValue, Amount: 1}),
+	}, []Signer{NewAccountSigner(payer)}, WithDurableNonce(nonceAccount.ToBase58(), nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, nonceValue.ToBase58(), tx.Message.RecentBlockHash)
+	assert.Equal(t, 0, stub.callCount("getLatestBlockhash"))
+
+	instructions := tx.Message.DecompileInstructions()
+	require.Len(t, instructions, 2)
+	advance := system.AdvanceNonceAccount(system.AdvanceNonceAccountParam{
+		Nonce: nonceAccount,
+		Auth:  payer.PublicKey,
+	})
+	assert.Equal(t, advance.ProgramID, instructions[0].ProgramID)
+	assert.Equal(t, advance.Data, instructions[0].Data)
+	assert.Equal(t, nonceAccount, instructions[0].Accounts[0].PubKey)
+}
+
+func TestBuildTransactionNonceAuthorityMismatch(t *testing.T) {
+	payer := types.NewAccount()
+	other := types.NewAccount()
+
+	stub, c := newRPCStub(t)
+	stub.on("getAccountInfo", func(params []json.RawMessage) any {
+		return withContext(accountInfo(common.SystemProgramID.ToBase58(), 1447680, nonceAccountData(other.PublicKey, other.PublicKey)))
+	})
+
+	_, err := buildTransaction(context.Background(), c, []types.Instruction{
+		system.Transfer(system.TransferParam{From: payer.PublicKey, To: other.PublicKey, Amount: 1}),
+	}, []Signer{NewAccountSigner(payer)}, WithDurableNonce(types.NewAccount().PublicKey.ToBase58(), nil))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "nonce authority mismatch")
+}
+
+func TestNonceRejectsInvalidAddresses(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	nonceAccount := types.NewAccount().PublicKey.ToBase58()
+
+	_, err := wm.WithdrawNonce(context.Background(), nonceAccount, "not-an-address", 1000)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = wm.WithdrawNonce(context.Background(), "bad", wm.PublicKey().ToBase58(), 1000)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Zero(t, stub.callCount("getBalance"))
+
+	_, err = wm.AdvanceNonce(context.Background(), "bad")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = wm.AuthorizeNonce(context.Background(), "bad", wm.PublicKey().ToBase58())
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = wm.AuthorizeNonce(context.Background(), nonceAccount, "bad")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	_, _, err = wm.CreateNonceAccount(context.Background(), "bad")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = wm.TransferSOL(context.Background(), wm.PublicKey().ToBase58(), 1, WithDurableNonce("bad", nil))
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Zero(t, stub.callCount("sendTransaction"))
+}
+
+func TestAdvanceNonceAppliesOptions(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	nonceAccount := types.NewAccount().PublicKey.ToBase58()
+
+	_, err := wm.AdvanceNonce(context.Background(), nonceAccount, WithPriorityFee(1000, 0), WithMemo("rotate"))
+	require.NoError(t, err)
+	tx := sentTransaction(t, stub, 0)
+	assert.Equal(t, []string{"rotate"}, MessageMemos(tx.Message))
+	instructions := tx.Message.DecompileInstructions()
+	require.Len(t, instructions, 3)
+	assert.Equal(t, common.ComputeBudgetProgramID, instructions[0].ProgramID)
+	assert.Equal(t, common.SystemProgramID, instructions[1].ProgramID)
+}
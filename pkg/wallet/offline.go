@@ -0,0 +1,311 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// 离线交易的序列化编码
+const (
+	EncodingBase64 = "base64"
+	EncodingBase58 = "base58"
+)
+
+// emptySignature 未签名的签名槽位
+var emptySignature = make([]byte, ed25519.SignatureSize)
+
+// BuildUnsignedTransaction 在联网机器上组装未签名交易，签名槽位全部留空。
+// 只需要手续费支付账户的公钥，私钥可以保存在离线机器上。设置了 Policy 时组装前执行策略检查。
+// 离线签名耗时较长时应配合 WithDurableNonce 使用，避免区块哈希过期。
+func (wm *WalletManager) BuildUnsignedTransaction(
+	ctx context.Context,
+	feePayer string,
+	instructions []types.Instruction,
+	opts ...TxOption,
+) (_ types.Transaction, err error) {
+	ctx, span := wm.startOperation(ctx, "BuildUnsignedTransaction")
+	defer func() { endSpan(span, err) }()
+
+	payer, err := ParseAddress(feePayer)
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("invalid fee payer: %w", err)
+	}
+	message, err := buildMessage(ctx, wm.Client, payer, instructions, newTxOptions(opts))
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	// 签名在离线机器上完成，组装时就执行策略并记录支出
+	if err := wm.checkMessagePolicy(ctx, "build_unsigned_transaction", message); err != nil {
+		return types.Transaction{}, err
+	}
+	return types.NewTransaction(types.NewTransactionParam{Message: message})
+}
+
+// BuildUnsignedTransferSOL 组装未签名的 SOL 转账交易
+func (wm *WalletManager) BuildUnsignedTransferSOL(
+	ctx context.Context,
+	fromAddress string,
+	toAddress string,
+	amount uint64,
+	opts ...TxOption,
+) (types.Transaction, error) {
+	from, err := ParseAddress(fromAddress)
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("invalid sender: %w", err)
+	}
+	to, err := ParseAddress(toAddress)
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("invalid recipient: %w", err)
+	}
+	return wm.BuildUnsignedTransaction(ctx, fromAddress, []types.Instruction{
+		system.Transfer(system.TransferParam{
+			From:   from,
+			To:     to,
+			Amount: amount,
+		}),
+	}, opts...)
+}
+
+// SendSignedTransaction 在联网机器上广播已签名的交易，发送前校验签名完整且与消息匹配，并执行策略检查
+func (wm *WalletManager) SendSignedTransaction(ctx context.Context, tx types.Transaction) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "SendSignedTransaction")
+	defer func() { endSpan(span, err) }()
+
+	missing, err := VerifyTransaction(tx)
+	if err != nil {
+		return "", err
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("transaction is missing signatures from: %s", strings.Join(missing, ", "))
+	}
+	// 交易可能不是由本钱包组装的，广播前再执行一次策略，组装时已记录的交易不重复计算
+	if !wm.policyRecorded(tx.Message) {
+		if err := wm.checkMessagePolicy(ctx, "send_signed_transaction", tx.Message); err != nil {
+			return "", err
+		}
+	}
+
+	txhash, err := wm.sendTransaction(ctx, "send_signed_transaction", tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return txhash, nil
+}
+
+// SignTransaction 用给定签名者为交易签名，只填充对应的签名槽位，其余签名保持不变
+func SignTransaction(ctx context.Context, tx *types.Transaction, signers ...Signer) error {
+	return signMessage(ctx, tx, signers...)
+}
+
+// VerifyTransaction 校验交易中已有的签名，返回尚未签名的账户列表。
+// 任何已有签名与消息不匹配（消息在签名后被篡改）都会返回错误。
+func VerifyTransaction(tx types.Transaction) ([]string, error) {
+	if err := validateMessageHeader(tx.Message); err != nil {
+		return nil, err
+	}
+	data, err := tx.Message.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize message: %w", err)
+	}
+	if len(tx.Signatures) != int(tx.Message.Header.NumRequireSignatures) {
+		return nil, fmt.Errorf("signature count mismatch: have %d, need %d",
+			len(tx.Signatures), tx.Message.Header.NumRequireSignatures)
+	}
+
+	var missing []string
+	for i, sig := range tx.Signatures {
+		signer := tx.Message.Accounts[i]
+		if bytes.Equal(sig, emptySignature) {
+			missing = append(missing, signer.ToBase58())
+			continue
+		}
+		if !ed25519.Verify(signer.Bytes(), data, sig) {
+			return nil, fmt.Errorf("invalid signature from %s: message may have been altered", signer.ToBase58())
+		}
+	}
+	return missing, nil
+}
+
+// MessageHash 返回交易消息的 SHA-256 摘要（base58），
+// 构建端和签名端分别展示该摘要，由操作员核对消息在传递过程中未被修改
+func MessageHash(tx types.Transaction) (string, error) {
+	data, err := tx.Message.Serialize()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize message: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return base58.Encode(sum[:]), nil
+}
+
+// EncodeTransaction 将交易序列化为 base64 或 base58 字符串，便于在机器之间传递
+func EncodeTransaction(tx types.Transaction, encoding string) (string, error) {
+	data, err := tx.Serialize()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+	switch encoding {
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(data), nil
+	case EncodingBase58:
+		return base58.Encode(data), nil
+	default:
+		return "", fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+}
+
+// DecodeTransaction 解析 EncodeTransaction 生成的字符串
+func DecodeTransaction(encoded string, encoding string) (types.Transaction, error) {
+	var (
+		data []byte
+		err  error
+	)
+	encoded = strings.TrimSpace(encoded)
+	switch encoding {
+	case EncodingBase64:
+		data, err = base64.StdEncoding.DecodeString(encoded)
+	case EncodingBase58:
+		data, err = base58.Decode(encoded)
+	default:
+		return types.Transaction{}, fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("failed to decode %s transaction: %w", encoding, err)
+	}
+
+	tx, err := types.TransactionDeserialize(data)
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("failed to deserialize transaction: %w", err)
+	}
+	if err := validateMessageHeader(tx.Message); err != nil {
+		return types.Transaction{}, err
+	}
+	return tx, nil
+}
+
+// validateMessageHeader 检查消息头与账户列表是否一致。
+// SDK 反序列化时不做该检查，损坏或伪造的交易会让按签名下标读取账户的代码越界。
+func validateMessageHeader(message types.Message) error {
+	header := message.Header
+	switch {
+	case len(message.Accounts) == 0:
+		return errors.New("invalid transaction: no accounts")
+	case header.NumRequireSignatures == 0:
+		return errors.New("invalid transaction: no required signatures")
+	case int(header.NumRequireSignatures) > len(message.Accounts):
+		return fmt.Errorf("invalid transaction: %d required signatures but only %d accounts",
+			header.NumRequireSignatures, len(message.Accounts))
+	case header.NumReadonlySignedAccounts >= header.NumRequireSignatures:
+		return fmt.Errorf("invalid transaction: %d readonly signers out of %d signers",
+			header.NumReadonlySignedAccounts, header.NumRequireSignatures)
+	case int(header.NumReadonlyUnsignedAccounts) > len(message.Accounts)-int(header.NumRequireSignatures):
+		return fmt.Errorf("invalid transaction: %d readonly unsigned accounts out of %d",
+			header.NumReadonlyUnsignedAccounts, len(message.Accounts)-int(header.NumRequireSignatures))
+	}
+	return nil
+}
+
+// SummarizeTransaction 生成交易的可读摘要，供签名前人工核对
+func SummarizeTransaction(tx types.Transaction) (string, error) {
+	if err := validateMessageHeader(tx.Message); err != nil {
+		return "", err
+	}
+	hash, err := MessageHash(tx)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Message hash: %s\n", hash)
+	fmt.Fprintf(&sb, "Fee payer: %s\n", tx.Message.Accounts[0].ToBase58())
+	fmt.Fprintf(&sb, "Recent blockhash / nonce: %s\n", tx.Message.RecentBlockHash)
+	sb.WriteString("Signers:\n")
+	for i, signer := range tx.Message.Accounts[:tx.Message.Header.NumRequireSignatures] {
+		status := "unsigned"
+		if i < len(tx.Signatures) && !bytes.Equal(tx.Signatures[i], emptySignature) {
+			status = "signed"
+		}
+		fmt.Fprintf(&sb, "  %s (%s)\n", signer.ToBase58(), status)
+	}
+	sb.WriteString("Instructions:\n")
+	for i, ins := range tx.Message.Instructions {
+		// 引用地址查找表的账户不在 Accounts 中，无法解析
+		programID, accounts, ok := compiledAccounts(tx.Message, ins)
+		if !ok {
+			fmt.Fprintf(&sb, "  #%d uses accounts from address lookup tables, not decoded\n", i)
+			continue
+		}
+		instruction := types.Instruction{ProgramID: programID, Data: ins.Data}
+		for _, account := range accounts {
+			instruction.Accounts = append(instruction.Accounts, types.AccountMeta{PubKey: account})
+		}
+		fmt.Fprintf(&sb, "  #%d %s\n", i, describeInstruction(instruction))
+	}
+	return sb.String(), nil
+}
+
+// describeInstruction 解析常见的 System / Token / Memo 指令，其余指令只显示程序和数据长度
+func describeInstruction(instruction types.Instruction) string {
+	data := instruction.Data
+	accounts := instruction.Accounts
+	switch instruction.ProgramID {
+	case common.SystemProgramID:
+		if len(data) < 4 {
+			break
+		}
+		switch system.Instruction(binary.LittleEndian.Uint32(data)) {
+		case system.InstructionTransfer:
+			if len(data) >= 12 && len(accounts) >= 2 {
+				return fmt.Sprintf("System Transfer: %d lamports from %s to %s",
+					binary.LittleEndian.Uint64(data[4:12]), accounts[0].PubKey.ToBase58(), accounts[1].PubKey.ToBase58())
+			}
+		case system.InstructionAdvanceNonceAccount:
+			if len(accounts) >= 3 {
+				return fmt.Sprintf("System AdvanceNonceAccount: nonce %s, authority %s",
+					accounts[0].PubKey.ToBase58(), accounts[2].PubKey.ToBase58())
+			}
+		}
+	case common.TokenProgramID:
+		if len(data) < 1 {
+			break
+		}
+		switch token.Instruction(data[0]) {
+		case token.InstructionTransfer:
+			if len(data) >= 9 && len(accounts) >= 3 {
+				return fmt.Sprintf("Token Transfer: %d from %s to %s, authority %s",
+					binary.LittleEndian.Uint64(data[1:9]), accounts[0].PubKey.ToBase58(), accounts[1].PubKey.ToBase58(), accounts[2].PubKey.ToBase58())
+			}
+		case token.InstructionTransferChecked:
+			if len(data) >= 10 && len(accounts) >= 4 {
+				return fmt.Sprintf("Token TransferChecked: %d (decimals %d) of mint %s from %s to %s, authority %s",
+					binary.LittleEndian.Uint64(data[1:9]), data[9], accounts[1].PubKey.ToBase58(),
+					accounts[0].PubKey.ToBase58(), accounts[2].PubKey.ToBase58(), accounts[3].PubKey.ToBase58())
+			}
+		}
+	case common.MemoProgramID, memoV1ProgramID:
+		return fmt.Sprintf("Memo: %q", data)
+	}
+	return fmt.Sprintf("Program %s: %d accounts, %d bytes data", instruction.ProgramID.ToBase58(), len(accounts), len(data))
+}
+
+// signerIndex 返回账户在签名列表中的位置
+func signerIndex(message types.Message, pubkey common.PublicKey) (int, error) {
+	for i := 0; i < int(message.Header.NumRequireSignatures) && i < len(message.Accounts); i++ {
+		if message.Accounts[i] == pubkey {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not a required signer of this transaction", pubkey.ToBase58())
+}
@@ -0,0 +1,157 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUnsignedTransfer(t *testing.T, from, to types.Account) types.Transaction {
+	t.Helper()
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        from.PublicKey,
+			RecentBlockhash: types.NewAccount().PublicKey.ToBase58(),
+			Instructions: []types.Instruction{
+				system.Transfer(system.TransferParam{From: from.PublicKey, To: to.PublicKey, Amount: 42}),
+			},
+		}),
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestOfflineSigningRoundTrip(t *testing.T) {
+	from := types.NewAccount()
+	to := types.NewAccount()
+	unsigned := newUnsignedTransfer(t, from, to)
+
+	for _, encoding := range []string{EncodingBase64, EncodingBase58} {
+		t.Run(encoding, func(t *testing.T) {
+			encoded, err := EncodeTransaction(unsigned, encoding)
+			require.NoError(t, err)
+
+			// 离线机器：解码、核对摘要后签名
+			tx, err := DecodeTransaction(encoded, encoding)
+			require.NoError(t, err)
+			wantHash, _ := MessageHash(unsigned)
+			gotHash, _ := MessageHash(tx)
+			assert.Equal(t, wantHash, gotHash)
+
+			missing, err := VerifyTransaction(tx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{from.PublicKey.ToBase58()}, missing)
+
+			require.NoError(t, SignTransaction(context.Background(), &tx, NewAccountSigner(from)))
+			signed, err := EncodeTransaction(tx, encoding)
+			require.NoError(t, err)
+
+			// 联网机器：校验签名完整
+			tx, err = DecodeTransaction(signed, encoding)
+			require.NoError(t, err)
+			missing, err = VerifyTransaction(tx)
+			require.NoError(t, err)
+			assert.Empty(t, missing)
+		})
+	}
+}
+
+func TestVerifyTransactionDetectsAlteredMessage(t *testing.T) {
+	from := types.NewAccount()
+	tx := newUnsignedTransfer(t, from, types.NewAccount())
+	require.NoError(t, SignTransaction(context.Background(), &tx, NewAccountSigner(from)))
+
+	// 签名后替换收款地址
+	tx.Message.Accounts[1] = types.NewAccount().PublicKey
+	_, err := VerifyTransaction(tx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "message may have been altered")
+}
+
+func TestSignTransactionRejectsUnexpectedSigner(t *testing.T) {
+	tx := newUnsignedTransfer(t, types.NewAccount(), types.NewAccount())
+	err := SignTransaction(context.Background(), &tx, NewAccountSigner(types.NewAccount()))
+	assert.Error(t, err)
+}
+
+func TestSummarizeTransaction(t *testing.T) {
+	from := types.NewAccount()
+	to := types.NewAccount()
+	summary, err := SummarizeTransaction(newUnsignedTransfer(t, from, to))
+	require.NoError(t, err)
+	assert.Contains(t, summary, "System Transfer: 42 lamports from "+from.PublicKey.ToBase58()+" to "+to.PublicKey.ToBase58())
+	assert.Contains(t, summary, "(unsigned)")
+}
+
+func TestMalformedTransactionHeader(t *testing.T) {
+	tx := newUnsignedTransfer(t, types.NewAccount(), types.NewAccount())
+	tx.Message.Header.NumRequireSignatures = 5
+	tx.Signatures = make([]types.Signature, 5)
+	for i := range tx.Signatures {
+		tx.Signatures[i] = emptySignature
+	}
+
+	// 消息头声明的签名数多于账户数，不能越界读取
+	_, err := VerifyTransaction(tx)
+	assert.ErrorContains(t, err, "required signatures")
+	_, err = SummarizeTransaction(tx)
+	assert.ErrorContains(t, err, "required signatures")
+	encoded, err := EncodeTransaction(tx, EncodingBase64)
+	require.NoError(t, err)
+	_, err = DecodeTransaction(encoded, EncodingBase64)
+	assert.ErrorContains(t, err, "required signatures")
+
+	tx.Message.Accounts = nil
+	_, err = VerifyTransaction(tx)
+	assert.ErrorContains(t, err, "no accounts")
+}
+
+func TestBuildUnsignedTransferSOLRejectsInvalidAddresses(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	from := wm.PublicKey().ToBase58()
+
+	_, err := wm.BuildUnsignedTransferSOL(context.Background(), "bad", from, 1)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = wm.BuildUnsignedTransferSOL(context.Background(), from, "bad", 1)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	_, err = wm.BuildUnsignedTransaction(context.Background(), "bad", nil)
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Zero(t, stub.callCount("getLatestBlockhash"))
+}
+
+func TestOfflineTransactionPolicy(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	engine, err := NewPolicyEngine(Policy{
+		Mints: map[string]MintLimit{"SOL": {MaxPerTransaction: uint64Ptr(1000), MaxPerWindow: uint64Ptr(1500)}},
+	}, nil)
+	require.NoError(t, err)
+	wm.Policy = engine
+	from := wm.PublicKey().ToBase58()
+	to := types.NewAccount().PublicKey.ToBase58()
+
+	_, err = wm.BuildUnsignedTransferSOL(context.Background(), from, to, 1001)
+	requireViolation(t, err, RuleTransactionLimit)
+
+	// 组装时记录的支出在广播时不重复计算
+	tx, err := wm.BuildUnsignedTransferSOL(context.Background(), from, to, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), engine.Spent(SOL_MINT_ADDR))
+	require.NoError(t, SignTransaction(context.Background(), &tx, NewAccountSigner(wm.Account)))
+	_, err = wm.SendSignedTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), engine.Spent(SOL_MINT_ADDR))
+
+	// 其他地方组装的交易在广播前检查
+	external := newUnsignedTransfer(t, wm.Account, types.NewAccount())
+	external.Message.Instructions[0].Data = system.Transfer(system.TransferParam{Amount: 600}).Data
+	require.NoError(t, SignTransaction(context.Background(), &external, NewAccountSigner(wm.Account)))
+	_, err = wm.SendSignedTransaction(context.Background(), external)
+	requireViolation(t, err, RuleWindowLimit)
+	assert.Equal(t, 1, stub.callCount("sendTransaction"))
+}
@@ -0,0 +1,626 @@
+package wallet
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/compute_budget"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// defaultPolicyWindow 未设置 window 时滚动限额的统计时长
+const defaultPolicyWindow = 24 * time.Hour
+
+// 策略规则名称，用于 PolicyViolation.Rule
+const (
+	RuleTransactionLimit       = "transaction_limit"
+	RuleWindowLimit            = "window_limit"
+	RuleRecipientDenied        = "recipient_denied"
+	RuleRecipientNotAllowed    = "recipient_not_allowed"
+	RuleMaxSlippage            = "max_slippage"
+	RuleMaxPriorityFee         = "max_priority_fee"
+	RuleApprovalRequired       = "approval_required"
+	RuleApprovalDenied         = "approval_denied"
+	RuleUnsupportedInstruction = "unsupported_instruction"
+	RuleUnknownRecipient       = "unknown_recipient"
+)
+
+// Policy 签名前检查的策略，对应 JSON 策略文件。金额均为基本单位（SOL 为 lamports），未设置的限制不生效。
+//
+//	{
+//	  "mints": {
+//	    "SOL": {"maxPerTransaction": 1000000000, "maxPerWindow": 5000000000, "window": "24h", "approvalAbove": 500000000}
+//	  },
+//	  "denyRecipients": ["..."],
+//	  "maxSlippageBps": 100,
+//	  "maxPriorityFeeMicroLamports": 100000
+//	}
+type Policy struct {
+	// Mints 按 mint 地址设置的限额，"SOL" 表示 SOL
+	Mints map[string]MintLimit `json:"mints,omitempty"`
+	// AllowRecipients 非空时只允许转给这些地址（钱包地址或代币账户）
+	AllowRecipients []string `json:"allowRecipients,omitempty"`
+	// DenyRecipients 禁止转给这些地址
+	DenyRecipients []string `json:"denyRecipients,omitempty"`
+	// MaxSlippageBps 兑换允许的最大滑点
+	MaxSlippageBps *int `json:"maxSlippageBps,omitempty"`
+	// MaxPriorityFeeMicroLamports 每个计算单元的最高优先费（SetComputeUnitPrice）
+	MaxPriorityFeeMicroLamports *uint64 `json:"maxPriorityFeeMicroLamports,omitempty"`
+}
+
+// MintLimit 单个 mint 的限额
+type MintLimit struct {
+	// MaxPerTransaction 单笔交易的最大金额
+	MaxPerTransaction *uint64 `json:"maxPerTransaction,omitempty"`
+	// MaxPerWindow Window 内累计的最大金额，包括已签名但尚未失败的交易
+	MaxPerWindow *uint64 `json:"maxPerWindow,omitempty"`
+	// Window 滚动窗口时长，例如 "1h"、"24h"，默认 24h
+	Window string `json:"window,omitempty"`
+	// ApprovalAbove 超过该金额的交易需要 PolicyEngine.Approve 确认
+	ApprovalAbove *uint64 `json:"approvalAbove,omitempty"`
+}
+
+// PolicyViolation 被策略拒绝的操作
+type PolicyViolation struct {
+	Rule      string // Rule* 常量之一
+	Operation string
+	Mint      string // 与金额无关的规则为空
+	Message   string
+}
+
+func (e *PolicyViolation) Error() string {
+	return fmt.Sprintf("policy violation (%s) in %s: %s", e.Rule, e.Operation, e.Message)
+}
+
+// Spend 一次操作转出的资产
+type Spend struct {
+	Operation string
+	Mint      string
+	Amount    uint64
+	// Recipient 接收方钱包地址；代币转账时为目标代币账户的所有者，无法查询时为空。
+	// 兑换等没有接收方的操作 Recipient 和 RecipientAccount 都为空，不检查收款人名单
+	Recipient string
+	// RecipientAccount 代币转账的目标代币账户
+	RecipientAccount string
+}
+
+// ApproveFunc 确认大额操作，返回 false 表示拒绝
+type ApproveFunc func(ctx context.Context, spend Spend) (bool, error)
+
+// PolicyEngine 在签名前执行 Policy，并记录滚动窗口内的支出。
+// 所有方法都可以在 nil 上调用，未设置策略时不做任何检查。
+type PolicyEngine struct {
+	// Approve 确认超过 ApprovalAbove 的操作，为空时这些操作一律拒绝
+	Approve ApproveFunc
+
+	policy  Policy
+	windows map[string]time.Duration
+	allow   map[string]bool
+	deny    map[string]bool
+
+	mu      sync.Mutex
+	now     func() time.Time
+	records []spendRecord
+}
+
+type spendRecord struct {
+	key    [sha256.Size]byte // 交易消息摘要，交易发送失败时据此撤销
+	mint   string
+	amount uint64
+	at     time.Time
+}
+
+// LoadPolicy 读取 JSON 策略文件
+func LoadPolicy(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to read policy: %w", err)
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Policy{}, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// NewPolicyEngine 校验策略并创建 PolicyEngine
+func NewPolicyEngine(p Policy, approve ApproveFunc) (*PolicyEngine, error) {
+	e := &PolicyEngine{
+		Approve: approve,
+		policy:  Policy{Mints: map[string]MintLimit{}},
+		windows: map[string]time.Duration{},
+		allow:   map[string]bool{},
+		deny:    map[string]bool{},
+		now:     time.Now,
+	}
+	for mint, limit := range p.Mints {
+		if mint == "SOL" {
+			mint = SOL_MINT_ADDR
+		}
+		if err := validatePublicKey("mint", mint); err != nil {
+			return nil, fmt.Errorf("invalid policy mint %q: %w", mint, err)
+		}
+		window := defaultPolicyWindow
+		if limit.Window != "" {
+			d, err := time.ParseDuration(limit.Window)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid window %q for mint %s", limit.Window, mint)
+			}
+			window = d
+		}
+		e.policy.Mints[mint] = limit
+		e.windows[mint] = window
+	}
+	for _, addr := range p.AllowRecipients {
+		if err := validatePublicKey("allowRecipients", addr); err != nil {
+			return nil, err
+		}
+		e.allow[addr] = true
+	}
+	for _, addr := range p.DenyRecipients {
+		if err := validatePublicKey("denyRecipients", addr); err != nil {
+			return nil, err
+		}
+		e.deny[addr] = true
+	}
+	if p.MaxSlippageBps != nil && (*p.MaxSlippageBps < 0 || *p.MaxSlippageBps > maxSlippageBps) {
+		return nil, fmt.Errorf("maxSlippageBps must be between 0 and %d", maxSlippageBps)
+	}
+	e.policy.AllowRecipients = p.AllowRecipients
+	e.policy.DenyRecipients = p.DenyRecipients
+	e.policy.MaxSlippageBps = p.MaxSlippageBps
+	e.policy.MaxPriorityFeeMicroLamports = p.MaxPriorityFeeMicroLamports
+	return e, nil
+}
+
+// clone 用同一份策略和确认函数创建新的引擎，支出记录从零开始。
+// 同一服务加载多个钱包时每个钱包使用自己的引擎，限额按钱包分别计算。
+func (e *PolicyEngine) clone() *PolicyEngine {
+	if e == nil {
+		return nil
+	}
+	// 校验后的策略、窗口和名单只读，可以共用
+	return &PolicyEngine{Approve: e.Approve, policy: e.policy, windows: e.windows, allow: e.allow, deny: e.deny, now: e.now}
+}
+
+// CheckSlippage 检查兑换滑点
+func (e *PolicyEngine) CheckSlippage(operation string, slippageBps int) error {
+	if e == nil || e.policy.MaxSlippageBps == nil || slippageBps <= *e.policy.MaxSlippageBps {
+		return nil
+	}
+	return &PolicyViolation{Rule: RuleMaxSlippage, Operation: operation,
+		Message: fmt.Sprintf("slippage %d bps exceeds the maximum of %d bps", slippageBps, *e.policy.MaxSlippageBps)}
+}
+
+// CheckPriorityFee 检查每个计算单元的优先费
+func (e *PolicyEngine) CheckPriorityFee(operation string, microLamports uint64) error {
+	if e == nil || e.policy.MaxPriorityFeeMicroLamports == nil || microLamports <= *e.policy.MaxPriorityFeeMicroLamports {
+		return nil
+	}
+	return &PolicyViolation{Rule: RuleMaxPriorityFee, Operation: operation,
+		Message: fmt.Sprintf("priority fee %d micro-lamports per compute unit exceeds the maximum of %d", microLamports, *e.policy.MaxPriorityFeeMicroLamports)}
+}
+
+// Spent 返回 mint 在当前滚动窗口内已记录的支出
+func (e *PolicyEngine) Spent(mint string) uint64 {
+	if e == nil {
+		return 0
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.spentLocked(mint)
+}
+
+// authorize 检查一笔交易的所有支出，通过后在滚动窗口中记录，key 为交易消息摘要
+func (e *PolicyEngine) authorize(ctx context.Context, key [sha256.Size]byte, spends []Spend) error {
+	if e == nil || len(spends) == 0 {
+		return nil
+	}
+
+	// 同一笔交易中同一 mint 的金额合并计算
+	totals := map[string]uint64{}
+	var mints []string
+	for _, s := range spends {
+		if err := e.checkRecipient(s); err != nil {
+			return err
+		}
+		if _, ok := totals[s.Mint]; !ok {
+			mints = append(mints, s.Mint)
+		}
+		totals[s.Mint] += s.Amount
+	}
+	operation := spends[0].Operation
+
+	for _, mint := range mints {
+		limit, ok := e.policy.Mints[mint]
+		if ok && limit.MaxPerTransaction != nil && totals[mint] > *limit.MaxPerTransaction {
+			return &PolicyViolation{Rule: RuleTransactionLimit, Operation: operation, Mint: mint,
+				Message: fmt.Sprintf("amount %d exceeds the per-transaction limit of %d", totals[mint], *limit.MaxPerTransaction)}
+		}
+	}
+	// 先检查窗口限额，超限的操作不再请求人工确认
+	e.mu.Lock()
+	err := e.checkWindowLocked(operation, mints, totals)
+	e.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, mint := range mints {
+		limit, ok := e.policy.Mints[mint]
+		if ok && limit.ApprovalAbove != nil && totals[mint] > *limit.ApprovalAbove {
+			if err := e.approve(ctx, Spend{Operation: operation, Mint: mint, Amount: totals[mint], Recipient: spends[0].Recipient, RecipientAccount: spends[0].RecipientAccount}, *limit.ApprovalAbove); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 确认期间其他操作可能用掉了额度，记录前在同一把锁内重新检查，并发操作不会同时用掉同一额度
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.checkWindowLocked(operation, mints, totals); err != nil {
+		return err
+	}
+	now := e.now()
+	for _, mint := range mints {
+		e.records = append(e.records, spendRecord{key: key, mint: mint, amount: totals[mint], at: now})
+	}
+	return nil
+}
+
+// checkWindowLocked 检查加上本次金额后是否超出滚动窗口限额，调用方需持有锁
+func (e *PolicyEngine) checkWindowLocked(operation string, mints []string, totals map[string]uint64) error {
+	for _, mint := range mints {
+		limit, ok := e.policy.Mints[mint]
+		if !ok || limit.MaxPerWindow == nil {
+			continue
+		}
+		if spent := e.spentLocked(mint); spent+totals[mint] > *limit.MaxPerWindow {
+			return &PolicyViolation{Rule: RuleWindowLimit, Operation: operation, Mint: mint,
+				Message: fmt.Sprintf("amount %d plus %d already spent in the last %s exceeds the limit of %d",
+					totals[mint], spent, e.windows[mint], *limit.MaxPerWindow)}
+		}
+	}
+	return nil
+}
+
+// recorded 交易是否已在滚动窗口中记录
+func (e *PolicyEngine) recorded(key [sha256.Size]byte) bool {
+	if e == nil {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range e.records {
+		if r.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// release 撤销发送失败的交易在滚动窗口中的记录
+func (e *PolicyEngine) release(key [sha256.Size]byte) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	kept := e.records[:0]
+	for _, r := range e.records {
+		if r.key != key {
+			kept = append(kept, r)
+		}
+	}
+	e.records = kept
+}
+
+func (e *PolicyEngine) checkRecipient(s Spend) error {
+	for _, addr := range []string{s.Recipient, s.RecipientAccount} {
+		if addr != "" && e.deny[addr] {
+			return &PolicyViolation{Rule: RuleRecipientDenied, Operation: s.Operation, Mint: s.Mint,
+				Message: fmt.Sprintf("recipient %s is on the deny list", addr)}
+		}
+	}
+	if s.Recipient == "" && s.RecipientAccount == "" {
+		return nil
+	}
+	if len(e.allow) > 0 && !e.allow[s.Recipient] && !e.allow[s.RecipientAccount] {
+		recipient := s.Recipient
+		if recipient == "" {
+			recipient = s.RecipientAccount
+		}
+		return &PolicyViolation{Rule: RuleRecipientNotAllowed, Operation: s.Operation, Mint: s.Mint,
+			Message: fmt.Sprintf("recipient %s is not on the allow list", recipient)}
+	}
+	return nil
+}
+
+func (e *PolicyEngine) approve(ctx context.Context, s Spend, threshold uint64) error {
+	if e.Approve == nil {
+		return &PolicyViolation{Rule: RuleApprovalRequired, Operation: s.Operation, Mint: s.Mint,
+			Message: fmt.Sprintf("amount %d is above %d and requires approval, but no approver is configured", s.Amount, threshold)}
+	}
+	ok, err := e.Approve(ctx, s)
+	if err != nil {
+		return fmt.Errorf("approval failed: %w", err)
+	}
+	if !ok {
+		return &PolicyViolation{Rule: RuleApprovalDenied, Operation: s.Operation, Mint: s.Mint,
+			Message: fmt.Sprintf("amount %d was not approved", s.Amount)}
+	}
+	return nil
+}
+
+// spentLocked 统计窗口内的支出，并清理过期记录
+func (e *PolicyEngine) spentLocked(mint string) uint64 {
+	now := e.now()
+	var total uint64
+	kept := e.records[:0]
+	for _, r := range e.records {
+		window, ok := e.windows[r.mint]
+		if !ok || now.Sub(r.at) >= window {
+			continue
+		}
+		kept = append(kept, r)
+		if r.mint == mint {
+			total += r.amount
+		}
+	}
+	e.records = kept
+	return total
+}
+
+// WithPolicy 启用签名前的策略检查，被拒绝的操作返回 *PolicyViolation
+func WithPolicy(e *PolicyEngine) ManagerOption {
+	return func(wm *WalletManager) {
+		wm.Policy = e
+	}
+}
+
+// policyOpts 为 buildTransaction 追加签名前的策略检查
+func (wm *WalletManager) policyOpts(operation string, opts []TxOption) []TxOption {
+	if wm.Policy == nil {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], func(o *txOptions) {
+		o.beforeSign = func(ctx context.Context, message types.Message) error {
+			return wm.checkMessagePolicy(ctx, operation, message)
+		}
+	})
+}
+
+// checkMessagePolicy 从交易消息解析支出并执行策略，用于签名前和离线交易的组装、广播
+func (wm *WalletManager) checkMessagePolicy(ctx context.Context, operation string, message types.Message) error {
+	if wm.Policy == nil {
+		return nil
+	}
+	spends, err := wm.messageSpends(ctx, operation, message)
+	if err != nil {
+		return err
+	}
+	return wm.enforcePolicy(ctx, operation, message, spends)
+}
+
+// enforcePolicy 检查交易的优先费和支出，通过后记录支出
+func (wm *WalletManager) enforcePolicy(ctx context.Context, operation string, message types.Message, spends []Spend) error {
+	if wm.Policy == nil {
+		return nil
+	}
+	if fee, ok := computeUnitPrice(message); ok {
+		if err := wm.Policy.CheckPriorityFee(operation, fee); err != nil {
+			return err
+		}
+	}
+	key, err := messageKey(message)
+	if err != nil {
+		return err
+	}
+	if err := wm.Policy.authorize(ctx, key, spends); err != nil {
+		wm.logger().WarnContext(ctx, "operation rejected by policy", "operation", operation, "network", wm.Network, "error", err)
+		return err
+	}
+	return nil
+}
+
+// policyRecorded 交易的支出是否已经记录，例如由 BuildUnsignedTransaction 组装的离线交易
+func (wm *WalletManager) policyRecorded(message types.Message) bool {
+	key, err := messageKey(message)
+	return err == nil && wm.Policy.recorded(key)
+}
+
+// releasePolicy 交易发送失败时撤销已记录的支出
+func (wm *WalletManager) releasePolicy(message types.Message) {
+	if wm.Policy == nil {
+		return
+	}
+	if key, err := messageKey(message); err == nil {
+		wm.Policy.release(key)
+	}
+}
+
+// messageSpends 从交易指令解析支出，计入的指令有：
+//   - System Transfer、TransferWithSeed 和 WithdrawNonceAccount
+//   - Token / Token-2022 的 Transfer、TransferChecked，以及转给其他地址的 CloseAccount（按账户 lamports 计为 SOL）
+//
+// CreateAccount、Allocate 和 nonce 账户管理等指令只支付新账户的租金，不计入支出。
+// 其他可能转移资金或权限的指令（Assign、Approve、SetAuthority、Burn、Token-2022 扩展指令等）、
+// 数据格式错误的指令、未知程序的指令和通过地址查找表引用账户的指令都无法核算，返回 PolicyViolation。
+func (wm *WalletManager) messageSpends(ctx context.Context, operation string, message types.Message) ([]Spend, error) {
+	unsupported := func(format string, args ...any) error {
+		return &PolicyViolation{Rule: RuleUnsupportedInstruction, Operation: operation, Message: fmt.Sprintf(format, args...)}
+	}
+
+	// 同一交易中新建的关联代币账户还查不到所有者，从创建指令中记录
+	owners := map[common.PublicKey]common.PublicKey{}
+	for _, ins := range message.Instructions {
+		programID, accounts, ok := compiledAccounts(message, ins)
+		if ok && programID == common.SPLAssociatedTokenAccountProgramID && len(accounts) >= 3 {
+			owners[accounts[1]] = accounts[2]
+		}
+	}
+
+	var spends []Spend
+	for i, ins := range message.Instructions {
+		programID, accounts, ok := compiledAccounts(message, ins)
+		if !ok {
+			return nil, unsupported("instruction %d references accounts through an address lookup table", i)
+		}
+		data := ins.Data
+		switch programID {
+		case common.SystemProgramID:
+			if len(data) < 4 {
+				return nil, unsupported("malformed system instruction %d", i)
+			}
+			switch instruction := system.Instruction(binary.LittleEndian.Uint32(data)); instruction {
+			case system.InstructionTransfer, system.InstructionWithdrawNonceAccount, system.InstructionTransferWithSeed:
+				// TransferWithSeed 的账户依次为转出账户、base 和接收方
+				to := 1
+				if instruction == system.InstructionTransferWithSeed {
+					to = 2
+				}
+				if len(data) < 12 || len(accounts) <= to {
+					return nil, unsupported("malformed system instruction %d", i)
+				}
+				// 转入自己的 wSOL 账户是包装 SOL，资金没有离开钱包
+				if ata, err := wm.wrappedSOLAccount(); err == nil && accounts[to] == ata {
+					continue
+				}
+				spends = append(spends, Spend{Operation: operation, Mint: SOL_MINT_ADDR,
+					Amount: binary.LittleEndian.Uint64(data[4:12]), Recipient: accounts[to].ToBase58()})
+			case system.InstructionCreateAccount, system.InstructionCreateAccountWithSeed,
+				system.InstructionAllocate, system.InstructionAllocateWithSeed,
+				system.InstructionAdvanceNonceAccount, system.InstructionInitializeNonceAccount,
+				system.InstructionAuthorizeNonceAccount, system.InstructionUpgradeNonceAccount:
+			default:
+				return nil, unsupported("system instruction %d (type %d) is not supported", i, instruction)
+			}
+		case common.TokenProgramID, common.Token2022ProgramID:
+			if len(data) == 0 {
+				return nil, unsupported("malformed token instruction %d", i)
+			}
+			switch instruction := token.Instruction(data[0]); instruction {
+			case token.InstructionTransferChecked:
+				if len(data) < 10 || len(accounts) < 3 {
+					return nil, unsupported("malformed token instruction %d", i)
+				}
+				spend := Spend{Operation: operation, Mint: accounts[1].ToBase58(),
+					Amount: binary.LittleEndian.Uint64(data[1:9]), RecipientAccount: accounts[2].ToBase58()}
+				if err := wm.resolveSpendRecipient(ctx, &spend, owners); err != nil {
+					return nil, err
+				}
+				spends = append(spends, spend)
+			case token.InstructionTransfer:
+				if len(data) < 9 || len(accounts) < 3 {
+					return nil, unsupported("malformed token instruction %d", i)
+				}
+				// Transfer 指令不带 mint，从转出账户读取
+				source, err := wm.Client.GetTokenAccount(ctx, accounts[0].ToBase58())
+				if err != nil {
+					return nil, fmt.Errorf("failed to read token account %s for policy check: %w", accounts[0].ToBase58(), wrapRPCError(err, nil))
+				}
+				spend := Spend{Operation: operation, Mint: source.Mint.ToBase58(),
+					Amount: binary.LittleEndian.Uint64(data[1:9]), RecipientAccount: accounts[1].ToBase58()}
+				if err := wm.resolveSpendRecipient(ctx, &spend, owners); err != nil {
+					return nil, err
+				}
+				spends = append(spends, spend)
+			case token.InstructionCloseAccount:
+				if len(accounts) < 2 {
+					return nil, unsupported("malformed token instruction %d", i)
+				}
+				// 关闭账户取回的 lamports（wSOL 账户还包括包装的 SOL）转给其他地址时计为 SOL 支出
+				if accounts[1] == wm.PublicKey() {
+					continue
+				}
+				info, err := getAccountInfo(ctx, wm.Client, accounts[0].ToBase58())
+				if err != nil {
+					return nil, fmt.Errorf("failed to read account %s for policy check: %w", accounts[0].ToBase58(), err)
+				}
+				spends = append(spends, Spend{Operation: operation, Mint: SOL_MINT_ADDR,
+					Amount: info.Lamports, Recipient: accounts[1].ToBase58()})
+			case token.InstructionInitializeMint, token.InstructionInitializeMint2,
+				token.InstructionInitializeAccount, token.InstructionInitializeAccount2, token.InstructionInitializeAccount3,
+				token.InstructionInitializeMultisig, token.InstructionInitializeMultisig2,
+				token.InstructionMintTo, token.InstructionMintToChecked, token.InstructionRevoke,
+				token.InstructionFreezeAccount, token.InstructionThawAccount, token.InstructionSyncNative:
+			default:
+				return nil, unsupported("token instruction %d (type %d) is not supported", i, instruction)
+			}
+		case common.SPLAssociatedTokenAccountProgramID:
+			// 只允许 Create 和 CreateIdempotent，旧版 Create 没有指令数据
+			if len(data) > 0 && associated_token_account.Instruction(data[0]) != associated_token_account.InstructionCreate &&
+				associated_token_account.Instruction(data[0]) != associated_token_account.InstructionCreateIdempotent {
+				return nil, unsupported("associated token account instruction %d (type %d) is not supported", i, data[0])
+			}
+		case common.ComputeBudgetProgramID, common.MemoProgramID, memoV1ProgramID:
+		default:
+			return nil, unsupported("instruction %d calls unsupported program %s", i, programID.ToBase58())
+		}
+	}
+	return spends, nil
+}
+
+// resolveSpendRecipient 收款人名单按钱包地址配置时，查询目标代币账户的所有者作为收款人。
+// 配置了名单但无法确定所有者时返回 PolicyViolation
+func (wm *WalletManager) resolveSpendRecipient(ctx context.Context, spend *Spend, owners map[common.PublicKey]common.PublicKey) error {
+	if len(wm.Policy.allow) == 0 && len(wm.Policy.deny) == 0 {
+		return nil
+	}
+	if owner, ok := owners[common.PublicKeyFromString(spend.RecipientAccount)]; ok {
+		spend.Recipient = owner.ToBase58()
+		return nil
+	}
+	account, err := wm.Client.GetTokenAccount(ctx, spend.RecipientAccount)
+	if err != nil {
+		return &PolicyViolation{Rule: RuleUnknownRecipient, Operation: spend.Operation, Mint: spend.Mint,
+			Message: fmt.Sprintf("cannot determine the owner of token account %s: %v", spend.RecipientAccount, err)}
+	}
+	spend.Recipient = account.Owner.ToBase58()
+	return nil
+}
+
+// computeUnitPrice 返回交易中 SetComputeUnitPrice 设置的优先费
+func computeUnitPrice(message types.Message) (uint64, bool) {
+	for _, ins := range message.Instructions {
+		programID, _, ok := compiledAccounts(message, ins)
+		if ok && programID == common.ComputeBudgetProgramID && len(ins.Data) >= 9 &&
+			compute_budget.Instruction(ins.Data[0]) == compute_budget.InstructionSetComputeUnitPrice {
+			return binary.LittleEndian.Uint64(ins.Data[1:9]), true
+		}
+	}
+	return 0, false
+}
+
+// compiledAccounts 返回指令的程序和账户。v0 消息中通过地址查找表引用的账户无法解析，此时返回 false
+func compiledAccounts(message types.Message, ins types.CompiledInstruction) (common.PublicKey, []common.PublicKey, bool) {
+	if ins.ProgramIDIndex >= len(message.Accounts) {
+		return common.PublicKey{}, nil, false
+	}
+	accounts := make([]common.PublicKey, 0, len(ins.Accounts))
+	for _, idx := range ins.Accounts {
+		if idx >= len(message.Accounts) {
+			return common.PublicKey{}, nil, false
+		}
+		accounts = append(accounts, message.Accounts[idx])
+	}
+	return message.Accounts[ins.ProgramIDIndex], accounts, true
+}
+
+// messageKey 交易消息的摘要，用于关联签名前记录的支出和发送结果
+func messageKey(message types.Message) ([sha256.Size]byte, error) {
+	data, err := message.Serialize()
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("failed to serialize message: %w", err)
+	}
+	return sha256.Sum256(data), nil
+}
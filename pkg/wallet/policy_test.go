@@ -0,0 +1,279 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/compute_budget"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uint64Ptr(v uint64) *uint64 { return &v }
+
+func requireViolation(t *testing.T, err error, rule string) {
+	t.Helper()
+	var violation *PolicyViolation
+	require.True(t, errors.As(err, &violation), "expected PolicyViolation, got %v", err)
+	assert.Equal(t, rule, violation.Rule)
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"mints": {"SOL": {"maxPerTransaction": 100, "maxPerWindow": 150, "window": "1h"}},
+		"maxSlippageBps": 50
+	}`), 0o600))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	engine, err := NewPolicyEngine(policy, nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, engine.windows[SOL_MINT_ADDR])
+	assert.NoError(t, engine.CheckSlippage("swap", 50))
+	requireViolation(t, engine.CheckSlippage("swap", 51), RuleMaxSlippage)
+
+	_, err = NewPolicyEngine(Policy{Mints: map[string]MintLimit{"SOL": {Window: "soon"}}}, nil)
+	assert.Error(t, err)
+	_, err = NewPolicyEngine(Policy{DenyRecipients: []string{"bad"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestPolicyEngineWindow(t *testing.T) {
+	engine, err := NewPolicyEngine(Policy{Mints: map[string]MintLimit{
+		"SOL": {MaxPerTransaction: uint64Ptr(100), MaxPerWindow: uint64Ptr(150), Window: "1h"},
+	}}, nil)
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+	engine.now = func() time.Time { return now }
+	ctx := context.Background()
+	spend := func(amount uint64) []Spend {
+		return []Spend{{Operation: "transfer_sol", Mint: SOL_MINT_ADDR, Amount: amount}}
+	}
+
+	requireViolation(t, engine.authorize(ctx, [32]byte{1}, spend(101)), RuleTransactionLimit)
+	require.NoError(t, engine.authorize(ctx, [32]byte{1}, spend(100)))
+	requireViolation(t, engine.authorize(ctx, [32]byte{2}, spend(60)), RuleWindowLimit)
+
+	// 发送失败的交易撤销后不占用额度
+	engine.release([32]byte{1})
+	assert.Equal(t, uint64(0), engine.Spent(SOL_MINT_ADDR))
+	require.NoError(t, engine.authorize(ctx, [32]byte{2}, spend(100)))
+
+	// 超出窗口的支出不再计入
+	now = now.Add(time.Hour)
+	assert.Equal(t, uint64(0), engine.Spent(SOL_MINT_ADDR))
+	require.NoError(t, engine.authorize(ctx, [32]byte{3}, spend(100)))
+}
+
+func TestPolicyEngineApproval(t *testing.T) {
+	policy := Policy{Mints: map[string]MintLimit{"SOL": {ApprovalAbove: uint64Ptr(10)}}}
+	spends := []Spend{{Operation: "transfer_sol", Mint: SOL_MINT_ADDR, Amount: 11}}
+
+	engine, err := NewPolicyEngine(policy, nil)
+	require.NoError(t, err)
+	requireViolation(t, engine.authorize(context.Background(), [32]byte{}, spends), RuleApprovalRequired)
+
+	var asked []Spend
+	approved := false
+	engine, err = NewPolicyEngine(policy, func(_ context.Context, s Spend) (bool, error) {
+		asked = append(asked, s)
+		return approved, nil
+	})
+	require.NoError(t, err)
+	requireViolation(t, engine.authorize(context.Background(), [32]byte{}, spends), RuleApprovalDenied)
+	approved = true
+	require.NoError(t, engine.authorize(context.Background(), [32]byte{}, spends))
+	require.NoError(t, engine.authorize(context.Background(), [32]byte{}, []Spend{{Operation: "transfer_sol", Mint: SOL_MINT_ADDR, Amount: 10}}))
+	assert.Len(t, asked, 2)
+}
+
+func TestPolicyEngineChecksWindowBeforeApproval(t *testing.T) {
+	asked := 0
+	engine, err := NewPolicyEngine(Policy{Mints: map[string]MintLimit{
+		"SOL": {ApprovalAbove: uint64Ptr(10), MaxPerWindow: uint64Ptr(100)},
+	}}, func(context.Context, Spend) (bool, error) {
+		asked++
+		return true, nil
+	})
+	require.NoError(t, err)
+	spend := func(amount uint64) []Spend {
+		return []Spend{{Operation: "transfer_sol", Mint: SOL_MINT_ADDR, Amount: amount}}
+	}
+
+	require.NoError(t, engine.authorize(context.Background(), [32]byte{1}, spend(60)))
+	// 超出窗口限额的操作直接拒绝，不再请求确认
+	requireViolation(t, engine.authorize(context.Background(), [32]byte{2}, spend(60)), RuleWindowLimit)
+	assert.Equal(t, 1, asked)
+}
+
+func TestMessageSpendsTokenTransferAndClose(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	engine, err := NewPolicyEngine(Policy{}, nil)
+	require.NoError(t, err)
+	wm.Policy = engine
+	owner := wm.PublicKey()
+	mint := types.NewAccount().PublicKey
+	source := types.NewAccount().PublicKey
+	closed := types.NewAccount().PublicKey
+	destination := types.NewAccount().PublicKey
+	foreign := types.NewAccount().PublicKey
+	stub.on("getAccountInfo", func(params []json.RawMessage) any {
+		var address string
+		_ = json.Unmarshal(params[0], &address)
+		if address == source.ToBase58() {
+			return withContext(accountInfo(common.TokenProgramID.ToBase58(), 2_039_280, tokenAccountData(mint, owner, 500, 1, nil)))
+		}
+		return withContext(accountInfo(common.TokenProgramID.ToBase58(), 2_039_280, tokenAccountData(mint, owner, 0, 1, nil)))
+	})
+
+	message := types.NewMessage(types.NewMessageParam{
+		FeePayer:        owner,
+		RecentBlockhash: testBlockhash,
+		Instructions: []types.Instruction{
+			token.Transfer(token.TransferParam{From: source, To: destination, Auth: owner, Amount: 300}),
+			token.CloseAccount(token.CloseAccountParam{Account: closed, To: foreign, Auth: owner}),
+			token.CloseAccount(token.CloseAccountParam{Account: source, To: owner, Auth: owner}),
+		},
+	})
+	spends, err := wm.messageSpends(context.Background(), "custom", message)
+	require.NoError(t, err)
+	assert.Equal(t, []Spend{
+		{Operation: "custom", Mint: mint.ToBase58(), Amount: 300, RecipientAccount: destination.ToBase58()},
+		{Operation: "custom", Mint: SOL_MINT_ADDR, Amount: 2_039_280, Recipient: foreign.ToBase58()},
+	}, spends)
+
+	// 无法读取转出账户时拒绝，不能漏算支出
+	stub.on("getAccountInfo", func([]json.RawMessage) any { return rpcStubError{Code: -32005, Message: "node is behind"} })
+	_, err = wm.messageSpends(context.Background(), "custom", message)
+	assert.ErrorContains(t, err, "policy check")
+}
+
+func TestMessageSpendsFailsClosed(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	engine, err := NewPolicyEngine(Policy{DenyRecipients: []string{types.NewAccount().PublicKey.ToBase58()}}, nil)
+	require.NoError(t, err)
+	wm.Policy = engine
+	owner := wm.PublicKey()
+	mint := types.NewAccount().PublicKey
+	source := types.NewAccount().PublicKey
+	recipient := types.NewAccount().PublicKey
+	stub.on("getAccountInfo", func([]json.RawMessage) any { return withContext(nil) })
+	spends := func(instructions ...types.Instruction) ([]Spend, error) {
+		return wm.messageSpends(context.Background(), "custom", types.NewMessage(types.NewMessageParam{
+			FeePayer: owner, RecentBlockhash: testBlockhash, Instructions: instructions,
+		}))
+	}
+
+	seeded, err := spends(system.TransferWithSeed(system.TransferWithSeedParam{
+		From: source, To: recipient, Base: owner, Owner: common.SystemProgramID, Seed: "seed", Amount: 700,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []Spend{{Operation: "custom", Mint: SOL_MINT_ADDR, Amount: 700, Recipient: recipient.ToBase58()}}, seeded)
+
+	// 同一交易中创建的关联代币账户从创建指令取得所有者
+	ata, _, err := common.FindAssociatedTokenAddress(recipient, mint)
+	require.NoError(t, err)
+	created, err := spends(
+		associated_token_account.CreateIdempotent(associated_token_account.CreateIdempotentParam{
+			Funder: owner, Owner: recipient, Mint: mint, AssociatedTokenAccount: ata,
+		}),
+		token.TransferChecked(token.TransferCheckedParam{From: source, To: ata, Mint: mint, Auth: owner, Amount: 5, Decimals: 6}),
+	)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, recipient.ToBase58(), created[0].Recipient)
+
+	// 配置了收款人名单但查不到目标账户所有者
+	_, err = spends(token.TransferChecked(token.TransferCheckedParam{From: source, To: types.NewAccount().PublicKey, Mint: mint, Auth: owner, Amount: 5, Decimals: 6}))
+	requireViolation(t, err, RuleUnknownRecipient)
+
+	malformed := token.Transfer(token.TransferParam{From: source, To: recipient, Auth: owner, Amount: 1})
+	malformed.Data = malformed.Data[:5]
+	unknown := types.Instruction{ProgramID: types.NewAccount().PublicKey, Accounts: []types.AccountMeta{{PubKey: source, IsWritable: true}}}
+	for name, ins := range map[string]types.Instruction{
+		"burn":            token.Burn(token.BurnParam{Account: source, Mint: mint, Auth: owner, Amount: 1}),
+		"approve":         token.Approve(token.ApproveParam{From: source, To: recipient, Auth: owner, Amount: 1}),
+		"assign":          system.Assign(system.AssignParam{From: owner, Owner: types.NewAccount().PublicKey}),
+		"malformed":       malformed,
+		"unknown program": unknown,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := spends(ins)
+			requireViolation(t, err, RuleUnsupportedInstruction)
+		})
+	}
+
+	// 通过地址查找表引用的账户无法解析
+	message := types.NewMessage(types.NewMessageParam{
+		FeePayer: owner, RecentBlockhash: testBlockhash,
+		Instructions: []types.Instruction{system.Transfer(system.TransferParam{From: owner, To: recipient, Amount: 1})},
+	})
+	message.Instructions[0].Accounts[1] = len(message.Accounts)
+	_, err = wm.messageSpends(context.Background(), "custom", message)
+	requireViolation(t, err, RuleUnsupportedInstruction)
+}
+
+func TestTransferSOLPolicy(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	allowed := types.NewAccount().PublicKey.ToBase58()
+	denied := types.NewAccount().PublicKey.ToBase58()
+	engine, err := NewPolicyEngine(Policy{
+		Mints:           map[string]MintLimit{"SOL": {MaxPerTransaction: uint64Ptr(1000), MaxPerWindow: uint64Ptr(1500)}},
+		AllowRecipients: []string{allowed},
+		DenyRecipients:  []string{denied},
+	}, nil)
+	require.NoError(t, err)
+	WithPolicy(engine)(wm)
+
+	_, err = wm.TransferSOL(context.Background(), allowed, 1001)
+	requireViolation(t, err, RuleTransactionLimit)
+	_, err = wm.TransferSOL(context.Background(), denied, 1)
+	requireViolation(t, err, RuleRecipientDenied)
+	_, err = wm.TransferSOL(context.Background(), types.NewAccount().PublicKey.ToBase58(), 1)
+	requireViolation(t, err, RuleRecipientNotAllowed)
+	assert.Equal(t, 0, stub.callCount("sendTransaction"))
+
+	_, err = wm.TransferSOL(context.Background(), allowed, 1000)
+	require.NoError(t, err)
+	_, err = wm.TransferSOL(context.Background(), allowed, 1000)
+	requireViolation(t, err, RuleWindowLimit)
+	assert.Equal(t, uint64(1000), engine.Spent(SOL_MINT_ADDR))
+
+	// 节点拒绝的交易不计入窗口
+	stub.on("sendTransaction", func([]json.RawMessage) any { return rpcStubError{Code: -32002, Message: "simulation failed"} })
+	_, err = wm.TransferSOL(context.Background(), allowed, 500)
+	require.Error(t, err)
+	assert.Equal(t, uint64(1000), engine.Spent(SOL_MINT_ADDR))
+}
+
+func TestPolicyPriorityFee(t *testing.T) {
+	engine, err := NewPolicyEngine(Policy{MaxPriorityFeeMicroLamports: uint64Ptr(1000)}, nil)
+	require.NoError(t, err)
+	wm := &WalletManager{Policy: engine}
+
+	payer := types.NewAccount().PublicKey
+	message := types.NewMessage(types.NewMessageParam{
+		FeePayer:        payer,
+		RecentBlockhash: testBlockhash,
+		Instructions: []types.Instruction{
+			compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{MicroLamports: 5000}),
+			system.Transfer(system.TransferParam{From: payer, To: common.PublicKeyFromString(testBlockhash), Amount: 1}),
+		},
+	})
+	fee, ok := computeUnitPrice(message)
+	require.True(t, ok)
+	assert.Equal(t, uint64(5000), fee)
+	requireViolation(t, wm.enforcePolicy(context.Background(), "transfer_sol", message, nil), RuleMaxPriorityFee)
+}
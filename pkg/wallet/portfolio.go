@@ -0,0 +1,265 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+)
+
+const (
+	// JupiterPriceAPI Jupiter 价格接口（v3），按 mint 返回 USD 价格、24 小时涨跌幅和价格所在的 slot
+	JupiterPriceAPI = "https://lite-api.jup.ag/price/v3"
+	// DefaultMaxPriceAge 价格超过这个时间没有更新视为过期
+	DefaultMaxPriceAge = 5 * time.Minute
+	// maxPriceIDs 价格接口每次最多查询的 mint 数量
+	maxPriceIDs = 50
+	// slotDuration 估算 slot 间隔，用于把价格的 slot 换算为时间
+	slotDuration = 400 * time.Millisecond
+)
+
+// TokenPrice 代币的 USD 价格
+type TokenPrice struct {
+	Mint      string
+	USD       float64
+	Change24h *float64 // 24 小时涨跌幅（百分比），价格接口没有返回时为空
+	Slot      uint64   // 价格最后一次更新所在的 slot，0 表示未知
+}
+
+// Holding 一种代币的持仓
+type Holding struct {
+	Mint      string
+	Amount    uint64  // 最小单位，同一 mint 的多个账户合并计算
+	Decimals  uint8   // mint 的精度
+	UIAmount  float64 // Amount / 10^Decimals
+	Priced    bool    // 价格接口是否返回了该 mint 的价格
+	PriceUSD  float64
+	ValueUSD  float64
+	Change24h *float64 // 价格的 24 小时涨跌幅（百分比）
+	Stale     bool     // 价格超过 MaxPriceAge 没有更新
+}
+
+// Portfolio 钱包持仓估值
+type Portfolio struct {
+	Holdings []Holding // 按估值从高到低排列，没有价格的排在最后
+	TotalUSD float64   // 有价格的持仓估值之和
+	// Change24hUSD 按当前持仓计算的 24 小时估值变化，只统计有涨跌幅的持仓
+	Change24hUSD float64
+	// Change24hPct 相对 24 小时前估值的涨跌幅（百分比），没有可统计的持仓时为空
+	Change24hPct *float64
+	Stale        bool // 任一持仓的价格已过期
+}
+
+// PortfolioOption Portfolio 的可选配置
+type PortfolioOption func(*portfolioOptions)
+
+type portfolioOptions struct {
+	priceAPI    string
+	maxPriceAge time.Duration
+}
+
+// WithPriceAPI 设置价格接口地址（默认 JupiterPriceAPI），测试时可指向本地服务
+func WithPriceAPI(priceAPI string) PortfolioOption {
+	return func(o *portfolioOptions) {
+		o.priceAPI = priceAPI
+	}
+}
+
+// WithMaxPriceAge 设置价格的最长有效时间（默认 DefaultMaxPriceAge），超过后 Holding.Stale 为 true
+func WithMaxPriceAge(age time.Duration) PortfolioOption {
+	return func(o *portfolioOptions) {
+		o.maxPriceAge = age
+	}
+}
+
+// Portfolio 列出当前钱包的 SOL 和所有代币余额，并按价格接口的 USD 价格估值。
+// 包括 Token 和 Token-2022 的代币账户，同一 mint 的账户合并；wSOL 与原生 SOL 合并为一项；余额为 0 的代币账户不列出。
+func (wm *WalletManager) Portfolio(ctx context.Context, opts ...PortfolioOption) (_ *Portfolio, err error) {
+	ctx, span := wm.startOperation(ctx, "Portfolio")
+	defer func() { endSpan(span, err) }()
+
+	o := portfolioOptions{priceAPI: JupiterPriceAPI, maxPriceAge: DefaultMaxPriceAge}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	native, err := wm.CheckAmount(ctx, SOL_MINT_ADDR)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := wm.Client.GetTokenAccountsByOwnerByProgram(ctx, wm.PublicKey().ToBase58(), common.TokenProgramID.ToBase58())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list token accounts: %w", wrapRPCError(err, nil))
+	}
+	token2022, err := getToken2022Accounts(ctx, wm.Client, wm.PublicKey().ToBase58())
+	if err != nil {
+		return nil, err
+	}
+	accounts = append(accounts, token2022...)
+
+	amounts := map[string]uint64{SOL_MINT_ADDR: native}
+	var mints []string
+	for _, a := range accounts {
+		mint := a.Mint.ToBase58()
+		if a.Amount == 0 {
+			continue
+		}
+		if _, ok := amounts[mint]; !ok {
+			mints = append(mints, mint)
+		}
+		amounts[mint] += a.Amount
+	}
+	if amounts[SOL_MINT_ADDR] == 0 {
+		delete(amounts, SOL_MINT_ADDR)
+	}
+
+	decimals := map[string]uint8{SOL_MINT_ADDR: solDecimals}
+	infos, err := getMultipleAccounts(ctx, wm.Client, mints)
+	if err != nil {
+		return nil, err
+	}
+	for _, mint := range mints {
+		if mint == SOL_MINT_ADDR {
+			continue
+		}
+		// Token-2022 的 mint 在基础的 82 字节之后还有扩展数据
+		data := infos[mint].Data
+		account, err := token.MintAccountFromData(data[:min(len(data), token.MintAccountSize)])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse mint %s: %w", mint, err)
+		}
+		decimals[mint] = account.Decimals
+	}
+
+	ids := make([]string, 0, len(amounts))
+	for mint := range amounts {
+		ids = append(ids, mint)
+	}
+	prices, err := wm.GetPrices(ctx, ids, WithPriceAPI(o.priceAPI))
+	if err != nil {
+		return nil, err
+	}
+	var slot uint64
+	if len(prices) > 0 {
+		if slot, err = wm.Client.GetSlot(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get slot: %w", wrapRPCError(err, nil))
+		}
+	}
+	maxAgeSlots := uint64(o.maxPriceAge / slotDuration)
+
+	p := &Portfolio{}
+	var before float64
+	for mint, amount := range amounts {
+		h := Holding{
+			Mint:     mint,
+			Amount:   amount,
+			Decimals: decimals[mint],
+			UIAmount: float64(amount) / math.Pow10(int(decimals[mint])),
+		}
+		if price, ok := prices[mint]; ok {
+			h.Priced = true
+			h.PriceUSD = price.USD
+			h.ValueUSD = h.UIAmount * price.USD
+			h.Change24h = price.Change24h
+			h.Stale = price.Slot > 0 && slot > price.Slot+maxAgeSlots
+			p.TotalUSD += h.ValueUSD
+			p.Stale = p.Stale || h.Stale
+			if h.Change24h != nil && *h.Change24h > -100 {
+				previous := h.ValueUSD / (1 + *h.Change24h/100)
+				p.Change24hUSD += h.ValueUSD - previous
+				before += previous
+			}
+		}
+		p.Holdings = append(p.Holdings, h)
+	}
+	if before > 0 {
+		pct := p.Change24hUSD / before * 100
+		p.Change24hPct = &pct
+	}
+	sort.Slice(p.Holdings, func(i, j int) bool {
+		a, b := p.Holdings[i], p.Holdings[j]
+		if a.Priced != b.Priced {
+			return a.Priced
+		}
+		if a.ValueUSD != b.ValueUSD {
+			return a.ValueUSD > b.ValueUSD
+		}
+		return a.Mint < b.Mint
+	})
+	return p, nil
+}
+
+// GetPrices 从价格接口查询 mints 的 USD 价格，接口没有价格的 mint 不在结果中。只使用 WithPriceAPI 选项。
+func (wm *WalletManager) GetPrices(ctx context.Context, mints []string, opts ...PortfolioOption) (_ map[string]TokenPrice, err error) {
+	ctx, span := wm.startOperation(ctx, "GetPrices")
+	defer func() { endSpan(span, err) }()
+
+	o := portfolioOptions{priceAPI: JupiterPriceAPI}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	mints = append([]string(nil), mints...)
+	sort.Strings(mints)
+	prices := make(map[string]TokenPrice, len(mints))
+	for start := 0; start < len(mints); start += maxPriceIDs {
+		chunk := mints[start:min(start+maxPriceIDs, len(mints))]
+		if err := wm.fetchPrices(ctx, o.priceAPI, chunk, prices); err != nil {
+			return nil, err
+		}
+	}
+	return prices, nil
+}
+
+// fetchPrices 查询一批 mint 的价格并写入 prices
+func (wm *WalletManager) fetchPrices(ctx context.Context, priceAPI string, mints []string, prices map[string]TokenPrice) error {
+	priceURL := priceAPI + "?ids=" + url.QueryEscape(strings.Join(mints, ","))
+	logger := wm.logger().With("operation", "get_prices", "url", redactURL(priceURL))
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, priceURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid price url: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logger.Error("price request failed", "error", err)
+		return fmt.Errorf("price request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read price response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Warn("price request rejected", "status", resp.StatusCode, "latency", time.Since(start))
+		return fmt.Errorf("price request failed: %s", truncate(string(body), maxLoggedBody))
+	}
+
+	// 没有价格的 mint 在响应中缺失或为 null
+	var result map[string]*struct {
+		USDPrice       float64  `json:"usdPrice"`
+		BlockID        uint64   `json:"blockId"`
+		PriceChange24h *float64 `json:"priceChange24h"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to decode price response: %w", err)
+	}
+	for mint, p := range result {
+		if p == nil {
+			continue
+		}
+		prices[mint] = TokenPrice{Mint: mint, USD: p.USDPrice, Change24h: p.PriceChange24h, Slot: p.BlockID}
+	}
+	logger.Debug("prices fetched", "requested", len(mints), "returned", len(result), "latency", time.Since(start))
+	return nil
+}
@@ -0,0 +1,151 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPriceServer 本地价格接口，按 mint 返回预设价格，记录每次请求的 ids
+func newPriceServer(t *testing.T, prices map[string]any) (*httptest.Server, *[][]string) {
+	t.Helper()
+	var requests [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		requests = append(requests, ids)
+		result := map[string]any{}
+		for _, id := range ids {
+			if p, ok := prices[id]; ok {
+				result[id] = p
+			}
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestPortfolio(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	owner := wm.PublicKey()
+	usdc := types.NewAccount().PublicKey
+	unknown := types.NewAccount().PublicKey
+	empty := types.NewAccount().PublicKey
+	pyusd := types.NewAccount().PublicKey // Token-2022 代币
+	wsol := common.PublicKeyFromString(SOL_MINT_ADDR)
+
+	var accounts []any
+	for _, data := range [][]byte{
+		tokenAccountData(wsol, owner, 500_000_000, 1, nil),
+		tokenAccountData(usdc, owner, 1_500_000, 1, nil),
+		tokenAccountData(usdc, owner, 500_000, 1, nil),
+		tokenAccountData(unknown, owner, 42, 1, nil),
+		tokenAccountData(empty, owner, 0, 1, nil),
+	} {
+		accounts = append(accounts, map[string]any{
+			"pubkey":  types.NewAccount().PublicKey.ToBase58(),
+			"account": accountInfo(common.TokenProgramID.ToBase58(), testRentExempt(token.TokenAccountSize), data),
+		})
+	}
+	var token2022 []any
+	for _, amount := range []uint64{300_000, 200_000} {
+		token2022 = append(token2022, map[string]any{
+			"pubkey":  types.NewAccount().PublicKey.ToBase58(),
+			"account": accountInfo(common.Token2022ProgramID.ToBase58(), testRentExempt(token.TokenAccountSize), tokenAccountData(pyusd, owner, amount, 1, nil)),
+		})
+	}
+	stub.on("getTokenAccountsByOwner", tokenAccountsByProgram(map[common.PublicKey][]any{
+		common.TokenProgramID:     accounts,
+		common.Token2022ProgramID: token2022,
+	}))
+	stub.on("getMultipleAccounts", func(params []json.RawMessage) any {
+		var addresses []string
+		_ = json.Unmarshal(params[0], &addresses)
+		values := make([]any, len(addresses))
+		for i, address := range addresses {
+			data := make([]byte, token.MintAccountSize)
+			programID := common.TokenProgramID
+			if address == pyusd.ToBase58() {
+				// 带扩展的 Token-2022 mint
+				data = make([]byte, 234)
+				programID = common.Token2022ProgramID
+			}
+			data[44] = map[string]byte{usdc.ToBase58(): 6, unknown.ToBase58(): 0, SOL_MINT_ADDR: 9, pyusd.ToBase58(): 6}[address]
+			data[45] = 1
+			values[i] = accountInfo(programID.ToBase58(), 1_000_000, data)
+		}
+		return withContext(values)
+	})
+	stub.on("getSlot", func([]json.RawMessage) any { return 10_000 })
+
+	server, requests := newPriceServer(t, map[string]any{
+		SOL_MINT_ADDR:    map[string]any{"usdPrice": 100.0, "blockId": 9_990, "priceChange24h": 10.0},
+		usdc.ToBase58():  map[string]any{"usdPrice": 1.0, "blockId": 1_000},
+		empty.ToBase58(): map[string]any{"usdPrice": 3.0, "blockId": 9_990},
+		pyusd.ToBase58(): map[string]any{"usdPrice": 1.0, "blockId": 9_990},
+	})
+
+	p, err := wm.Portfolio(context.Background(), WithPriceAPI(server.URL), WithMaxPriceAge(DefaultMaxPriceAge))
+	require.NoError(t, err)
+
+	// 余额为 0 的代币不查询价格
+	require.Len(t, *requests, 1)
+	ids := []string{SOL_MINT_ADDR, usdc.ToBase58(), unknown.ToBase58(), pyusd.ToBase58()}
+	sort.Strings(ids)
+	assert.Equal(t, ids, (*requests)[0])
+
+	require.Len(t, p.Holdings, 4)
+	sol := p.Holdings[0]
+	assert.Equal(t, SOL_MINT_ADDR, sol.Mint)
+	assert.Equal(t, uint64(1_500_000_000), sol.Amount)
+	assert.InDelta(t, 150.0, sol.ValueUSD, 1e-9)
+	assert.False(t, sol.Stale)
+	require.NotNil(t, sol.Change24h)
+
+	assert.Equal(t, Holding{
+		Mint: usdc.ToBase58(), Amount: 2_000_000, Decimals: 6, UIAmount: 2,
+		Priced: true, PriceUSD: 1, ValueUSD: 2, Stale: true,
+	}, p.Holdings[1])
+	assert.Equal(t, Holding{
+		Mint: pyusd.ToBase58(), Amount: 500_000, Decimals: 6, UIAmount: 0.5,
+		Priced: true, PriceUSD: 1, ValueUSD: 0.5,
+	}, p.Holdings[2])
+	assert.Equal(t, Holding{Mint: unknown.ToBase58(), Amount: 42, UIAmount: 42}, p.Holdings[3])
+
+	assert.InDelta(t, 152.5, p.TotalUSD, 1e-9)
+	assert.InDelta(t, 150.0-150.0/1.1, p.Change24hUSD, 1e-9)
+	require.NotNil(t, p.Change24hPct)
+	assert.InDelta(t, 10.0, *p.Change24hPct, 1e-9)
+	assert.True(t, p.Stale)
+}
+
+func TestGetPrices(t *testing.T) {
+	wm, _ := newErrorTestWallet(t, rpcStubError{})
+	mints := make([]string, 0, maxPriceIDs+1)
+	for i := 0; i <= maxPriceIDs; i++ {
+		mints = append(mints, types.NewAccount().PublicKey.ToBase58())
+	}
+	server, requests := newPriceServer(t, map[string]any{mints[0]: map[string]any{"usdPrice": 2.5}, mints[1]: nil})
+
+	prices, err := wm.GetPrices(context.Background(), mints, WithPriceAPI(server.URL))
+	require.NoError(t, err)
+	assert.Len(t, *requests, 2)
+	assert.Equal(t, map[string]TokenPrice{mints[0]: {Mint: mints[0], USD: 2.5}}, prices)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer failing.Close()
+	_, err = wm.GetPrices(context.Background(), mints[:1], WithPriceAPI(failing.URL))
+	assert.ErrorContains(t, err, "rate limited")
+}
@@ -0,0 +1,272 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRegistryPath 默认的钱包登记文件
+const DefaultRegistryPath = "assets/registry.json"
+
+// ErrWalletNotFound 登记表中没有对应标签的钱包
+var ErrWalletNotFound = errors.New("wallet not found in registry")
+
+// WalletEntry 登记表中的一个钱包
+type WalletEntry struct {
+	Label     string    `json:"label"`
+	PublicKey string    `json:"publicKey"`
+	Path      string    `json:"path"`
+	Networks  []string  `json:"networks,omitempty"` // 为空表示不限制网络
+	CreatedAt time.Time `json:"createdAt"`
+	Notes     string    `json:"notes,omitempty"`
+}
+
+// AllowsNetwork 判断钱包是否标记为可在指定网络使用
+func (e WalletEntry) AllowsNetwork(network string) bool {
+	if len(e.Networks) == 0 {
+		return true
+	}
+	for _, n := range e.Networks {
+		if n == network {
+			return true
+		}
+	}
+	return false
+}
+
+// Registry 钱包登记表，用标签代替私钥文件路径
+type Registry struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]WalletEntry
+}
+
+// OpenRegistry 打开登记文件，文件不存在时返回空登记表
+func OpenRegistry(path string) (*Registry, error) {
+	r := &Registry{path: path, entries: map[string]WalletEntry{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+
+	var entries []WalletEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse registry %s: %w", path, err)
+	}
+	for _, e := range entries {
+		r.entries[e.Label] = e
+	}
+	return r, nil
+}
+
+// Add 登记钱包。公钥从私钥文件读取，若 entry.PublicKey 非空则必须与文件一致
+func (r *Registry) Add(entry WalletEntry) error {
+	if entry.Label == "" {
+		return errors.New("label is required")
+	}
+	publicKey, err := keyFilePublicKey(entry.Path)
+	if err != nil {
+		return err
+	}
+	if entry.PublicKey != "" && entry.PublicKey != publicKey {
+		return fmt.Errorf("public key mismatch: %s contains %s, not %s", entry.Path, publicKey, entry.PublicKey)
+	}
+	entry.PublicKey = publicKey
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[entry.Label]; ok {
+		return fmt.Errorf("label %q already exists", entry.Label)
+	}
+	for _, e := range r.entries {
+		if e.PublicKey == entry.PublicKey {
+			return fmt.Errorf("wallet %s is already registered as %q", entry.PublicKey, e.Label)
+		}
+	}
+	entries := r.copyEntries()
+	entries[entry.Label] = entry
+	return r.commit(entries)
+}
+
+// List 按标签排序列出钱包，network 非空时只返回可在该网络使用的钱包
+func (r *Registry) List(network string) []WalletEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]WalletEntry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if network == "" || e.AllowsNetwork(network) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Label < entries[j].Label })
+	return entries
+}
+
+// Lookup 按标签查找钱包
+func (r *Registry) Lookup(label string) (WalletEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[label]
+	if !ok {
+		return WalletEntry{}, fmt.Errorf("%w: %q", ErrWalletNotFound, label)
+	}
+	return e, nil
+}
+
+// LookupPublicKey 按公钥查找钱包
+func (r *Registry) LookupPublicKey(publicKey string) (WalletEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.entries {
+		if e.PublicKey == publicKey {
+			return e, nil
+		}
+	}
+	return WalletEntry{}, fmt.Errorf("%w: %s", ErrWalletNotFound, publicKey)
+}
+
+// Rename 修改钱包标签
+func (r *Registry) Rename(oldLabel string, newLabel string) error {
+	if newLabel == "" {
+		return errors.New("new label is required")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[oldLabel]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrWalletNotFound, oldLabel)
+	}
+	if _, ok := r.entries[newLabel]; ok {
+		return fmt.Errorf("label %q already exists", newLabel)
+	}
+	entries := r.copyEntries()
+	delete(entries, oldLabel)
+	e.Label = newLabel
+	entries[newLabel] = e
+	return r.commit(entries)
+}
+
+// Remove 从登记表删除钱包，私钥文件保留不动
+func (r *Registry) Remove(label string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[label]; !ok {
+		return fmt.Errorf("%w: %q", ErrWalletNotFound, label)
+	}
+	entries := r.copyEntries()
+	delete(entries, label)
+	return r.commit(entries)
+}
+
+// copyEntries 复制当前登记表，修改在副本上进行，调用方需持有锁
+func (r *Registry) copyEntries() map[string]WalletEntry {
+	entries := make(map[string]WalletEntry, len(r.entries)+1)
+	for label, e := range r.entries {
+		entries[label] = e
+	}
+	return entries
+}
+
+// commit 原子写入修改后的登记表，写入成功后才替换内存中的登记表，调用方需持有锁
+func (r *Registry) commit(entries map[string]WalletEntry) error {
+	list := make([]WalletEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Label < list[j].Label })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(r.path, data, 0o644, true); err != nil {
+		return fmt.Errorf("failed to write registry: %w", err)
+	}
+	r.entries = entries
+	return nil
+}
+
+// LoadFromRegistry 按标签加载登记表中的钱包，钱包的网络标签必须包含当前网络
+func (wm *WalletManager) LoadFromRegistry(r *Registry, label string) (string, error) {
+	e, err := r.Lookup(label)
+	if err != nil {
+		return "", err
+	}
+	if !e.AllowsNetwork(wm.Network) {
+		return "", fmt.Errorf("wallet %q is not tagged for network %s (tags: %v)", label, wm.Network, e.Networks)
+	}
+	if IsEncryptedKeystore(e.Path) {
+		return "", fmt.Errorf("wallet %q is an encrypted keystore, use NewKeystoreSigner(%q, ...)", label, e.Path)
+	}
+
+	publicKey, err := wm.LoadAccount(e.Path)
+	if err != nil {
+		return "", err
+	}
+	if publicKey != e.PublicKey {
+		return "", fmt.Errorf("key file %s now contains %s, registry expects %s", e.Path, publicKey, e.PublicKey)
+	}
+	return publicKey, nil
+}
+
+// ResolveKeyPath 参数是已存在的文件时直接返回，否则作为 registryPath 登记表中的标签查找私钥文件
+func ResolveKeyPath(registryPath string, labelOrPath string) (string, error) {
+	if _, err := os.Stat(labelOrPath); err == nil {
+		return labelOrPath, nil
+	}
+	registry, err := OpenRegistry(registryPath)
+	if err != nil {
+		return "", err
+	}
+	entry, err := registry.Lookup(labelOrPath)
+	if err != nil {
+		return "", fmt.Errorf("%s is neither a key file nor a registered label: %w", labelOrPath, err)
+	}
+	return entry.Path, nil
+}
+
+// LoadSigner 按文件路径或默认登记表中的标签加载明文私钥文件或加密 keystore，
+// keystore 的密码在签名时由 passphrase 提供
+func LoadSigner(labelOrPath string, passphrase PassphraseFunc) (Signer, error) {
+	keyPath, err := ResolveKeyPath(DefaultRegistryPath, labelOrPath)
+	if err != nil {
+		return nil, err
+	}
+	if IsEncryptedKeystore(keyPath) {
+		return NewKeystoreSigner(keyPath, passphrase)
+	}
+	account, err := LoadKeypair(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewAccountSigner(account), nil
+}
+
+// keyFilePublicKey 读取明文私钥文件或加密 keystore 对应的公钥
+func keyFilePublicKey(path string) (string, error) {
+	if ks, err := readKeystore(path); err == nil {
+		return ks.PublicKey, nil
+	}
+	account, err := LoadKeypair(path)
+	if err != nil {
+		return "", err
+	}
+	return account.PublicKey.ToBase58(), nil
+}
@@ -0,0 +1,113 @@
+package wallet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestKeyFile(t *testing.T, dir string, account types.Account) string {
+	t.Helper()
+	path := filepath.Join(dir, account.PublicKey.ToBase58()+".json")
+	data, err := json.Marshal(account.PrivateKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestRegistry(t *testing.T) {
+	dir := t.TempDir()
+	registryPath := filepath.Join(dir, "registry.json")
+	treasury := types.NewAccount()
+	hot := types.NewAccount()
+
+	registry, err := OpenRegistry(registryPath)
+	require.NoError(t, err)
+	require.NoError(t, registry.Add(WalletEntry{Label: "treasury", Path: writeTestKeyFile(t, dir, treasury), Networks: []string{"mainnet"}}))
+	require.NoError(t, registry.Add(WalletEntry{Label: "hot", Path: writeTestKeyFile(t, dir, hot), Notes: "trading"}))
+
+	// 标签和公钥都不能重复
+	assert.Error(t, registry.Add(WalletEntry{Label: "treasury", Path: writeTestKeyFile(t, dir, types.NewAccount())}))
+	assert.Error(t, registry.Add(WalletEntry{Label: "treasury2", Path: writeTestKeyFile(t, dir, treasury)}))
+	// 公钥与文件不一致
+	assert.Error(t, registry.Add(WalletEntry{Label: "bad", PublicKey: hot.PublicKey.ToBase58(), Path: writeTestKeyFile(t, dir, types.NewAccount())}))
+
+	// 重新打开后内容保持一致
+	registry, err = OpenRegistry(registryPath)
+	require.NoError(t, err)
+	entry, err := registry.Lookup("treasury")
+	require.NoError(t, err)
+	assert.Equal(t, treasury.PublicKey.ToBase58(), entry.PublicKey)
+	assert.False(t, entry.CreatedAt.IsZero())
+
+	assert.Len(t, registry.List(""), 2)
+	devnet := registry.List("devnet")
+	require.Len(t, devnet, 1)
+	assert.Equal(t, "hot", devnet[0].Label)
+
+	require.NoError(t, registry.Rename("hot", "trading"))
+	_, err = registry.Lookup("hot")
+	assert.ErrorIs(t, err, ErrWalletNotFound)
+
+	wm := &WalletManager{Network: "devnet"}
+	publicKey, err := wm.LoadFromRegistry(registry, "trading")
+	require.NoError(t, err)
+	assert.Equal(t, hot.PublicKey.ToBase58(), publicKey)
+	_, err = wm.LoadFromRegistry(registry, "treasury")
+	assert.Error(t, err)
+
+	require.NoError(t, registry.Remove("trading"))
+	assert.Len(t, registry.List(""), 1)
+}
+
+func TestRegistryKeepsEntriesWhenSaveFails(t *testing.T) {
+	dir := t.TempDir()
+	registry, err := OpenRegistry(filepath.Join(dir, "registry.json"))
+	require.NoError(t, err)
+	require.NoError(t, registry.Add(WalletEntry{Label: "hot", Path: writeTestKeyFile(t, dir, types.NewAccount())}))
+
+	// 父路径是普通文件，写入必然失败
+	blocker := filepath.Join(dir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o600))
+	registry.path = filepath.Join(blocker, "registry.json")
+
+	assert.Error(t, registry.Rename("hot", "trading"))
+	assert.Error(t, registry.Remove("hot"))
+	assert.Error(t, registry.Add(WalletEntry{Label: "cold", Path: writeTestKeyFile(t, dir, types.NewAccount())}))
+	entries := registry.List("")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "hot", entries[0].Label)
+}
+
+func TestLoadSignerResolvesFilesAndKeystores(t *testing.T) {
+	dir := t.TempDir()
+	plain := types.NewAccount()
+	signer, err := LoadSigner(writeTestKeyFile(t, dir, plain), EnvPassphrase("UNUSED_PASSPHRASE"))
+	require.NoError(t, err)
+	assert.Equal(t, plain.PublicKey, signer.PublicKey())
+
+	encrypted := types.NewAccount()
+	keystorePath := filepath.Join(dir, "keystore.json")
+	require.NoError(t, SaveEncryptedKeystore(keystorePath, encrypted, []byte("secret")))
+	signer, err = LoadSigner(keystorePath, EnvPassphrase("TEST_KEYSTORE_PASSPHRASE"))
+	require.NoError(t, err)
+	assert.Equal(t, encrypted.PublicKey, signer.PublicKey())
+	_, err = EnvPassphrase("TEST_KEYSTORE_PASSPHRASE")()
+	assert.ErrorContains(t, err, "TEST_KEYSTORE_PASSPHRASE is not set")
+
+	// 不是文件时按标签查找登记表
+	registryPath := filepath.Join(dir, "registry.json")
+	registry, err := OpenRegistry(registryPath)
+	require.NoError(t, err)
+	require.NoError(t, registry.Add(WalletEntry{Label: "hot", Path: keystorePath}))
+	path, err := ResolveKeyPath(registryPath, "hot")
+	require.NoError(t, err)
+	assert.Equal(t, keystorePath, path)
+	_, err = ResolveKeyPath(registryPath, "cold")
+	assert.ErrorIs(t, err, ErrWalletNotFound)
+}
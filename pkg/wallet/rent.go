@@ -0,0 +1,191 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrBelowRentExempt 操作后账户余额大于 0 但低于免租金额，节点会拒绝这类交易
+var ErrBelowRentExempt = errors.New("balance would fall below rent exemption")
+
+// reclaimBatchSize 每笔交易最多关闭的代币账户数量，设置了 memo 时按交易大小（1232 字节）进一步减少
+const reclaimBatchSize = 20
+
+// ReclaimResult ReclaimRent 的结果
+type ReclaimResult struct {
+	Closed     []string // 已关闭的代币账户
+	Signatures []string // 每批一个交易签名
+	Lamports   uint64   // 按免租金额估算的回收数量
+}
+
+// ensureRentExempt 检查账户支出 spend 后余额要么为 0（账户被回收），要么不低于 dataLen 字节账户的免租金额。
+// 余额不足以支出时返回 ErrInsufficientFunds，会留下零头时返回 ErrBelowRentExempt。
+func (wm *WalletManager) ensureRentExempt(ctx context.Context, address string, balance uint64, spend uint64, dataLen uint64) error {
+	if balance < spend {
+		return fmt.Errorf("%w: %s has %d lamports, need %d lamports", ErrInsufficientFunds, address, balance, spend)
+	}
+	remaining := balance - spend
+	if remaining == 0 {
+		return nil
+	}
+	minimum, err := wm.Client.GetMinimumBalanceForRentExemption(ctx, dataLen)
+	if err != nil {
+		return fmt.Errorf("failed to get min balance for rent exemption: %w", wrapRPCError(err, nil))
+	}
+	if remaining >= minimum {
+		return nil
+	}
+	if balance > minimum {
+		return fmt.Errorf("%w: %s would keep %d lamports, the minimum is %d; spend at most %d lamports or empty the account",
+			ErrBelowRentExempt, address, remaining, minimum, balance-minimum)
+	}
+	return fmt.Errorf("%w: %s would keep %d lamports, the minimum is %d; empty the account instead",
+		ErrBelowRentExempt, address, remaining, minimum)
+}
+
+// ensurePayerRentExempt 检查当前钱包支付 spend 和交易手续费后仍然免租，或者余额恰好用完。
+// 手续费按 instructions 和 opts 组装的消息计算，包括优先费和 nonce 权限等所有签名。
+func (wm *WalletManager) ensurePayerRentExempt(ctx context.Context, spend uint64, instructions []types.Instruction, opts []TxOption) error {
+	balance, err := wm.CheckAmount(ctx, SOL_MINT_ADDR)
+	if err != nil {
+		return fmt.Errorf("failed to check balance: %w", err)
+	}
+	fee, err := wm.messageFee(ctx, wm.PublicKey(), instructions, opts)
+	if err != nil {
+		return err
+	}
+	return wm.ensureRentExempt(ctx, wm.PublicKey().ToBase58(), balance, spend+fee, 0)
+}
+
+// messageFee 用 getFeeForMessage 计算 instructions 和 opts 组装的交易的手续费
+func (wm *WalletManager) messageFee(ctx context.Context, feePayer common.PublicKey, instructions []types.Instruction, opts []TxOption) (uint64, error) {
+	message, err := buildMessage(ctx, wm.Client, feePayer, instructions, newTxOptions(opts))
+	if err != nil {
+		return 0, err
+	}
+	fee, err := wm.Client.GetFeeForMessage(ctx, message)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get fee for message: %w", wrapRPCError(err, nil))
+	}
+	if fee == nil {
+		return 0, fmt.Errorf("failed to get fee for message: %w", ErrBlockhashExpired)
+	}
+	return *fee, nil
+}
+
+// ReclaimRent 关闭当前钱包所有余额为 0 的代币账户（包括 Token-2022 账户），取回其中的免租 SOL。
+// 每笔交易最多关闭 reclaimBatchSize 个账户；中途失败时返回已完成的部分和错误。
+// 冻结的账户和关闭权限不属于当前钱包的账户会被跳过。
+func (wm *WalletManager) ReclaimRent(ctx context.Context, opts ...TxOption) (_ *ReclaimResult, err error) {
+	ctx, span := wm.startOperation(ctx, "ReclaimRent")
+	defer func() { endSpan(span, err) }()
+
+	owner := wm.PublicKey()
+	accounts, err := wm.Client.GetTokenAccountsByOwnerByProgram(ctx, owner.ToBase58(), common.TokenProgramID.ToBase58())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list token accounts: %w", wrapRPCError(err, nil))
+	}
+	accounts2022, err := getToken2022Accounts(ctx, wm.Client, owner.ToBase58())
+	if err != nil {
+		return nil, err
+	}
+	// Token-2022 账户需要由 Token-2022 程序关闭
+	token2022 := make(map[common.PublicKey]bool, len(accounts2022))
+	for _, a := range accounts2022 {
+		token2022[a.PublicKey] = true
+	}
+
+	var empty []common.PublicKey
+	for _, a := range append(accounts, accounts2022...) {
+		if a.Amount != 0 || a.State != token.TokenAccountStateInitialized {
+			continue
+		}
+		if a.CloseAuthority != nil && *a.CloseAuthority != owner {
+			continue
+		}
+		empty = append(empty, a.PublicKey)
+	}
+	result := &ReclaimResult{}
+	span.SetAttributes(attribute.Int("solana.empty_accounts", len(empty)))
+	if len(empty) == 0 {
+		return result, nil
+	}
+
+	rent, err := wm.Client.GetMinimumBalanceForRentExemption(ctx, token.TokenAccountSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get min balance for rent exemption: %w", wrapRPCError(err, nil))
+	}
+
+	batches, err := packReclaimBatches(owner, empty, token2022, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, batch := range batches {
+		instructions := closeAccountInstructions(owner, batch, token2022)
+		tx, err := buildTransaction(ctx, wm.Client, instructions, []Signer{wm.signer()}, wm.policyOpts("reclaim_rent", opts)...)
+		if err != nil {
+			return result, err
+		}
+		txhash, err := wm.sendTransaction(ctx, "reclaim_rent", tx, "accounts", len(batch))
+		if err != nil {
+			return result, fmt.Errorf("failed to send transaction: %w", err)
+		}
+		result.Signatures = append(result.Signatures, txhash)
+		for _, account := range batch {
+			result.Closed = append(result.Closed, account.ToBase58())
+		}
+		result.Lamports += rent * uint64(len(batch))
+	}
+	return result, nil
+}
+
+// packReclaimBatches 把要关闭的账户分批，每批不超过 reclaimBatchSize 个，
+// 且加上 memo 等选项追加的指令后不超过交易大小限制
+func packReclaimBatches(owner common.PublicKey, accounts []common.PublicKey, token2022 map[common.PublicKey]bool, opts []TxOption) ([][]common.PublicKey, error) {
+	reserved, err := newTxOptions(opts).memoInstructions(owner)
+	if err != nil {
+		return nil, err
+	}
+	var batches [][]common.PublicKey
+	var current []common.PublicKey
+	for _, account := range accounts {
+		next := append(current[:len(current):len(current)], account)
+		size, err := transactionSize(owner, append(reserved[:len(reserved):len(reserved)], closeAccountInstructions(owner, next, token2022)...))
+		if err != nil {
+			return nil, err
+		}
+		if len(current) > 0 && (len(next) > reclaimBatchSize || size > maxTransactionSize-sweepSizeHeadroom) {
+			batches = append(batches, current)
+			next = []common.PublicKey{account}
+		}
+		current = next
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches, nil
+}
+
+// closeAccountInstructions 关闭 accounts 并把 lamports 退回 owner 的指令，token2022 中的账户由 Token-2022 程序关闭
+func closeAccountInstructions(owner common.PublicKey, accounts []common.PublicKey, token2022 map[common.PublicKey]bool) []types.Instruction {
+	instructions := make([]types.Instruction, 0, len(accounts))
+	for _, account := range accounts {
+		ins := token.CloseAccount(token.CloseAccountParam{
+			Account: account,
+			Auth:    owner,
+			To:      owner,
+		})
+		// SDK 的 CloseAccount 固定使用 Token 程序，两个程序的指令格式相同
+		if token2022[account] {
+			ins.ProgramID = common.Token2022ProgramID
+		}
+		instructions = append(instructions, ins)
+	}
+	return instructions
+}
@@ -0,0 +1,145 @@
+package wallet
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenAccountData 构造代币账户数据，state 为 1（已初始化）或 2（冻结）
+func tokenAccountData(mint common.PublicKey, owner common.PublicKey, amount uint64, state byte, closeAuthority *common.PublicKey) []byte {
+	data := make([]byte, token.TokenAccountSize)
+	copy(data[0:32], mint[:])
+	copy(data[32:64], owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], amount)
+	data[108] = state
+	if closeAuthority != nil {
+		binary.LittleEndian.PutUint32(data[129:133], 1)
+		copy(data[133:165], closeAuthority[:])
+	}
+	return data
+}
+
+func TestTransferSOLRentExemption(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	to := types.NewAccount().PublicKey.ToBase58()
+	const balance = 1_000_000_000
+
+	// 剩余 100 lamports，低于免租金额
+	_, err := wm.TransferSOL(context.Background(), to, balance-lamportsPerSignature-100)
+	assert.ErrorIs(t, err, ErrBelowRentExempt)
+	_, err = wm.TransferSOL(context.Background(), to, balance)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Equal(t, 0, stub.callCount("sendTransaction"))
+
+	// 转出全部余额（扣除手续费）或保留免租金额都可以
+	_, err = wm.TransferSOL(context.Background(), to, balance-lamportsPerSignature)
+	require.NoError(t, err)
+	_, err = wm.TransferSOL(context.Background(), to, balance-lamportsPerSignature-testRentExempt(0))
+	require.NoError(t, err)
+	assert.Equal(t, 2, stub.callCount("sendTransaction"))
+
+	// 手续费按节点对实际消息的报价计算，包括优先费
+	stub.on("getFeeForMessage", func([]json.RawMessage) any { return withContext(uint64(lamportsPerSignature + 2000)) })
+	_, err = wm.TransferSOL(context.Background(), to, balance-lamportsPerSignature, WithPriorityFee(10_000, 200_000))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	_, err = wm.TransferSOL(context.Background(), to, balance-lamportsPerSignature-2000, WithPriorityFee(10_000, 200_000))
+	require.NoError(t, err)
+}
+
+func TestWithdrawNonceRentExemption(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	nonce := types.NewAccount().PublicKey.ToBase58()
+
+	_, err := wm.WithdrawNonce(context.Background(), nonce, types.NewAccount().PublicKey.ToBase58(), 1_000_000_000-100)
+	assert.ErrorIs(t, err, ErrBelowRentExempt)
+	assert.Equal(t, 0, stub.callCount("sendTransaction"))
+}
+
+func TestReclaimRent(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	owner := wm.PublicKey()
+	mint := types.NewAccount().PublicKey
+	other := types.NewAccount().PublicKey
+
+	accounts := map[common.PublicKey][]any{}
+	var empty []string
+	add := func(program common.PublicKey, data []byte) string {
+		pubkey := types.NewAccount().PublicKey.ToBase58()
+		accounts[program] = append(accounts[program], map[string]any{
+			"pubkey":  pubkey,
+			"account": accountInfo(program.ToBase58(), testRentExempt(token.TokenAccountSize), data),
+		})
+		return pubkey
+	}
+	for i := 0; i < 22; i++ {
+		empty = append(empty, add(common.TokenProgramID, tokenAccountData(mint, owner, 0, 1, nil)))
+	}
+	empty = append(empty, add(common.TokenProgramID, tokenAccountData(mint, owner, 0, 1, &owner)))
+	add(common.TokenProgramID, tokenAccountData(mint, owner, 5, 1, nil))
+	add(common.TokenProgramID, tokenAccountData(mint, owner, 0, 2, nil))
+	add(common.TokenProgramID, tokenAccountData(mint, owner, 0, 1, &other))
+	empty = append(empty, add(common.Token2022ProgramID, tokenAccountData(mint, owner, 0, 1, nil)))
+	add(common.Token2022ProgramID, tokenAccountData(mint, owner, 7, 1, nil))
+	stub.on("getTokenAccountsByOwner", tokenAccountsByProgram(accounts))
+
+	result, err := wm.ReclaimRent(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, empty, result.Closed)
+	assert.Len(t, result.Signatures, 2)
+	assert.Equal(t, 24*testRentExempt(token.TokenAccountSize), result.Lamports)
+
+	// 第二批包括 3 个 Token 账户和 1 个 Token-2022 账户
+	tx := sentTransaction(t, stub, 1)
+	require.Len(t, tx.Message.Instructions, 4)
+	for i, ins := range tx.Message.Instructions {
+		program := common.TokenProgramID
+		if i == 3 {
+			program = common.Token2022ProgramID
+		}
+		assert.Equal(t, program, tx.Message.Accounts[ins.ProgramIDIndex])
+		assert.Equal(t, byte(token.InstructionCloseAccount), ins.Data[0])
+	}
+}
+
+func TestPackReclaimBatchesCountsMemo(t *testing.T) {
+	owner := types.NewAccount().PublicKey
+	var accounts []common.PublicKey
+	for i := 0; i < 25; i++ {
+		accounts = append(accounts, types.NewAccount().PublicKey)
+	}
+
+	batches, err := packReclaimBatches(owner, accounts, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[0], reclaimBatchSize)
+
+	memo := strings.Repeat("m", MaxMemoLength)
+	batches, err = packReclaimBatches(owner, accounts, nil, []TxOption{WithMemo(memo)})
+	require.NoError(t, err)
+	assert.Greater(t, len(batches), 2)
+	var packed []common.PublicKey
+	for _, batch := range batches {
+		ins, err := memoInstruction(owner, memo)
+		require.NoError(t, err)
+		size, err := transactionSize(owner, append(closeAccountInstructions(owner, batch, nil), ins))
+		require.NoError(t, err)
+		assert.LessOrEqual(t, size, maxTransactionSize-sweepSizeHeadroom)
+		packed = append(packed, batch...)
+	}
+	assert.Equal(t, accounts, packed)
+
+	_, err = packReclaimBatches(owner, accounts, nil, []TxOption{WithMemo("")})
+	assert.ErrorIs(t, err, ErrInvalidMemo)
+}
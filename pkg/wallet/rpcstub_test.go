@@ -0,0 +1,138 @@
+package wallet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+// lamportsPerSignature 每个签名的基础手续费，不含优先费
+const lamportsPerSignature = 5000
+
+// rpcStub 本地 JSON-RPC 桩服务，按方法名返回预设结果
+type rpcStub struct {
+	mu       sync.Mutex
+	handlers map[string]func(params []json.RawMessage) any
+	calls    map[string][]([]json.RawMessage)
+	url      string
+}
+
+func newRPCStub(t *testing.T) (*rpcStub, *client.Client) {
+	t.Helper()
+	stub := &rpcStub{
+		handlers: map[string]func(params []json.RawMessage) any{},
+		calls:    map[string][]([]json.RawMessage){},
+	}
+	// 所有转账都会检查免租金额，默认按主网参数返回
+	stub.handlers["getMinimumBalanceForRentExemption"] = func(params []json.RawMessage) any {
+		var dataLen uint64
+		_ = json.Unmarshal(params[0], &dataLen)
+		return testRentExempt(dataLen)
+	}
+	// 手续费默认按消息头中的签名数计算，不含优先费
+	stub.handlers["getFeeForMessage"] = func(params []json.RawMessage) any {
+		var encoded string
+		_ = json.Unmarshal(params[0], &encoded)
+		message, _ := base64.StdEncoding.DecodeString(encoded)
+		if len(message) == 0 {
+			return withContext(nil)
+		}
+		return withContext(uint64(message[0]) * lamportsPerSignature)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     any               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stub.mu.Lock()
+		stub.calls[req.Method] = append(stub.calls[req.Method], req.Params)
+		handler, ok := stub.handlers[req.Method]
+		stub.mu.Unlock()
+
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if !ok {
+			resp["error"] = map[string]any{"code": -32601, "message": "method not found: " + req.Method}
+		} else if result := handler(req.Params); isRPCStubError(result) {
+			resp["error"] = result
+		} else {
+			resp["result"] = result
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	stub.url = server.URL
+	return stub, client.NewClient(server.URL)
+}
+
+// on 注册方法的返回结果
+func (s *rpcStub) on(method string, handler func(params []json.RawMessage) any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// callCount 返回方法被调用的次数
+func (s *rpcStub) callCount(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.calls[method])
+}
+
+// testRentExempt 主网参数下 dataLen 字节账户的免租金额：(128 + dataLen) * 3480 * 2
+func testRentExempt(dataLen uint64) uint64 {
+	return (128 + dataLen) * 3480 * 2
+}
+
+// withContext 包装带 context 的返回值
+func withContext(value any) any {
+	return map[string]any{"context": map[string]any{"slot": 1}, "value": value}
+}
+
+// tokenAccountsByProgram 按 getTokenAccountsByOwner 请求中的 programId 返回代币账户，没有列出的程序返回空列表
+func tokenAccountsByProgram(accounts map[common.PublicKey][]any) func([]json.RawMessage) any {
+	return func(params []json.RawMessage) any {
+		var filter struct {
+			ProgramID string `json:"programId"`
+		}
+		_ = json.Unmarshal(params[1], &filter)
+		if list, ok := accounts[common.PublicKeyFromString(filter.ProgramID)]; ok {
+			return withContext(list)
+		}
+		return withContext([]any{})
+	}
+}
+
+// accountInfo 构造 getAccountInfo 返回的账户信息
+func accountInfo(owner string, lamports uint64, data []byte) any {
+	return map[string]any{
+		"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"executable": false,
+		"lamports":   lamports,
+		"owner":      owner,
+		"rentEpoch":  0,
+	}
+}
+
+// rpcStubError 处理函数返回该类型时作为 JSON-RPC 错误响应
+type rpcStubError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func isRPCStubError(v any) bool {
+	_, ok := v.(rpcStubError)
+	return ok
+}
@@ -0,0 +1,204 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Signer 交易签名者。所有构建交易的方法都通过 Signer 签名，
+// 私钥可以在内存、加密 keystore 或远程签名服务中，调用方无需持有私钥。
+type Signer interface {
+	// PublicKey 返回签名账户的公钥
+	PublicKey() common.PublicKey
+	// SignMessage 对序列化后的交易消息签名，返回 64 字节 ed25519 签名
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// AccountSigner 使用内存中私钥签名
+type AccountSigner struct {
+	account types.Account
+}
+
+// NewAccountSigner 用内存中的账户创建签名者
+func NewAccountSigner(account types.Account) *AccountSigner {
+	return &AccountSigner{account: account}
+}
+
+// PublicKey 返回账户公钥
+func (s *AccountSigner) PublicKey() common.PublicKey {
+	return s.account.PublicKey
+}
+
+// SignMessage 使用内存中的私钥签名
+func (s *AccountSigner) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	return s.account.Sign(message), nil
+}
+
+// RemoteSigner 通过 HTTP 调用远程签名服务（硬件钱包网关、KMS 代理等）签名
+type RemoteSigner struct {
+	Endpoint   string // 签名服务地址，请求发送到 Endpoint + "/sign"
+	AuthToken  string // 可选，作为 Bearer token 发送
+	HTTPClient *http.Client
+	publicKey  common.PublicKey
+}
+
+// signRequest / signResponse 远程签名服务的请求和响应格式
+type signRequest struct {
+	PublicKey string `json:"publicKey"`
+	Message   string `json:"message"` // base64
+}
+
+type signResponse struct {
+	Signature string `json:"signature"` // base64
+	Error     string `json:"error,omitempty"`
+}
+
+// NewRemoteSigner 创建远程签名者，publicKey 为远程服务持有私钥对应的公钥
+func NewRemoteSigner(endpoint string, publicKey string) (*RemoteSigner, error) {
+	pubkey, err := ParseAddress(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer public key: %w", err)
+	}
+	return &RemoteSigner{
+		Endpoint:   endpoint,
+		HTTPClient: http.DefaultClient,
+		publicKey:  pubkey,
+	}, nil
+}
+
+// PublicKey 返回远程账户公钥
+func (s *RemoteSigner) PublicKey() common.PublicKey {
+	return s.publicKey
+}
+
+// SignMessage 请求远程服务签名，并在本地校验返回的签名
+func (s *RemoteSigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	reqBody, err := json.Marshal(signRequest{
+		PublicKey: s.publicKey.ToBase58(),
+		Message:   base64.StdEncoding.EncodeToString(message),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint+"/sign", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sign request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.AuthToken)
+	}
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote signer response: %w", err)
+	}
+	var signResp signResponse
+	if err := json.Unmarshal(body, &signResp); err != nil {
+		return nil, fmt.Errorf("failed to decode remote signer response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer failed with status %d: %s", resp.StatusCode, signResp.Error)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signResp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	if !ed25519.Verify(s.publicKey.Bytes(), message, signature) {
+		return nil, fmt.Errorf("remote signer returned an invalid signature for %s", s.publicKey.ToBase58())
+	}
+	return signature, nil
+}
+
+// NewSigningServer 返回本地签名服务的 HTTP handler，实现 RemoteSigner 使用的 /sign 接口。
+// 用于测试和本地开发，生产环境应替换为硬件钱包或 KMS 网关。
+// authToken 非空时要求请求携带相同的 Bearer token。
+func NewSigningServer(authToken string, signers ...Signer) http.Handler {
+	byPubkey := make(map[string]Signer, len(signers))
+	for _, s := range signers {
+		byPubkey[s.PublicKey().ToBase58()] = s
+	}
+
+	writeJSON := func(w http.ResponseWriter, status int, resp signResponse) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, signResponse{Error: "method not allowed"})
+			return
+		}
+		if authToken != "" && r.Header.Get("Authorization") != "Bearer "+authToken {
+			writeJSON(w, http.StatusUnauthorized, signResponse{Error: "unauthorized"})
+			return
+		}
+
+		var req signRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, signResponse{Error: "invalid request body"})
+			return
+		}
+		signer, ok := byPubkey[req.PublicKey]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, signResponse{Error: "unknown public key " + req.PublicKey})
+			return
+		}
+		message, err := base64.StdEncoding.DecodeString(req.Message)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, signResponse{Error: "message must be base64"})
+			return
+		}
+
+		signature, err := signer.SignMessage(r.Context(), message)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, signResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, signResponse{Signature: base64.StdEncoding.EncodeToString(signature)})
+	})
+	return mux
+}
+
+// signMessage 用签名者列表为交易签名，填充对应的签名槽位
+func signMessage(ctx context.Context, tx *types.Transaction, signers ...Signer) (err error) {
+	ctx, span := startSpan(ctx, "wallet.sign", attribute.Int("solana.signers", len(signers)))
+	defer func() { endSpan(span, err) }()
+
+	data, err := tx.Message.Serialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize message: %w", err)
+	}
+	for _, signer := range signers {
+		idx, err := signerIndex(tx.Message, signer.PublicKey())
+		if err != nil {
+			return err
+		}
+		signature, err := signer.SignMessage(ctx, data)
+		if err != nil {
+			return fmt.Errorf("signer %s failed: %w", signer.PublicKey().ToBase58(), err)
+		}
+		tx.Signatures[idx] = signature
+	}
+	return nil
+}
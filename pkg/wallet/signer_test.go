@@ -0,0 +1,125 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystoreSigner(t *testing.T) {
+	account := types.NewAccount()
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, SaveEncryptedKeystore(path, account, []byte("correct horse")))
+	assert.True(t, IsEncryptedKeystore(path))
+
+	signer, err := NewKeystoreSigner(path, func() ([]byte, error) { return []byte("correct horse"), nil })
+	require.NoError(t, err)
+	assert.Equal(t, account.PublicKey, signer.PublicKey())
+
+	message := []byte("message")
+	signature, err := signer.SignMessage(context.Background(), message)
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(account.PublicKey.Bytes(), message, signature))
+
+	wrong, err := NewKeystoreSigner(path, func() ([]byte, error) { return []byte("wrong"), nil })
+	require.NoError(t, err)
+	_, err = wrong.SignMessage(context.Background(), message)
+	assert.Error(t, err)
+}
+
+func TestDecryptKeystoreTruncatedNonce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, SaveEncryptedKeystore(path, types.NewAccount(), []byte("correct horse")))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var ks map[string]any
+	require.NoError(t, json.Unmarshal(data, &ks))
+	ks["nonce"] = base64.StdEncoding.EncodeToString([]byte("short"))
+	data, err = json.Marshal(ks)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	_, err = DecryptKeystore(path, []byte("correct horse"))
+	assert.ErrorContains(t, err, "corrupted file")
+}
+
+func TestKeystoreRejectsScryptParameters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, SaveEncryptedKeystore(path, types.NewAccount(), []byte("correct horse")))
+	original, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	for _, params := range []map[string]int{
+		{"n": 1 << 21},
+		{"n": 3000},
+		{"n": 0},
+		{"r": 64},
+		{"r": 0},
+		{"p": 17},
+	} {
+		var ks map[string]any
+		require.NoError(t, json.Unmarshal(original, &ks))
+		for k, v := range params {
+			ks[k] = v
+		}
+		data, err := json.Marshal(ks)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		_, err = NewKeystoreSigner(path, func() ([]byte, error) { return []byte("correct horse"), nil })
+		assert.ErrorContains(t, err, "unsupported scrypt parameters", params)
+		_, err = DecryptKeystore(path, []byte("correct horse"))
+		assert.ErrorContains(t, err, "unsupported scrypt parameters", params)
+		_, err = LoadSigner(path, nil)
+		assert.ErrorContains(t, err, "unsupported scrypt parameters", params)
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	account := types.NewAccount()
+	server := httptest.NewServer(NewSigningServer("secret", NewAccountSigner(account)))
+	defer server.Close()
+
+	signer, err := NewRemoteSigner(server.URL, account.PublicKey.ToBase58())
+	require.NoError(t, err)
+	signer.AuthToken = "secret"
+
+	// 远程账户作为手续费支付账户为交易签名
+	tx := newUnsignedTransfer(t, account, types.NewAccount())
+	require.NoError(t, SignTransaction(context.Background(), &tx, signer))
+	missing, err := VerifyTransaction(tx)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	message := []byte("message")
+	t.Run("unauthorized", func(t *testing.T) {
+		signer, err := NewRemoteSigner(server.URL, account.PublicKey.ToBase58())
+		require.NoError(t, err)
+		_, err = signer.SignMessage(context.Background(), message)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "401")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		signer, err := NewRemoteSigner(server.URL, types.NewAccount().PublicKey.ToBase58())
+		require.NoError(t, err)
+		signer.AuthToken = "secret"
+		_, err = signer.SignMessage(context.Background(), message)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown public key")
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		_, err := NewRemoteSigner(server.URL, "bad")
+		assert.ErrorIs(t, err, ErrInvalidAddress)
+	})
+}
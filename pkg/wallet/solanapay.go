@@ -0,0 +1,449 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/skip2/go-qrcode"
+)
+
+// solanaPayScheme Solana Pay 链接的 URL scheme
+const solanaPayScheme = "solana"
+
+// solDecimals SOL 的精度（1 SOL = 1e9 lamports）
+const solDecimals = 9
+
+var (
+	// ErrInvalidPaymentURL 不是合法的 Solana Pay 转账请求链接
+	ErrInvalidPaymentURL = errors.New("invalid solana pay url")
+	// ErrPaymentNotFound 链上还没有包含 reference 的交易
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrPaymentMismatch 找到了包含 reference 的交易，但收款方、代币或金额与请求不符
+	ErrPaymentMismatch = errors.New("payment does not match the request")
+)
+
+// TransferRequest Solana Pay 转账请求（https://docs.solanapay.com/spec#transfer-request）
+type TransferRequest struct {
+	Recipient  string   // 收款钱包地址（不是代币账户）
+	Amount     string   // 以 SOL 或代币为单位的十进制数，例如 "1.5"；为空时由付款方填写
+	SPLToken   string   // 代币 mint，为空时转 SOL
+	References []string // 附加到转账指令上的只读账户，用于在链上查找这笔付款
+	Label      string   // 收款方名称
+	Message    string   // 付款说明
+	Memo       string   // 写入交易的 SPL Memo
+}
+
+// NewTransferRequest 生成付款到当前钱包的转账请求，并附带一个新的随机 reference
+func (wm *WalletManager) NewTransferRequest(amount string, splToken string) (*TransferRequest, error) {
+	req := &TransferRequest{
+		Recipient:  wm.PublicKey().ToBase58(),
+		Amount:     amount,
+		SPLToken:   splToken,
+		References: []string{types.NewAccount().PublicKey.ToBase58()},
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// URL 编码为 solana: 链接
+func (r *TransferRequest) URL() (string, error) {
+	if err := r.validate(); err != nil {
+		return "", err
+	}
+	var params []string
+	add := func(key string, value string) {
+		if value != "" {
+			// 规范要求空格编码为 %20
+			params = append(params, key+"="+strings.ReplaceAll(url.QueryEscape(value), "+", "%20"))
+		}
+	}
+	add("amount", r.Amount)
+	add("spl-token", r.SPLToken)
+	for _, ref := range r.References {
+		add("reference", ref)
+	}
+	add("label", r.Label)
+	add("message", r.Message)
+	add("memo", r.Memo)
+
+	link := solanaPayScheme + ":" + r.Recipient
+	if len(params) > 0 {
+		link += "?" + strings.Join(params, "&")
+	}
+	return link, nil
+}
+
+// ParseTransferRequest 解析 solana: 转账请求链接。交易请求（solana:https://...）不支持。
+func ParseTransferRequest(link string) (*TransferRequest, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPaymentURL, err)
+	}
+	if u.Scheme != solanaPayScheme {
+		return nil, fmt.Errorf("%w: scheme must be %q", ErrInvalidPaymentURL, solanaPayScheme)
+	}
+	if strings.HasPrefix(u.Opaque, "https") {
+		return nil, fmt.Errorf("%w: transaction requests are not supported", ErrInvalidPaymentURL)
+	}
+	recipient, err := url.PathUnescape(u.Opaque)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPaymentURL, err)
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPaymentURL, err)
+	}
+	for key, values := range query {
+		if key != "reference" && len(values) > 1 {
+			return nil, fmt.Errorf("%w: %s appears more than once", ErrInvalidPaymentURL, key)
+		}
+	}
+	req := &TransferRequest{
+		Recipient:  recipient,
+		Amount:     query.Get("amount"),
+		SPLToken:   query.Get("spl-token"),
+		References: query["reference"],
+		Label:      query.Get("label"),
+		Message:    query.Get("message"),
+		Memo:       query.Get("memo"),
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (r *TransferRequest) validate() error {
+	if _, err := ParseAddress(r.Recipient); err != nil {
+		return fmt.Errorf("%w: recipient: %v", ErrInvalidPaymentURL, err)
+	}
+	if r.Amount != "" {
+		if _, _, err := splitDecimal(r.Amount); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPaymentURL, err)
+		}
+	}
+	if r.SPLToken != "" {
+		if _, err := ParseAddress(r.SPLToken); err != nil {
+			return fmt.Errorf("%w: spl-token: %v", ErrInvalidPaymentURL, err)
+		}
+	}
+	for _, ref := range r.References {
+		if _, err := ParseAddress(ref); err != nil {
+			return fmt.Errorf("%w: reference: %v", ErrInvalidPaymentURL, err)
+		}
+	}
+	if r.Memo != "" {
+		if err := ValidateMemo(r.Memo); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPaymentURL, err)
+		}
+	}
+	return nil
+}
+
+// splitDecimal 把非负十进制数拆成整数和小数部分，不接受科学计数法和省略整数部分的写法（".5"）
+func splitDecimal(amount string) (string, string, error) {
+	whole, frac, hasDot := strings.Cut(amount, ".")
+	if whole == "" || (hasDot && frac == "") || strings.Trim(whole+frac, "0123456789") != "" {
+		return "", "", fmt.Errorf("amount %q is not a non-negative decimal number", amount)
+	}
+	return whole, frac, nil
+}
+
+// parseDecimalAmount 把十进制数转为最小单位，小数位数超过 decimals 时报错
+func parseDecimalAmount(amount string, decimals uint8) (uint64, error) {
+	whole, frac, err := splitDecimal(amount)
+	if err != nil {
+		return 0, err
+	}
+	if len(frac) > int(decimals) {
+		return 0, fmt.Errorf("amount %q has more than %d decimal places", amount, decimals)
+	}
+	value, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", int(decimals)-len(frac)), 10)
+	if !ok || !value.IsUint64() {
+		return 0, fmt.Errorf("amount %q is out of range", amount)
+	}
+	return value.Uint64(), nil
+}
+
+// TransferRequestQR 把链接渲染为 size×size 像素的 PNG 二维码
+func TransferRequestQR(link string, size int) ([]byte, error) {
+	png, err := qrcode.Encode(link, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode qr code: %w", err)
+	}
+	return png, nil
+}
+
+// TransferRequestQRTerminal 把链接渲染为可在终端显示的二维码（每个字符表示上下两个模块）
+func TransferRequestQRTerminal(link string) (string, error) {
+	q, err := qrcode.New(link, qrcode.Medium)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode qr code: %w", err)
+	}
+	return q.ToSmallString(false), nil
+}
+
+// PayTransferRequest 按转账请求从当前钱包付款，返回交易哈希。
+// 按规范，Memo 指令紧挨在转账指令之前，reference 作为只读账户附加在转账指令上。
+// 代币付款从当前钱包的关联代币账户转到收款方的关联代币账户，后者不存在时创建；
+// Token-2022 的 mint 使用 Token-2022 程序和对应的关联代币账户。
+func (wm *WalletManager) PayTransferRequest(ctx context.Context, req *TransferRequest, opts ...TxOption) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "PayTransferRequest", attrMint.String(req.SPLToken))
+	defer func() { endSpan(span, err) }()
+
+	if err := req.validate(); err != nil {
+		return "", err
+	}
+	if req.Amount == "" {
+		return "", fmt.Errorf("%w: amount is required to pay", ErrInvalidPaymentURL)
+	}
+	recipient, err := wm.resolveRecipient(ctx, "solana_pay", req.Recipient)
+	if err != nil {
+		return "", err
+	}
+	owner := wm.PublicKey()
+
+	var instructions []types.Instruction
+	if req.Memo != "" {
+		ins, err := memoInstruction(owner, req.Memo)
+		if err != nil {
+			return "", err
+		}
+		instructions = append(instructions, ins)
+	}
+
+	var transfer types.Instruction
+	var amount uint64
+	if req.SPLToken == "" {
+		if amount, err = parseDecimalAmount(req.Amount, solDecimals); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidPaymentURL, err)
+		}
+		transfer = system.Transfer(system.TransferParam{From: owner, To: recipient, Amount: amount})
+	} else {
+		mint := common.PublicKeyFromString(req.SPLToken)
+		mintAccount, program, err := wm.readMint(ctx, mint)
+		if err != nil {
+			return "", err
+		}
+		decimals := mintAccount.Decimals
+		if amount, err = parseDecimalAmount(req.Amount, decimals); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidPaymentURL, err)
+		}
+		source, err := associatedTokenAddress(owner, mint, program)
+		if err != nil {
+			return "", err
+		}
+		destination, err := associatedTokenAddress(recipient, mint, program)
+		if err != nil {
+			return "", err
+		}
+		// CreateIdempotent 放在 Memo 之前，保证 Memo 紧挨着转账指令
+		create := associated_token_account.CreateIdempotent(associated_token_account.CreateIdempotentParam{
+			Funder:                 owner,
+			Owner:                  recipient,
+			Mint:                   mint,
+			AssociatedTokenAccount: destination,
+		})
+		// SDK 固定使用 Token 程序，按 mint 所属的程序替换
+		create.Accounts[5].PubKey = program
+		instructions = append([]types.Instruction{create}, instructions...)
+		transfer = token.TransferChecked(token.TransferCheckedParam{
+			From:     source,
+			To:       destination,
+			Mint:     mint,
+			Auth:     owner,
+			Signers:  []common.PublicKey{},
+			Amount:   amount,
+			Decimals: decimals,
+		})
+		transfer.ProgramID = program
+	}
+	for _, ref := range req.References {
+		transfer.Accounts = append(transfer.Accounts, types.AccountMeta{PubKey: common.PublicKeyFromString(ref)})
+	}
+	instructions = append(instructions, transfer)
+	if req.SPLToken == "" {
+		if err := wm.ensurePayerRentExempt(ctx, amount, instructions, opts); err != nil {
+			return "", err
+		}
+	}
+
+	tx, err := buildTransaction(ctx, wm.Client, instructions, []Signer{wm.signer()}, wm.policyOpts("solana_pay", opts)...)
+	if err != nil {
+		return "", err
+	}
+	txhash, err := wm.sendTransaction(ctx, "solana_pay", tx, "to", recipient.ToBase58(), "mint", req.SPLToken, "amount", amount)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return txhash, nil
+}
+
+// mintDecimals 读取 mint 的精度
+func (wm *WalletManager) mintDecimals(ctx context.Context, mint common.PublicKey) (uint8, error) {
+	account, _, err := wm.readMint(ctx, mint)
+	if err != nil {
+		return 0, err
+	}
+	return account.Decimals, nil
+}
+
+// readMint 读取 mint 账户，同时返回它所属的代币程序（Token 或 Token-2022）
+func (wm *WalletManager) readMint(ctx context.Context, mint common.PublicKey) (token.MintAccount, common.PublicKey, error) {
+	info, err := getAccountInfo(ctx, wm.Client, mint.ToBase58())
+	if err != nil {
+		return token.MintAccount{}, common.PublicKey{}, fmt.Errorf("failed to get mint: %w", err)
+	}
+	if info.Owner != common.TokenProgramID && info.Owner != common.Token2022ProgramID {
+		return token.MintAccount{}, common.PublicKey{}, fmt.Errorf("%s is not a token mint", mint.ToBase58())
+	}
+	// 带扩展的 Token-2022 mint 在基础结构之后还有扩展数据，只解析前 82 字节
+	account, err := token.MintAccountFromData(info.Data[:min(len(info.Data), token.MintAccountSize)])
+	if err != nil {
+		return token.MintAccount{}, common.PublicKey{}, fmt.Errorf("failed to parse mint %s: %w", mint.ToBase58(), err)
+	}
+	return account, info.Owner, nil
+}
+
+// associatedTokenAddress 计算 owner 在 program（Token 或 Token-2022）下的关联代币账户
+func associatedTokenAddress(owner, mint, program common.PublicKey) (common.PublicKey, error) {
+	ata, _, err := common.FindProgramAddress([][]byte{owner.Bytes(), program.Bytes(), mint.Bytes()}, common.SPLAssociatedTokenAccountProgramID)
+	if err != nil {
+		return common.PublicKey{}, fmt.Errorf("failed to find associated token address: %w", err)
+	}
+	return ata, nil
+}
+
+// FindTransferRequestPayment 按第一个 reference 查找付款交易，并校验收款方、代币和金额，返回交易哈希。
+// 还没有付款时返回 ErrPaymentNotFound，交易与请求不符时返回 ErrPaymentMismatch。
+func (wm *WalletManager) FindTransferRequestPayment(ctx context.Context, req *TransferRequest) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "FindTransferRequestPayment", attrMint.String(req.SPLToken))
+	defer func() { endSpan(span, err) }()
+
+	if len(req.References) == 0 {
+		return "", fmt.Errorf("%w: the request has no reference", ErrInvalidPaymentURL)
+	}
+	// 节点按时间倒序返回，最早的一笔是付款交易
+	signatures, err := wm.Client.GetSignaturesForAddressWithConfig(ctx, req.References[0], client.GetSignaturesForAddressConfig{
+		Commitment: "confirmed",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get signatures: %w", wrapRPCError(err, nil))
+	}
+	if len(signatures) == 0 {
+		return "", ErrPaymentNotFound
+	}
+	signature := signatures[len(signatures)-1].Signature
+
+	tx, err := wm.Client.GetTransaction(ctx, signature)
+	if err != nil {
+		return "", fmt.Errorf("failed to get transaction %s: %w", signature, wrapRPCError(err, nil))
+	}
+	if tx == nil || tx.Meta == nil {
+		return "", ErrPaymentNotFound
+	}
+	if tx.Meta.Err != nil {
+		return "", fmt.Errorf("%w: transaction %s failed: %v", ErrPaymentMismatch, signature, parseTransactionError(tx.Meta.Err, nil))
+	}
+	received, err := receivedAmount(tx, req)
+	if err != nil {
+		return "", err
+	}
+	if req.Amount != "" {
+		decimals := uint8(solDecimals)
+		if req.SPLToken != "" {
+			if decimals, err = wm.mintDecimals(ctx, common.PublicKeyFromString(req.SPLToken)); err != nil {
+				return "", err
+			}
+		}
+		expected, err := parseDecimalAmount(req.Amount, decimals)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidPaymentURL, err)
+		}
+		if received < expected {
+			return "", fmt.Errorf("%w: transaction %s paid %d, expected %d", ErrPaymentMismatch, signature, received, expected)
+		}
+	}
+	return signature, nil
+}
+
+// receivedAmount 从交易前后的余额计算收款方收到的 lamports 或代币数量，
+// 代币按收款方持有该 mint 的所有代币账户合计
+func receivedAmount(tx *client.Transaction, req *TransferRequest) (uint64, error) {
+	if req.SPLToken == "" {
+		for i, key := range tx.AccountKeys {
+			if key.ToBase58() == req.Recipient && i < len(tx.Meta.PreBalances) && i < len(tx.Meta.PostBalances) {
+				if diff := tx.Meta.PostBalances[i] - tx.Meta.PreBalances[i]; diff > 0 {
+					return uint64(diff), nil
+				}
+				return 0, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: recipient %s is not part of the transaction", ErrPaymentMismatch, req.Recipient)
+	}
+
+	balance := func(balances []rpc.TransactionMetaTokenBalance) (uint64, bool, error) {
+		var total uint64
+		found := false
+		for _, b := range balances {
+			if b.Owner != req.Recipient || b.Mint != req.SPLToken {
+				continue
+			}
+			amount, err := strconv.ParseUint(b.UITokenAmount.Amount, 10, 64)
+			if err != nil {
+				return 0, false, fmt.Errorf("%w: invalid token balance %q: %v", ErrPaymentMismatch, b.UITokenAmount.Amount, err)
+			}
+			total += amount
+			found = true
+		}
+		return total, found, nil
+	}
+	post, ok, err := balance(tx.Meta.PostTokenBalances)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("%w: recipient %s received no %s", ErrPaymentMismatch, req.Recipient, req.SPLToken)
+	}
+	// 收款方的代币账户在这笔交易中创建时，交易前没有余额记录
+	pre, _, err := balance(tx.Meta.PreTokenBalances)
+	if err != nil {
+		return 0, err
+	}
+	if post < pre {
+		return 0, nil
+	}
+	return post - pre, nil
+}
+
+// WaitForTransferRequestPayment 轮询直到找到与请求相符的付款，ctx 控制最长等待时间
+func (wm *WalletManager) WaitForTransferRequestPayment(ctx context.Context, req *TransferRequest) (string, error) {
+	ticker := time.NewTicker(confirmPollInterval)
+	defer ticker.Stop()
+	for {
+		signature, err := wm.FindTransferRequestPayment(ctx, req)
+		if !errors.Is(err, ErrPaymentNotFound) {
+			return signature, err
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("%w: %w", ErrPaymentNotFound, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferRequestURL(t *testing.T) {
+	recipient := types.NewAccount().PublicKey.ToBase58()
+	reference := types.NewAccount().PublicKey.ToBase58()
+	req := &TransferRequest{
+		Recipient:  recipient,
+		Amount:     "1.5",
+		References: []string{reference},
+		Label:      "Coffee Shop",
+		Message:    "咖啡 #42",
+		Memo:       "order-42",
+	}
+	link, err := req.URL()
+	require.NoError(t, err)
+	assert.Equal(t, "solana:"+recipient+"?amount=1.5&reference="+reference+
+		"&label=Coffee%20Shop&message=%E5%92%96%E5%95%A1%20%2342&memo=order-42", link)
+
+	parsed, err := ParseTransferRequest(link)
+	require.NoError(t, err)
+	assert.Equal(t, req, parsed)
+
+	png, err := TransferRequestQR(link, 256)
+	require.NoError(t, err)
+	assert.Equal(t, "\x89PNG", string(png[:4]))
+	terminal, err := TransferRequestQRTerminal(link)
+	require.NoError(t, err)
+	assert.NotEmpty(t, terminal)
+}
+
+func TestParseTransferRequestInvalid(t *testing.T) {
+	recipient := types.NewAccount().PublicKey.ToBase58()
+	for _, link := range []string{
+		"bitcoin:" + recipient,
+		"solana:not-a-key",
+		"solana:https://example.com/pay",
+		"solana:" + recipient + "?amount=.5",
+		"solana:" + recipient + "?amount=1e3",
+		"solana:" + recipient + "?amount=-1",
+		"solana:" + recipient + "?amount=1&amount=2",
+		"solana:" + recipient + "?reference=bad",
+	} {
+		_, err := ParseTransferRequest(link)
+		assert.ErrorIs(t, err, ErrInvalidPaymentURL, link)
+	}
+
+	req, err := ParseTransferRequest("solana:" + recipient)
+	require.NoError(t, err)
+	assert.Equal(t, &TransferRequest{Recipient: recipient}, req)
+}
+
+func TestParseDecimalAmount(t *testing.T) {
+	amount, err := parseDecimalAmount("1.5", 9)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1_500_000_000), amount)
+	amount, err = parseDecimalAmount("42", 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), amount)
+
+	_, err = parseDecimalAmount("0.001", 2)
+	assert.Error(t, err)
+	_, err = parseDecimalAmount("18446744073709551616", 0)
+	assert.Error(t, err)
+}
+
+func TestPayAndFindTransferRequest(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	var sentTx string
+	stub.on("sendTransaction", func(params []json.RawMessage) any {
+		_ = json.Unmarshal(params[0], &sentTx)
+		return "5Sig"
+	})
+	merchant := &WalletManager{Client: wm.Client, Network: "devnet", Account: types.NewAccount(), Logger: wm.Logger}
+	req, err := merchant.NewTransferRequest("0.5", "")
+	require.NoError(t, err)
+	req.Memo = "order-42"
+	link, err := req.URL()
+	require.NoError(t, err)
+
+	parsed, err := ParseTransferRequest(link)
+	require.NoError(t, err)
+	_, err = wm.PayTransferRequest(context.Background(), parsed)
+	require.NoError(t, err)
+
+	tx, err := DecodeTransaction(sentTx, EncodingBase64)
+	require.NoError(t, err)
+	require.Len(t, tx.Message.Instructions, 2)
+	assert.Equal(t, []string{"order-42"}, MessageMemos(tx.Message))
+	transfer := tx.Message.Instructions[1]
+	assert.Equal(t, common.SystemProgramID, tx.Message.Accounts[transfer.ProgramIDIndex])
+	require.Len(t, transfer.Accounts, 3)
+	assert.Equal(t, req.References[0], tx.Message.Accounts[transfer.Accounts[2]].ToBase58())
+
+	// 付款前查不到交易
+	var signatures []any
+	stub.on("getSignaturesForAddress", func([]json.RawMessage) any { return signatures })
+	_, err = merchant.FindTransferRequestPayment(context.Background(), req)
+	assert.ErrorIs(t, err, ErrPaymentNotFound)
+
+	signatures = []any{map[string]any{"signature": "5Sig", "slot": 10, "err": nil}}
+	received := int64(500_000_000)
+	stub.on("getTransaction", func([]json.RawMessage) any {
+		pre := make([]int64, len(tx.Message.Accounts))
+		post := make([]int64, len(tx.Message.Accounts))
+		for i, key := range tx.Message.Accounts {
+			if key == merchant.PublicKey() {
+				post[i] = received
+			}
+		}
+		return map[string]any{
+			"slot":        10,
+			"meta":        map[string]any{"fee": 5000, "err": nil, "preBalances": pre, "postBalances": post},
+			"transaction": []string{sentTx, "base64"},
+		}
+	})
+	signature, err := merchant.FindTransferRequestPayment(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "5Sig", signature)
+	signature, err = merchant.WaitForTransferRequestPayment(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "5Sig", signature)
+
+	received = 100
+	_, err = merchant.FindTransferRequestPayment(context.Background(), req)
+	assert.ErrorIs(t, err, ErrPaymentMismatch)
+}
+
+func TestPayAndFindTransferRequestToken2022(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	mint := types.NewAccount().PublicKey
+	mintData := make([]byte, token.MintAccountSize)
+	mintData[44] = 6
+	mintData[45] = 1
+	stub.on("getAccountInfo", func([]json.RawMessage) any {
+		return withContext(accountInfo(common.Token2022ProgramID.ToBase58(), 1_000_000, mintData))
+	})
+	merchant := &WalletManager{Client: wm.Client, Network: "devnet", Account: types.NewAccount(), Logger: wm.Logger}
+	req, err := merchant.NewTransferRequest("1", mint.ToBase58())
+	require.NoError(t, err)
+	_, err = wm.PayTransferRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	// 关联代币账户按 Token-2022 程序计算，转账也由 Token-2022 程序执行
+	tx := sentTransaction(t, stub, 0)
+	require.Len(t, tx.Message.Instructions, 2)
+	destination, _, err := common.FindProgramAddress([][]byte{
+		merchant.PublicKey().Bytes(), common.Token2022ProgramID.Bytes(), mint.Bytes(),
+	}, common.SPLAssociatedTokenAccountProgramID)
+	require.NoError(t, err)
+	create, transfer := tx.Message.Instructions[0], tx.Message.Instructions[1]
+	assert.Equal(t, destination, tx.Message.Accounts[create.Accounts[1]])
+	assert.Equal(t, common.Token2022ProgramID, tx.Message.Accounts[create.Accounts[5]])
+	assert.Equal(t, common.Token2022ProgramID, tx.Message.Accounts[transfer.ProgramIDIndex])
+	assert.Equal(t, destination, tx.Message.Accounts[transfer.Accounts[2]])
+
+	// 付款分到收款方的两个代币账户时合计计算
+	encoded, err := EncodeTransaction(tx, EncodingBase64)
+	require.NoError(t, err)
+	stub.on("getSignaturesForAddress", func([]json.RawMessage) any {
+		return []any{map[string]any{"signature": "5Sig", "slot": 10, "err": nil}}
+	})
+	balance := func(index int, amount string) any {
+		return map[string]any{"accountIndex": index, "mint": mint.ToBase58(), "owner": merchant.PublicKey().ToBase58(),
+			"uiTokenAmount": map[string]any{"amount": amount, "decimals": 6}}
+	}
+	stub.on("getTransaction", func([]json.RawMessage) any {
+		balances := make([]int64, len(tx.Message.Accounts))
+		return map[string]any{
+			"slot": 10,
+			"meta": map[string]any{"fee": 5000, "err": nil, "preBalances": balances, "postBalances": balances,
+				"preTokenBalances":  []any{balance(2, "100")},
+				"postTokenBalances": []any{balance(1, "600000"), balance(2, "400100")}},
+			"transaction": []string{encoded, "base64"},
+		}
+	})
+	signature, err := merchant.FindTransferRequestPayment(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "5Sig", signature)
+}
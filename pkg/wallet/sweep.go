@@ -0,0 +1,396 @@
+package wallet
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// maxTransactionSize 序列化后交易的最大字节数
+	maxTransactionSize = 1232
+	// sweepSizeHeadroom 为持久 nonce 和 ComputeBudget 指令预留的字节数，打包清扫指令时扣除
+	sweepSizeHeadroom = 160
+	// maxMultipleAccounts getMultipleAccounts 每次最多查询的账户数
+	maxMultipleAccounts = 100
+)
+
+// ErrToken2022NotSwept 钱包持有 Token-2022 代币账户，清扫暂不支持，需要先手动转出或关闭
+var ErrToken2022NotSwept = errors.New("sweep does not support Token-2022 accounts")
+
+// SweptToken 被清扫的一个代币账户
+type SweptToken struct {
+	Account string
+	Mint    string
+	Amount  uint64 // 转给收款方的数量，wSOL 账户为 0（直接关闭换回 SOL）
+	Closed  bool   // 关闭权限不属于当前钱包时不关闭
+}
+
+// SweepResult 清扫结果
+type SweepResult struct {
+	Tokens     []SweptToken
+	Lamports   uint64 // 最后一笔转出的 SOL，不清扫 SOL 时为 0
+	Fee        uint64 // 最后一笔交易的手续费（含优先费）
+	Signatures []string
+}
+
+// sweepGroup 清扫一个代币账户的指令，以及执行后钱包 SOL 余额的变化
+type sweepGroup struct {
+	token        SweptToken
+	instructions []types.Instruction
+	refund       uint64 // 关闭账户退回的 lamports
+	rent         uint64 // 为收款方创建关联代币账户支付的租金
+}
+
+// SweepSOL 把全部 SOL 转给 to，转出金额为余额减去 getFeeForMessage 计算的手续费（含 WithPriorityFee 设置的优先费），
+// 转账后钱包余额为 0。
+func (wm *WalletManager) SweepSOL(ctx context.Context, to string, opts ...TxOption) (*SweepResult, error) {
+	return wm.sweep(ctx, "SweepSOL", "sweep_sol", to, false, true, opts)
+}
+
+// SweepTokens 把所有代币账户的余额转到 to 的关联代币账户（不存在时创建），然后关闭这些账户，租金退回当前钱包。
+// wSOL 账户直接关闭，SOL 回到当前钱包。指令按交易大小打包，尽量减少交易数量。
+func (wm *WalletManager) SweepTokens(ctx context.Context, to string, opts ...TxOption) (*SweepResult, error) {
+	return wm.sweep(ctx, "SweepTokens", "sweep_tokens", to, true, false, opts)
+}
+
+// SweepAll 先执行 SweepTokens，再把剩余的 SOL（包括关闭账户退回的租金）全部转给 to。
+// SOL 转账尽量与最后一批代币指令放在同一笔交易中。
+func (wm *WalletManager) SweepAll(ctx context.Context, to string, opts ...TxOption) (*SweepResult, error) {
+	return wm.sweep(ctx, "SweepAll", "sweep_all", to, true, true, opts)
+}
+
+func (wm *WalletManager) sweep(ctx context.Context, name string, operation string, to string, tokens bool, sol bool, opts []TxOption) (_ *SweepResult, err error) {
+	ctx, span := wm.startOperation(ctx, name)
+	defer func() { endSpan(span, err) }()
+
+	owner := wm.PublicKey()
+	recipient, err := wm.resolveRecipient(ctx, operation, to)
+	if err != nil {
+		return nil, err
+	}
+	if recipient == owner {
+		return nil, errors.New("cannot sweep a wallet to itself")
+	}
+
+	var groups []sweepGroup
+	if tokens {
+		if groups, err = wm.sweepGroups(ctx, owner, recipient); err != nil {
+			return nil, err
+		}
+	}
+	reserved, err := newTxOptions(opts).memoInstructions(owner)
+	if err != nil {
+		return nil, err
+	}
+	batches, err := packSweepGroups(owner, recipient, groups, sol, reserved)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("solana.token_accounts", len(groups)), attribute.Int("solana.transactions", len(batches)))
+
+	result := &SweepResult{}
+	for i, batch := range batches {
+		var instructions []types.Instruction
+		for _, g := range batch {
+			instructions = append(instructions, g.instructions...)
+		}
+		if sol && i == len(batches)-1 {
+			// 前面的批次确认后余额才准确
+			for _, sig := range result.Signatures {
+				if err := wm.WaitForConfirmation(ctx, sig); err != nil {
+					return result, err
+				}
+			}
+			amount, fee, err := wm.sweepAmount(ctx, owner, recipient, batch, instructions, opts)
+			if err != nil {
+				return result, err
+			}
+			instructions = append(instructions, system.Transfer(system.TransferParam{From: owner, To: recipient, Amount: amount}))
+			result.Lamports = amount
+			result.Fee = fee
+		}
+
+		tx, err := buildTransaction(ctx, wm.Client, instructions, []Signer{wm.signer()}, wm.policyOpts(operation, opts)...)
+		if err != nil {
+			return result, err
+		}
+		txhash, err := wm.sendTransaction(ctx, operation, tx, "to", recipient.ToBase58(), "accounts", len(batch), "lamports", result.Lamports)
+		if err != nil {
+			return result, fmt.Errorf("failed to send transaction: %w", err)
+		}
+		result.Signatures = append(result.Signatures, txhash)
+		for _, g := range batch {
+			result.Tokens = append(result.Tokens, g.token)
+		}
+	}
+	return result, nil
+}
+
+// sweepAmount 计算最后一笔交易可以转出的 SOL：当前余额加上本批关闭账户退回的租金，
+// 减去为收款方创建账户的租金和整笔交易的手续费
+func (wm *WalletManager) sweepAmount(ctx context.Context, owner common.PublicKey, recipient common.PublicKey, batch []sweepGroup, instructions []types.Instruction, opts []TxOption) (uint64, uint64, error) {
+	balance, err := wm.CheckAmount(ctx, SOL_MINT_ADDR)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to check balance: %w", err)
+	}
+	available := balance
+	var rent uint64
+	for _, g := range batch {
+		available += g.refund
+		rent += g.rent
+	}
+	if available < rent {
+		return 0, 0, fmt.Errorf("%w: have %d lamports, need %d lamports to create token accounts", ErrInsufficientFunds, available, rent)
+	}
+	available -= rent
+
+	// 手续费与转账金额无关，用占位金额组装消息计算
+	placeholder := append(instructions[:len(instructions):len(instructions)],
+		system.Transfer(system.TransferParam{From: owner, To: recipient, Amount: available}))
+	fee, err := wm.messageFee(ctx, owner, placeholder, opts)
+	if err != nil {
+		return 0, 0, err
+	}
+	if available <= fee {
+		return 0, 0, fmt.Errorf("%w: %d lamports do not cover the %d lamports fee", ErrInsufficientFunds, available, fee)
+	}
+	amount := available - fee
+
+	// 收款账户不存在时，转入的金额必须达到免租金额
+	recipientBalance, err := wm.Client.GetBalance(ctx, recipient.ToBase58())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get recipient balance: %w", wrapRPCError(err, nil))
+	}
+	if recipientBalance == 0 {
+		if err := wm.ensureRentExempt(ctx, recipient.ToBase58(), amount, 0, 0); err != nil {
+			return 0, 0, err
+		}
+	}
+	return amount, fee, nil
+}
+
+// sweepGroups 为当前钱包的每个代币账户生成清扫指令：转出余额、关闭账户。冻结的账户会被跳过。
+// 钱包持有 Token-2022 账户时不发送任何交易，返回 ErrToken2022NotSwept 并列出这些账户，避免只清扫一部分。
+func (wm *WalletManager) sweepGroups(ctx context.Context, owner common.PublicKey, recipient common.PublicKey) ([]sweepGroup, error) {
+	accounts, err := wm.Client.GetTokenAccountsByOwnerByProgram(ctx, owner.ToBase58(), common.TokenProgramID.ToBase58())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list token accounts: %w", wrapRPCError(err, nil))
+	}
+	token2022, err := getToken2022Accounts(ctx, wm.Client, owner.ToBase58())
+	if err != nil {
+		return nil, err
+	}
+	if len(token2022) > 0 {
+		skipped := make([]string, 0, len(token2022))
+		for _, a := range token2022 {
+			skipped = append(skipped, fmt.Sprintf("%s (mint %s, amount %d)", a.PublicKey.ToBase58(), a.Mint.ToBase58(), a.Amount))
+		}
+		return nil, fmt.Errorf("%w, transfer or close them first: %s", ErrToken2022NotSwept, strings.Join(skipped, ", "))
+	}
+
+	// 一次查询代币账户的 lamports、mint 的精度和收款方的关联代币账户是否存在
+	var addresses []string
+	destinations := map[common.PublicKey]common.PublicKey{}
+	var active []client.TokenAccount
+	for _, a := range accounts {
+		if a.State != token.TokenAccountStateInitialized {
+			continue
+		}
+		active = append(active, a)
+		addresses = append(addresses, a.PublicKey.ToBase58())
+		if a.Amount == 0 || a.IsNative != nil {
+			continue
+		}
+		if _, ok := destinations[a.Mint]; !ok {
+			ata, _, err := common.FindAssociatedTokenAddress(recipient, a.Mint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find associated token address: %w", err)
+			}
+			destinations[a.Mint] = ata
+			addresses = append(addresses, a.Mint.ToBase58(), ata.ToBase58())
+		}
+	}
+	if len(active) == 0 {
+		return nil, nil
+	}
+	infos, err := getMultipleAccounts(ctx, wm.Client, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	var ataRent uint64
+	created := map[common.PublicKey]bool{}
+	var groups []sweepGroup
+	for _, a := range active {
+		g := sweepGroup{token: SweptToken{Account: a.PublicKey.ToBase58(), Mint: a.Mint.ToBase58()}}
+		if ata, ok := destinations[a.Mint]; ok && a.IsNative == nil && a.Amount > 0 {
+			mint, err := token.MintAccountFromData(infos[a.Mint.ToBase58()].Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse mint %s: %w", a.Mint.ToBase58(), err)
+			}
+			if infos[ata.ToBase58()].Owner == (common.PublicKey{}) && !created[ata] {
+				if ataRent == 0 {
+					if ataRent, err = wm.Client.GetMinimumBalanceForRentExemption(ctx, token.TokenAccountSize); err != nil {
+						return nil, fmt.Errorf("failed to get min balance for rent exemption: %w", wrapRPCError(err, nil))
+					}
+				}
+				created[ata] = true
+				g.rent = ataRent
+				g.instructions = append(g.instructions, associated_token_account.CreateIdempotent(associated_token_account.CreateIdempotentParam{
+					Funder:                 owner,
+					Owner:                  recipient,
+					Mint:                   a.Mint,
+					AssociatedTokenAccount: ata,
+				}))
+			}
+			g.instructions = append(g.instructions, token.TransferChecked(token.TransferCheckedParam{
+				From:     a.PublicKey,
+				To:       ata,
+				Mint:     a.Mint,
+				Auth:     owner,
+				Signers:  []common.PublicKey{},
+				Amount:   a.Amount,
+				Decimals: mint.Decimals,
+			}))
+			g.token.Amount = a.Amount
+		}
+		// wSOL 账户的余额就是 lamports，关闭即换回 SOL；关闭权限属于他人的账户只转出余额
+		if a.CloseAuthority == nil || *a.CloseAuthority == owner {
+			g.instructions = append(g.instructions, token.CloseAccount(token.CloseAccountParam{
+				Account: a.PublicKey,
+				Auth:    owner,
+				To:      owner,
+			}))
+			g.refund = infos[a.PublicKey.ToBase58()].Lamports
+			g.token.Closed = true
+		}
+		if len(g.instructions) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	return groups, nil
+}
+
+// packSweepGroups 按交易大小把清扫指令分批，同一账户的指令不拆开。
+// withTransfer 为 true 时最后一批末尾还要放下一条 SOL 转账，放不下时单独成批。
+// reserved 是每笔交易都会追加的指令（如 Memo），计入每批的大小。
+func packSweepGroups(owner common.PublicKey, recipient common.PublicKey, groups []sweepGroup, withTransfer bool, reserved []types.Instruction) ([][]sweepGroup, error) {
+	transfer := system.Transfer(system.TransferParam{From: owner, To: recipient, Amount: 1})
+	fits := func(batch []sweepGroup, extra ...types.Instruction) (bool, error) {
+		instructions := append([]types.Instruction{}, reserved...)
+		for _, g := range batch {
+			instructions = append(instructions, g.instructions...)
+		}
+		size, err := transactionSize(owner, append(instructions, extra...))
+		return size <= maxTransactionSize-sweepSizeHeadroom, err
+	}
+
+	var batches [][]sweepGroup
+	var current []sweepGroup
+	for _, g := range groups {
+		ok, err := fits(append(current[:len(current):len(current)], g))
+		if err != nil {
+			return nil, err
+		}
+		if !ok && len(current) > 0 {
+			batches = append(batches, current)
+			current = nil
+		}
+		current = append(current, g)
+	}
+	if withTransfer {
+		ok, err := fits(current, transfer)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			batches = append(batches, current)
+			current = nil
+		}
+		return append(batches, current), nil
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches, nil
+}
+
+// transactionSize 估算由 feePayer 签名的交易序列化后的字节数
+func transactionSize(feePayer common.PublicKey, instructions []types.Instruction) (int, error) {
+	message := types.NewMessage(types.NewMessageParam{
+		FeePayer:        feePayer,
+		RecentBlockhash: common.PublicKey{}.ToBase58(),
+		Instructions:    instructions,
+	})
+	data, err := message.Serialize()
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize message: %w", err)
+	}
+	// 签名数量的 compact-u16 编码（不超过 127 个签名时为 1 字节）加上每个 64 字节的签名
+	return 1 + 64*int(message.Header.NumRequireSignatures) + len(data), nil
+}
+
+// getMultipleAccounts 分批查询账户，返回地址到账户信息的映射，不存在的账户为零值
+func getMultipleAccounts(ctx context.Context, c *client.Client, addresses []string) (map[string]client.AccountInfo, error) {
+	infos := make(map[string]client.AccountInfo, len(addresses))
+	for start := 0; start < len(addresses); start += maxMultipleAccounts {
+		chunk := addresses[start:min(start+maxMultipleAccounts, len(addresses))]
+		result, err := c.GetMultipleAccounts(ctx, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get accounts: %w", wrapRPCError(err, nil))
+		}
+		for i, info := range result {
+			infos[chunk[i]] = info
+		}
+	}
+	return infos, nil
+}
+
+// getToken2022Accounts 列出 owner 的 Token-2022 代币账户。
+// SDK 只解析长度恰好 165 字节的 Token Program 账户，带扩展的 Token-2022 账户更长，这里只取前 165 字节的基础字段。
+func getToken2022Accounts(ctx context.Context, c *client.Client, owner string) ([]client.TokenAccount, error) {
+	resp, err := c.RpcClient.GetTokenAccountsByOwnerWithConfig(ctx, owner,
+		rpc.GetTokenAccountsByOwnerConfigFilter{ProgramId: common.Token2022ProgramID.ToBase58()},
+		rpc.GetTokenAccountsByOwnerConfig{Encoding: rpc.AccountEncodingBase64, DataSlice: &rpc.DataSlice{Length: token.TokenAccountSize}})
+	if err == nil {
+		err = resp.GetError()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Token-2022 accounts: %w", wrapRPCError(err, nil))
+	}
+
+	accounts := make([]client.TokenAccount, 0, len(resp.Result.Value))
+	for _, v := range resp.Result.Value {
+		raw, ok := v.Account.Data.([]any)
+		if !ok || len(raw) != 2 {
+			return nil, fmt.Errorf("unexpected data encoding for token account %s", v.Pubkey)
+		}
+		encoded, _ := raw[0].(string)
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode token account %s: %w", v.Pubkey, err)
+		}
+		account, err := token.TokenAccountFromData(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse token account %s: %w", v.Pubkey, err)
+		}
+		pubkey, err := ParseAddress(v.Pubkey)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, client.TokenAccount{TokenAccount: account, PublicKey: pubkey})
+	}
+	return accounts, nil
+}
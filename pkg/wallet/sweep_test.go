@@ -0,0 +1,245 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSweepTestWallet 钱包余额 1 SOL，手续费固定 5000 lamports，收款地址余额为 0
+func newSweepTestWallet(t *testing.T) (*WalletManager, *rpcStub) {
+	t.Helper()
+	interval := confirmPollInterval
+	t.Cleanup(func() { confirmPollInterval = interval })
+	confirmPollInterval = 10 * time.Millisecond
+
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	owner := wm.PublicKey().ToBase58()
+	stub.on("getBalance", func(params []json.RawMessage) any {
+		var address string
+		_ = json.Unmarshal(params[0], &address)
+		if address == owner {
+			return withContext(uint64(1_000_000_000))
+		}
+		return withContext(uint64(0))
+	})
+	stub.on("getFeeForMessage", func([]json.RawMessage) any { return withContext(uint64(lamportsPerSignature)) })
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	stub.on("getSignatureStatuses", func([]json.RawMessage) any {
+		return withContext([]any{map[string]any{"slot": 10, "confirmations": 0, "confirmationStatus": "confirmed", "err": nil}})
+	})
+	stub.on("getTokenAccountsByOwner", func([]json.RawMessage) any { return withContext([]any{}) })
+	return wm, stub
+}
+
+// sentTransaction 解码第 i 笔发送的交易
+func sentTransaction(t *testing.T, stub *rpcStub, i int) types.Transaction {
+	t.Helper()
+	var encoded string
+	require.NoError(t, json.Unmarshal(stub.calls["sendTransaction"][i][0], &encoded))
+	tx, err := DecodeTransaction(encoded, EncodingBase64)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestSweepSOL(t *testing.T) {
+	wm, stub := newSweepTestWallet(t)
+	to := types.NewAccount().PublicKey
+
+	result, err := wm.SweepSOL(context.Background(), to.ToBase58())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1_000_000_000-lamportsPerSignature), result.Lamports)
+	assert.Equal(t, uint64(lamportsPerSignature), result.Fee)
+	assert.Equal(t, []string{"5Sig"}, result.Signatures)
+	assert.Equal(t, 0, stub.callCount("getTokenAccountsByOwner"))
+
+	tx := sentTransaction(t, stub, 0)
+	require.Len(t, tx.Message.Instructions, 1)
+	assert.Equal(t, common.SystemProgramID, tx.Message.Accounts[tx.Message.Instructions[0].ProgramIDIndex])
+	assert.Equal(t, to, tx.Message.Accounts[1])
+
+	_, err = wm.SweepSOL(context.Background(), wm.PublicKey().ToBase58())
+	assert.Error(t, err)
+}
+
+func TestSweepSOLPriorityFee(t *testing.T) {
+	wm, stub := newSweepTestWallet(t)
+	stub.on("getFeeForMessage", func([]json.RawMessage) any { return withContext(uint64(lamportsPerSignature + 2000)) })
+
+	result, err := wm.SweepSOL(context.Background(), types.NewAccount().PublicKey.ToBase58(), WithPriorityFee(10_000, 200_000))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1_000_000_000-lamportsPerSignature-2000), result.Lamports)
+
+	// 手续费按带 ComputeBudget 指令的消息计算
+	assert.Equal(t, 1, stub.callCount("getFeeForMessage"))
+	tx := sentTransaction(t, stub, 0)
+	require.Len(t, tx.Message.Instructions, 3)
+	assert.Equal(t, common.ComputeBudgetProgramID, tx.Message.Accounts[tx.Message.Instructions[0].ProgramIDIndex])
+	assert.Equal(t, common.ComputeBudgetProgramID, tx.Message.Accounts[tx.Message.Instructions[1].ProgramIDIndex])
+}
+
+func TestSweepSOLBelowRentExempt(t *testing.T) {
+	wm, stub := newSweepTestWallet(t)
+	stub.on("getBalance", func(params []json.RawMessage) any {
+		var address string
+		_ = json.Unmarshal(params[0], &address)
+		if address == wm.PublicKey().ToBase58() {
+			return withContext(uint64(lamportsPerSignature + 100))
+		}
+		return withContext(uint64(0))
+	})
+
+	_, err := wm.SweepSOL(context.Background(), types.NewAccount().PublicKey.ToBase58())
+	assert.ErrorIs(t, err, ErrBelowRentExempt)
+	assert.Equal(t, 0, stub.callCount("sendTransaction"))
+}
+
+func TestSweepAll(t *testing.T) {
+	wm, stub := newSweepTestWallet(t)
+	owner := wm.PublicKey()
+	to := types.NewAccount().PublicKey
+	mint := types.NewAccount().PublicKey
+	other := types.NewAccount().PublicKey
+	rent := testRentExempt(token.TokenAccountSize)
+
+	var accounts []any
+	add := func(data []byte) string {
+		pubkey := types.NewAccount().PublicKey.ToBase58()
+		accounts = append(accounts, map[string]any{
+			"pubkey":  pubkey,
+			"account": accountInfo(common.TokenProgramID.ToBase58(), rent, data),
+		})
+		return pubkey
+	}
+	funded := add(tokenAccountData(mint, owner, 10, 1, nil))
+	empty := add(tokenAccountData(mint, owner, 0, 1, nil))
+	foreign := add(tokenAccountData(mint, owner, 7, 1, &other))
+	add(tokenAccountData(mint, owner, 5, 2, nil))
+	stub.on("getTokenAccountsByOwner", tokenAccountsByProgram(map[common.PublicKey][]any{common.TokenProgramID: accounts}))
+
+	mintData := make([]byte, token.MintAccountSize)
+	mintData[44] = 6
+	mintData[45] = 1
+	stub.on("getMultipleAccounts", func(params []json.RawMessage) any {
+		var addresses []string
+		_ = json.Unmarshal(params[0], &addresses)
+		values := make([]any, len(addresses))
+		for i, address := range addresses {
+			switch address {
+			case mint.ToBase58():
+				values[i] = accountInfo(common.TokenProgramID.ToBase58(), 1_000_000, mintData)
+			case funded, empty, foreign:
+				values[i] = accountInfo(common.TokenProgramID.ToBase58(), rent, make([]byte, token.TokenAccountSize))
+			}
+		}
+		return withContext(values)
+	})
+
+	result, err := wm.SweepAll(context.Background(), to.ToBase58())
+	require.NoError(t, err)
+	assert.Equal(t, []SweptToken{
+		{Account: funded, Mint: mint.ToBase58(), Amount: 10, Closed: true},
+		{Account: empty, Mint: mint.ToBase58(), Closed: true},
+		{Account: foreign, Mint: mint.ToBase58(), Amount: 7},
+	}, result.Tokens)
+	// 关闭两个账户退回的租金减去为收款方创建关联代币账户的租金
+	assert.Equal(t, uint64(1_000_000_000+2*rent-rent-lamportsPerSignature), result.Lamports)
+	require.Len(t, result.Signatures, 1)
+
+	tx := sentTransaction(t, stub, 0)
+	var kinds []string
+	for _, ins := range tx.Message.Instructions {
+		switch program := tx.Message.Accounts[ins.ProgramIDIndex]; {
+		case program == common.SPLAssociatedTokenAccountProgramID:
+			assert.Equal(t, []byte{byte(associated_token_account.InstructionCreateIdempotent)}, ins.Data)
+			kinds = append(kinds, "create")
+		case program == common.TokenProgramID && ins.Data[0] == byte(token.InstructionTransferChecked):
+			kinds = append(kinds, "transfer")
+		case program == common.TokenProgramID && ins.Data[0] == byte(token.InstructionCloseAccount):
+			kinds = append(kinds, "close")
+		case program == common.SystemProgramID:
+			assert.Equal(t, byte(system.InstructionTransfer), ins.Data[0])
+			kinds = append(kinds, "sol")
+		}
+	}
+	assert.Equal(t, []string{"create", "transfer", "close", "close", "transfer", "sol"}, kinds)
+}
+
+func TestSweepTokensBatches(t *testing.T) {
+	t.Run("plain", func(t *testing.T) { testSweepTokensBatches(t, nil) })
+	t.Run("memo", func(t *testing.T) {
+		testSweepTokensBatches(t, []TxOption{WithMemo(strings.Repeat("m", MaxMemoLength))})
+	})
+}
+
+func testSweepTokensBatches(t *testing.T, opts []TxOption) {
+	wm, stub := newSweepTestWallet(t)
+	owner := wm.PublicKey()
+	mint := types.NewAccount().PublicKey
+
+	var accounts []any
+	for i := 0; i < 40; i++ {
+		accounts = append(accounts, map[string]any{
+			"pubkey":  types.NewAccount().PublicKey.ToBase58(),
+			"account": accountInfo(common.TokenProgramID.ToBase58(), testRentExempt(token.TokenAccountSize), tokenAccountData(mint, owner, 0, 1, nil)),
+		})
+	}
+	stub.on("getTokenAccountsByOwner", tokenAccountsByProgram(map[common.PublicKey][]any{common.TokenProgramID: accounts}))
+	stub.on("getMultipleAccounts", func(params []json.RawMessage) any {
+		var addresses []string
+		_ = json.Unmarshal(params[0], &addresses)
+		return withContext(make([]any, len(addresses)))
+	})
+
+	result, err := wm.SweepTokens(context.Background(), types.NewAccount().PublicKey.ToBase58(), opts...)
+	require.NoError(t, err)
+	assert.Len(t, result.Tokens, 40)
+	assert.Zero(t, result.Lamports)
+	assert.Greater(t, len(result.Signatures), 1)
+	assert.Equal(t, 0, stub.callCount("getFeeForMessage"))
+
+	closed := 0
+	for i := range result.Signatures {
+		tx := sentTransaction(t, stub, i)
+		data, err := tx.Serialize()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(data), maxTransactionSize-sweepSizeHeadroom)
+		closed += len(tx.Message.Instructions) - len(MessageMemos(tx.Message))
+	}
+	assert.Equal(t, 40, closed)
+}
+
+func TestSweepTokensRejectsToken2022(t *testing.T) {
+	wm, stub := newSweepTestWallet(t)
+	owner := wm.PublicKey()
+	account := types.NewAccount().PublicKey
+	mint := types.NewAccount().PublicKey
+	stub.on("getTokenAccountsByOwner", tokenAccountsByProgram(map[common.PublicKey][]any{
+		// 节点按 dataSlice 只返回前 165 字节
+		common.Token2022ProgramID: {map[string]any{
+			"pubkey":  account.ToBase58(),
+			"account": accountInfo(common.Token2022ProgramID.ToBase58(), testRentExempt(token.TokenAccountSize+12), tokenAccountData(mint, owner, 7, 1, nil)),
+		}},
+	}))
+
+	_, err := wm.SweepAll(context.Background(), types.NewAccount().PublicKey.ToBase58())
+	assert.ErrorIs(t, err, ErrToken2022NotSwept)
+	assert.ErrorContains(t, err, account.ToBase58()+" (mint "+mint.ToBase58()+", amount 7)")
+	assert.Zero(t, stub.callCount("sendTransaction"))
+
+	var cfg struct {
+		DataSlice struct{ Length int } `json:"dataSlice"`
+	}
+	require.NoError(t, json.Unmarshal(stub.calls["getTokenAccountsByOwner"][1][2], &cfg))
+	assert.Equal(t, token.TokenAccountSize, cfg.DataSlice.Length)
+}
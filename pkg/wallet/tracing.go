@@ -0,0 +1,77 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName 本包创建的 span 使用的 instrumentation 名称
+const tracerName = "github.com/paxzhu/go-solana/pkg/wallet"
+
+// span 属性名
+const (
+	attrOperation = attribute.Key("solana.operation")
+	attrNetwork   = attribute.Key("solana.network")
+	attrPubkey    = attribute.Key("solana.pubkey")
+	attrMint      = attribute.Key("solana.mint")
+	attrAmount    = attribute.Key("solana.amount")
+	attrSignature = attribute.Key("solana.signature")
+	attrEndpoint  = attribute.Key("rpc.endpoint")
+	attrRPCMethod = attribute.Key("rpc.method")
+)
+
+// NewOTLPTracerProvider 创建通过 OTLP/HTTP 导出 span 的 TracerProvider。
+// 采集器地址等配置从标准的 OTEL_EXPORTER_OTLP_* 环境变量读取，调用方负责 Shutdown。
+func NewOTLPTracerProvider(ctx context.Context, serviceName string) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res)), nil
+}
+
+// tracer 返回 WalletManager 使用的 Tracer，未设置 TracerProvider 时使用全局的 otel.GetTracerProvider()
+func (wm *WalletManager) tracer() trace.Tracer {
+	if wm.TracerProvider != nil {
+		return wm.TracerProvider.Tracer(tracerName)
+	}
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// startOperation 为 WalletManager 的一次操作创建根 span，之后各阶段的 span 都挂在它下面
+func (wm *WalletManager) startOperation(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attrOperation.String(operation), attrNetwork.String(wm.Network))
+	if pubkey := wm.PublicKey(); pubkey != (common.PublicKey{}) {
+		attrs = append(attrs, attrPubkey.String(pubkey.ToBase58()))
+	}
+	return wm.tracer().Start(ctx, "wallet."+operation, trace.WithAttributes(attrs...))
+}
+
+// startSpan 在 ctx 中已有的 span 下创建子 span（例如 build、sign、send），
+// ctx 中没有 span 时返回不记录的 span
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan 记录错误并结束 span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,94 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spansByName 按名称索引已结束的 span
+func spansByName(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	return spans
+}
+
+func spanAttr(s sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTransferSOLTracing(t *testing.T) {
+	stub, _ := newRPCStub(t)
+	stub.on("getBalance", func([]json.RawMessage) any { return withContext(uint64(1_000_000_000)) })
+	stub.on("getLatestBlockhash", func([]json.RawMessage) any {
+		return withContext(map[string]any{"blockhash": testBlockhash, "lastValidBlockHeight": 100})
+	})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	wm := &WalletManager{
+		Client:         newRPCClient(stub.url, nil),
+		Network:        "devnet",
+		Account:        types.NewAccount(),
+		Logger:         NewLogger(io.Discard, nil),
+		TracerProvider: tp,
+	}
+
+	_, err := wm.TransferSOL(context.Background(), types.NewAccount().PublicKey.ToBase58(), 1000)
+	require.NoError(t, err)
+
+	spans := spansByName(recorder)
+	root := spans["wallet.TransferSOL"]
+	require.NotNil(t, root)
+	assert.Equal(t, SOL_MINT_ADDR, spanAttr(root, attrMint).AsString())
+	assert.Equal(t, int64(1000), spanAttr(root, attrAmount).AsInt64())
+	assert.Equal(t, "5Sig", spanAttr(root, attrSignature).AsString())
+
+	// 每个阶段都是同一个 trace 中的子 span
+	for _, name := range []string{"wallet.CheckAmount", "wallet.build", "wallet.sign", "wallet.send", "rpc.getBalance", "rpc.getLatestBlockhash", "rpc.sendTransaction"} {
+		s, ok := spans[name]
+		require.True(t, ok, "missing span %s", name)
+		assert.Equal(t, root.SpanContext().TraceID(), s.SpanContext().TraceID(), name)
+	}
+	assert.Equal(t, root.SpanContext().SpanID(), spans["wallet.send"].Parent().SpanID())
+	assert.Equal(t, spans["wallet.build"].SpanContext().SpanID(), spans["rpc.getLatestBlockhash"].Parent().SpanID())
+	assert.Equal(t, endpointLabel(stub.url), spanAttr(spans["rpc.sendTransaction"], attrEndpoint).AsString())
+}
+
+func TestGetQuoteTracingRecordsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"no route"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	wm := &WalletManager{Network: "mainnet", Logger: NewLogger(io.Discard, nil),
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))}
+
+	_, err := wm.GetQuoteContext(context.Background(), server.URL+"?inputMint=x&api-key=secret")
+	require.Error(t, err)
+
+	span := spansByName(recorder)["wallet.GetQuote"]
+	require.NotNil(t, span)
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.NotContains(t, spanAttr(span, attrEndpoint).AsString(), "secret")
+}
@@ -0,0 +1,421 @@
+package wallet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+// DefaultTriggerOrdersPath 默认的条件单文件
+const DefaultTriggerOrdersPath = "assets/orders.json"
+
+// 条件单类型。买入花费 SOL，卖出得到 SOL
+const (
+	TriggerLimitBuy   = "limit_buy"   // 价格不高于触发价时买入
+	TriggerLimitSell  = "limit_sell"  // 价格不低于触发价时卖出
+	TriggerStopLoss   = "stop_loss"   // 价格跌到触发价及以下时卖出
+	TriggerTakeProfit = "take_profit" // 价格涨到触发价及以上时卖出
+)
+
+// 条件单状态
+const (
+	OrderPending   = "pending"   // 等待触发
+	OrderTriggered = "triggered" // 已触发、正在兑换；进程在兑换完成前退出时保持该状态，需人工核对是否成交
+	OrderExecuted  = "executed"  // 兑换成功
+	OrderFailed    = "failed"    // 兑换失败，不再重试
+	OrderCanceled  = "canceled"  // 已取消
+)
+
+var (
+	// ErrOrderNotFound 没有对应 ID 的条件单
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrOrderNotPending 条件单已触发、完成或取消，不能再取消
+	ErrOrderNotPending = errors.New("order is not pending")
+)
+
+// TriggerOrder 条件单
+type TriggerOrder struct {
+	ID      string `json:"id"`
+	Network string `json:"network"`
+	Type    string `json:"type"` // Trigger* 常量之一
+	Mint    string `json:"mint"`
+	// Amount 买入时为花费的 lamports，卖出时为代币的最小单位
+	Amount uint64 `json:"amount"`
+	// Price 触发价格，单位由价格来源决定：QuotePriceSource 为每个代币的 SOL 价格，FeedPriceSource 为 USD 价格
+	Price       float64 `json:"price"`
+	SlippageBps int     `json:"slippageBps"` // 为 0 时使用默认滑点
+
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	TriggeredAt    *time.Time `json:"triggeredAt,omitempty"`
+	TriggeredPrice float64    `json:"triggeredPrice,omitempty"`
+	Signature      string     `json:"signature,omitempty"`
+	Error          string     `json:"error,omitempty"`
+}
+
+// buying 买入单用 SOL 兑换 Mint，其余卖出 Mint 换回 SOL
+func (o *TriggerOrder) buying() bool {
+	return o.Type == TriggerLimitBuy
+}
+
+// slippage 条件单的滑点，未设置时使用默认值
+func (o *TriggerOrder) slippage() int {
+	if o.SlippageBps == 0 {
+		return defaultSlippageBps
+	}
+	return o.SlippageBps
+}
+
+// triggered 判断当前价格是否满足触发条件
+func (o *TriggerOrder) triggered(price float64) bool {
+	switch o.Type {
+	case TriggerLimitBuy, TriggerStopLoss:
+		return price <= o.Price
+	default:
+		return price >= o.Price
+	}
+}
+
+// TriggerPriceSource 条件单的价格来源
+type TriggerPriceSource interface {
+	OrderPrice(ctx context.Context, order TriggerOrder) (float64, error)
+}
+
+// QuotePriceSource 按条件单的金额向 Jupiter 询价，价格为每个完整代币的 SOL 数量，已包含价格影响
+type QuotePriceSource struct {
+	Wallet   *WalletManager
+	QuoteAPI string // 为空时使用 JupiterQuoteAPI
+}
+
+// OrderPrice 卖出单报价 Mint -> SOL，买入单报价 SOL -> Mint
+func (s *QuotePriceSource) OrderPrice(ctx context.Context, order TriggerOrder) (float64, error) {
+	decimals, err := s.Wallet.mintDecimals(ctx, common.PublicKeyFromString(order.Mint))
+	if err != nil {
+		return 0, err
+	}
+	input, output := order.Mint, SOL_MINT_ADDR
+	if order.buying() {
+		input, output = SOL_MINT_ADDR, order.Mint
+	}
+	api := s.QuoteAPI
+	if api == "" {
+		api = JupiterQuoteAPI
+	}
+	quote, err := s.Wallet.GetQuoteContext(ctx, quoteURL(api, input, output, order.Amount, order.slippage()))
+	if err != nil {
+		return 0, err
+	}
+	out, err := strconv.ParseUint(quote.OutAmount, 10, 64)
+	if err != nil || out == 0 {
+		return 0, fmt.Errorf("invalid quote outAmount %q", quote.OutAmount)
+	}
+	sol := func(lamports uint64) float64 { return float64(lamports) / math.Pow10(solDecimals) }
+	tokens := func(amount uint64) float64 { return float64(amount) / math.Pow10(int(decimals)) }
+	if order.buying() {
+		return sol(order.Amount) / tokens(out), nil
+	}
+	return sol(out) / tokens(order.Amount), nil
+}
+
+// FeedPriceSource 使用价格接口的 USD 价格，与金额无关
+type FeedPriceSource struct {
+	Wallet   *WalletManager
+	PriceAPI string // 为空时使用 JupiterPriceAPI
+}
+
+// OrderPrice 返回 Mint 的 USD 价格
+func (s *FeedPriceSource) OrderPrice(ctx context.Context, order TriggerOrder) (float64, error) {
+	var opts []PortfolioOption
+	if s.PriceAPI != "" {
+		opts = append(opts, WithPriceAPI(s.PriceAPI))
+	}
+	prices, err := s.Wallet.GetPrices(ctx, []string{order.Mint}, opts...)
+	if err != nil {
+		return 0, err
+	}
+	price, ok := prices[order.Mint]
+	if !ok {
+		return 0, fmt.Errorf("no price for %s", order.Mint)
+	}
+	return price.USD, nil
+}
+
+// TriggerEngine 轮询价格并在满足条件时通过 Swap 执行条件单。
+// 条件单保存在 JSON 文件中，重启后继续生效；Check 每轮重新读取文件，其他进程下的单和撤单在下一轮生效。
+// 每次修改都在文件锁内重新读取后写回，多个进程同时修改时不会互相覆盖。
+type TriggerEngine struct {
+	wm     *WalletManager
+	source TriggerPriceSource
+	path   string
+	// swap 执行兑换，默认为 wm.Swap，测试时替换
+	swap func(ctx context.Context, inputMint string, outputMint string, amount uint64, slippageBps int) (string, error)
+
+	mu     sync.Mutex
+	orders []TriggerOrder
+}
+
+type triggerOrdersFile struct {
+	Orders []TriggerOrder `json:"orders"`
+}
+
+// OpenTriggerEngine 打开条件单文件（不存在时为空），只处理当前网络的条件单
+func (wm *WalletManager) OpenTriggerEngine(path string, source TriggerPriceSource) (*TriggerEngine, error) {
+	e := &TriggerEngine{wm: wm, source: source, path: path}
+	e.swap = func(ctx context.Context, inputMint string, outputMint string, amount uint64, slippageBps int) (string, error) {
+		_, txhash, err := wm.Swap(ctx, inputMint, outputMint, amount, slippageBps)
+		return txhash, err
+	}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Place 校验并保存条件单，返回分配了 ID 的条件单
+func (e *TriggerEngine) Place(order TriggerOrder) (TriggerOrder, error) {
+	switch order.Type {
+	case TriggerLimitBuy, TriggerLimitSell, TriggerStopLoss, TriggerTakeProfit:
+	default:
+		return TriggerOrder{}, fmt.Errorf("unknown order type %q", order.Type)
+	}
+	if _, err := ParseAddress(order.Mint); err != nil {
+		return TriggerOrder{}, err
+	}
+	if order.Mint == SOL_MINT_ADDR {
+		return TriggerOrder{}, errors.New("mint must not be SOL")
+	}
+	if order.Amount == 0 {
+		return TriggerOrder{}, errors.New("amount must be positive")
+	}
+	if !(order.Price > 0) || math.IsInf(order.Price, 0) {
+		return TriggerOrder{}, errors.New("price must be positive")
+	}
+	if order.SlippageBps < 0 {
+		return TriggerOrder{}, errors.New("slippage must not be negative")
+	}
+	if err := e.wm.Policy.CheckSlippage("swap", order.slippage()); err != nil {
+		return TriggerOrder{}, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return TriggerOrder{}, fmt.Errorf("failed to generate order id: %w", err)
+	}
+	order.ID = hex.EncodeToString(id)
+	order.Network = e.wm.Network
+	order.Status = OrderPending
+	order.CreatedAt = time.Now().UTC()
+	order.TriggeredAt, order.TriggeredPrice, order.Signature, order.Error = nil, 0, "", ""
+
+	if _, err := e.transact(func() (bool, error) {
+		e.orders = append(e.orders, order)
+		return true, nil
+	}); err != nil {
+		return TriggerOrder{}, err
+	}
+	return order, nil
+}
+
+// Cancel 取消等待触发的条件单
+func (e *TriggerEngine) Cancel(id string) error {
+	_, err := e.transact(func() (bool, error) {
+		i := e.find(id)
+		if i < 0 {
+			return false, fmt.Errorf("%w: %s", ErrOrderNotFound, id)
+		}
+		if e.orders[i].Status != OrderPending {
+			return false, fmt.Errorf("%w: %s is %s", ErrOrderNotPending, id, e.orders[i].Status)
+		}
+		e.orders[i].Status = OrderCanceled
+		return true, nil
+	})
+	return err
+}
+
+// Orders 按创建顺序返回所有网络的条件单
+func (e *TriggerEngine) Orders() []TriggerOrder {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]TriggerOrder(nil), e.orders...)
+}
+
+// Check 检查当前网络所有等待触发的条件单，执行满足条件的，返回本轮触发的条件单（已更新为 executed 或 failed）。
+// 单个条件单查询价格失败不影响其他条件单，所有价格错误合并返回。
+func (e *TriggerEngine) Check(ctx context.Context) (_ []TriggerOrder, err error) {
+	ctx, span := e.wm.startOperation(ctx, "CheckTriggers")
+	defer func() { endSpan(span, err) }()
+
+	// 其他进程（如命令行）可能修改了文件，每轮重新读取
+	e.mu.Lock()
+	err = e.load()
+	e.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var pending []TriggerOrder
+	for _, o := range e.Orders() {
+		if o.Status == OrderPending && o.Network == e.wm.Network {
+			pending = append(pending, o)
+		}
+	}
+
+	var done []TriggerOrder
+	var errs []error
+	for _, o := range pending {
+		price, err := e.source.OrderPrice(ctx, o)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("order %s: %w", o.ID, err))
+			continue
+		}
+		if !o.triggered(price) {
+			continue
+		}
+		// 兑换前先保存为 triggered，进程中途退出时不会重复下单
+		ok, err := e.update(o.ID, func(o *TriggerOrder) bool {
+			if o.Status != OrderPending {
+				return false
+			}
+			now := time.Now().UTC()
+			o.Status, o.TriggeredAt, o.TriggeredPrice = OrderTriggered, &now, price
+			return true
+		})
+		if err != nil {
+			return done, err
+		}
+		if !ok {
+			continue // 检查期间被取消
+		}
+		e.wm.logger().InfoContext(ctx, "order triggered", "operation", "trigger", "network", e.wm.Network,
+			"order", o.ID, "type", o.Type, "mint", o.Mint, "price", price, "trigger_price", o.Price)
+
+		input, output := o.Mint, SOL_MINT_ADDR
+		if o.buying() {
+			input, output = SOL_MINT_ADDR, o.Mint
+		}
+		txhash, swapErr := e.swap(ctx, input, output, o.Amount, o.slippage())
+		var result TriggerOrder
+		if _, err := e.update(o.ID, func(o *TriggerOrder) bool {
+			o.Status, o.Signature = OrderExecuted, txhash
+			if swapErr != nil {
+				o.Status, o.Error = OrderFailed, swapErr.Error()
+			}
+			result = *o
+			return true
+		}); err != nil {
+			return done, err
+		}
+		done = append(done, result)
+	}
+	return done, errors.Join(errs...)
+}
+
+// Run 每隔 interval 调用一次 Check，直到 ctx 取消；价格查询失败只记录日志，onDone 接收每个触发的条件单
+func (e *TriggerEngine) Run(ctx context.Context, interval time.Duration, onDone func(TriggerOrder)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		done, err := e.Check(ctx)
+		for _, o := range done {
+			onDone(o)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			e.wm.logger().WarnContext(ctx, "trigger check failed", "operation", "trigger", "network", e.wm.Network, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// update 修改条件单并保存，fn 看到的是文件中最新的条件单，返回 false 时不修改
+func (e *TriggerEngine) update(id string, fn func(*TriggerOrder) bool) (bool, error) {
+	return e.transact(func() (bool, error) {
+		i := e.find(id)
+		return i >= 0 && fn(&e.orders[i]), nil
+	})
+}
+
+// transact 持有文件锁重新读取条件单，由 fn 修改后保存，fn 返回 false 或错误时不保存。
+// 其他进程（如命令行撤单）的修改不会被内存中的旧数据覆盖。
+func (e *TriggerEngine) transact(fn func() (bool, error)) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	unlock, err := lockFile(e.path)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	if err := e.load(); err != nil {
+		return false, err
+	}
+	ok, err := fn()
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := e.save(); err != nil {
+		// 丢弃没有保存成功的修改
+		_ = e.load()
+		return false, err
+	}
+	return true, nil
+}
+
+// find 返回条件单的下标，调用方需持有锁
+func (e *TriggerEngine) find(id string) int {
+	for i := range e.orders {
+		if e.orders[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// load 从文件读取条件单，文件不存在时为空，调用方需持有锁
+func (e *TriggerEngine) load() error {
+	data, err := os.ReadFile(e.path)
+	if errors.Is(err, os.ErrNotExist) {
+		e.orders = nil
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read orders: %w", err)
+	}
+	var file triggerOrdersFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse orders %s: %w", e.path, err)
+	}
+	e.orders = file.Orders
+	return nil
+}
+
+// save 保存所有条件单，调用方需持有锁
+func (e *TriggerEngine) save() error {
+	file := triggerOrdersFile{Orders: e.orders}
+	if file.Orders == nil {
+		file.Orders = []TriggerOrder{}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(e.path, data, 0o644, true); err != nil {
+		return fmt.Errorf("failed to write orders: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticPriceSource 按 mint 返回固定价格，没有价格的 mint 返回错误
+type staticPriceSource map[string]float64
+
+func (s staticPriceSource) OrderPrice(_ context.Context, order TriggerOrder) (float64, error) {
+	price, ok := s[order.Mint]
+	if !ok {
+		return 0, errors.New("no price")
+	}
+	return price, nil
+}
+
+func TestTriggerEnginePlaceAndCancel(t *testing.T) {
+	wm, _ := newErrorTestWallet(t, rpcStubError{})
+	path := filepath.Join(t.TempDir(), "orders.json")
+	e, err := wm.OpenTriggerEngine(path, staticPriceSource{})
+	require.NoError(t, err)
+	mint := types.NewAccount().PublicKey.ToBase58()
+
+	for _, o := range []TriggerOrder{
+		{Type: "market", Mint: mint, Amount: 1, Price: 1},
+		{Type: TriggerStopLoss, Mint: "bad", Amount: 1, Price: 1},
+		{Type: TriggerStopLoss, Mint: SOL_MINT_ADDR, Amount: 1, Price: 1},
+		{Type: TriggerStopLoss, Mint: mint, Price: 1},
+		{Type: TriggerStopLoss, Mint: mint, Amount: 1},
+		{Type: TriggerStopLoss, Mint: mint, Amount: 1, Price: 1, SlippageBps: -1},
+	} {
+		_, err := e.Place(o)
+		assert.Error(t, err, o)
+	}
+
+	order, err := e.Place(TriggerOrder{Type: TriggerStopLoss, Mint: mint, Amount: 1_000, Price: 0.5})
+	require.NoError(t, err)
+	assert.Len(t, order.ID, 16)
+	assert.Equal(t, OrderPending, order.Status)
+	assert.Equal(t, "devnet", order.Network)
+
+	// 重新打开后条件单仍在
+	e, err = wm.OpenTriggerEngine(path, staticPriceSource{})
+	require.NoError(t, err)
+	assert.Equal(t, []TriggerOrder{order}, e.Orders())
+
+	require.NoError(t, e.Cancel(order.ID))
+	assert.ErrorIs(t, e.Cancel(order.ID), ErrOrderNotPending)
+	assert.ErrorIs(t, e.Cancel("missing"), ErrOrderNotFound)
+	e, err = wm.OpenTriggerEngine(path, staticPriceSource{})
+	require.NoError(t, err)
+	assert.Equal(t, OrderCanceled, e.Orders()[0].Status)
+}
+
+func TestTriggerEngineCheck(t *testing.T) {
+	wm, _ := newErrorTestWallet(t, rpcStubError{})
+	path := filepath.Join(t.TempDir(), "orders.json")
+	falling := types.NewAccount().PublicKey.ToBase58()
+	unpriced := types.NewAccount().PublicKey.ToBase58()
+	prices := staticPriceSource{falling: 0.9}
+	e, err := wm.OpenTriggerEngine(path, prices)
+	require.NoError(t, err)
+
+	type swapCall struct {
+		input, output string
+		amount        uint64
+		slippageBps   int
+	}
+	var swaps []swapCall
+	e.swap = func(_ context.Context, input string, output string, amount uint64, slippageBps int) (string, error) {
+		swaps = append(swaps, swapCall{input, output, amount, slippageBps})
+		if input == SOL_MINT_ADDR {
+			return "", errors.New("insufficient funds")
+		}
+		return "5Sig", nil
+	}
+
+	stopLoss, err := e.Place(TriggerOrder{Type: TriggerStopLoss, Mint: falling, Amount: 1_000, Price: 1})
+	require.NoError(t, err)
+	takeProfit, err := e.Place(TriggerOrder{Type: TriggerTakeProfit, Mint: falling, Amount: 1_000, Price: 2})
+	require.NoError(t, err)
+	limitBuy, err := e.Place(TriggerOrder{Type: TriggerLimitBuy, Mint: falling, Amount: 5_000, Price: 0.95, SlippageBps: 50})
+	require.NoError(t, err)
+	_, err = e.Place(TriggerOrder{Type: TriggerLimitSell, Mint: unpriced, Amount: 1, Price: 1})
+	require.NoError(t, err)
+	canceled, err := e.Place(TriggerOrder{Type: TriggerStopLoss, Mint: falling, Amount: 1, Price: 1})
+	require.NoError(t, err)
+
+	// 其他进程撤单后，下一轮检查读取到
+	other, err := wm.OpenTriggerEngine(path, prices)
+	require.NoError(t, err)
+	require.NoError(t, other.Cancel(canceled.ID))
+
+	done, err := e.Check(context.Background())
+	assert.ErrorContains(t, err, "no price")
+	require.Len(t, done, 2)
+	assert.Equal(t, []swapCall{
+		{falling, SOL_MINT_ADDR, 1_000, defaultSlippageBps},
+		{SOL_MINT_ADDR, falling, 5_000, 50},
+	}, swaps)
+	assert.Equal(t, stopLoss.ID, done[0].ID)
+	assert.Equal(t, OrderExecuted, done[0].Status)
+	assert.Equal(t, "5Sig", done[0].Signature)
+	assert.Equal(t, 0.9, done[0].TriggeredPrice)
+	assert.NotNil(t, done[0].TriggeredAt)
+	assert.Equal(t, limitBuy.ID, done[1].ID)
+	assert.Equal(t, OrderFailed, done[1].Status)
+	assert.Equal(t, "insufficient funds", done[1].Error)
+
+	// 已执行的条件单不会重复执行，状态已保存
+	prices[falling] = 2
+	done, err = e.Check(context.Background())
+	assert.Error(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, takeProfit.ID, done[0].ID)
+	assert.Len(t, swaps, 3)
+
+	e, err = wm.OpenTriggerEngine(path, prices)
+	require.NoError(t, err)
+	statuses := map[string]string{}
+	for _, o := range e.Orders() {
+		statuses[o.ID] = o.Status
+	}
+	assert.Equal(t, OrderExecuted, statuses[stopLoss.ID])
+	assert.Equal(t, OrderExecuted, statuses[takeProfit.ID])
+	assert.Equal(t, OrderFailed, statuses[limitBuy.ID])
+	assert.Equal(t, OrderCanceled, statuses[canceled.ID])
+}
+
+func TestQuotePriceSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 0.25 SOL / 代币
+		out := "500000000"
+		if r.URL.Query().Get("inputMint") == SOL_MINT_ADDR {
+			out = "4000000"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"inAmount": r.URL.Query().Get("amount"), "outAmount": out})
+	}))
+	defer server.Close()
+
+	// 带扩展的 Token-2022 mint 数据比基础结构长
+	for name, tc := range map[string]struct {
+		program common.PublicKey
+		size    int
+	}{
+		"token":               {common.TokenProgramID, token.MintAccountSize},
+		"token-2022 extended": {common.Token2022ProgramID, 234},
+	} {
+		t.Run(name, func(t *testing.T) {
+			wm, stub := newErrorTestWallet(t, rpcStubError{})
+			mint := types.NewAccount().PublicKey.ToBase58()
+			mintData := make([]byte, tc.size)
+			mintData[44] = 6
+			mintData[45] = 1
+			stub.on("getAccountInfo", func([]json.RawMessage) any {
+				return withContext(accountInfo(tc.program.ToBase58(), 1_000_000, mintData))
+			})
+
+			source := &QuotePriceSource{Wallet: wm, QuoteAPI: server.URL}
+			price, err := source.OrderPrice(context.Background(), TriggerOrder{Type: TriggerStopLoss, Mint: mint, Amount: 2_000_000})
+			require.NoError(t, err)
+			assert.InDelta(t, 0.25, price, 1e-12)
+			price, err = source.OrderPrice(context.Background(), TriggerOrder{Type: TriggerLimitBuy, Mint: mint, Amount: 1_000_000_000})
+			require.NoError(t, err)
+			assert.InDelta(t, 0.25, price, 1e-12)
+		})
+	}
+}
+
+// cancelingPriceSource 查询价格时通过另一个引擎撤单，模拟命令行在检查期间撤单
+type cancelingPriceSource struct {
+	other *TriggerEngine
+	price float64
+}
+
+func (s *cancelingPriceSource) OrderPrice(_ context.Context, order TriggerOrder) (float64, error) {
+	if err := s.other.Cancel(order.ID); err != nil {
+		return 0, err
+	}
+	return s.price, nil
+}
+
+func TestTriggerEngineKeepsOtherProcessChanges(t *testing.T) {
+	wm, _ := newErrorTestWallet(t, rpcStubError{})
+	path := filepath.Join(t.TempDir(), "orders.json")
+	mint := types.NewAccount().PublicKey.ToBase58()
+	source := &cancelingPriceSource{price: 0.1}
+	e, err := wm.OpenTriggerEngine(path, source)
+	require.NoError(t, err)
+	other, err := wm.OpenTriggerEngine(path, staticPriceSource{})
+	require.NoError(t, err)
+	source.other = other
+
+	// 两个引擎各自下单，都保留
+	first, err := e.Place(TriggerOrder{Type: TriggerStopLoss, Mint: mint, Amount: 1, Price: 0.5})
+	require.NoError(t, err)
+	second, err := other.Place(TriggerOrder{Type: TriggerStopLoss, Mint: mint, Amount: 2, Price: 0.5})
+	require.NoError(t, err)
+	require.NoError(t, e.Cancel(second.ID))
+
+	// 检查期间被另一个引擎撤单的条件单不再触发，撤单不会被覆盖
+	e.swap = func(context.Context, string, string, uint64, int) (string, error) {
+		t.Fatal("canceled order must not be swapped")
+		return "", nil
+	}
+	done, err := e.Check(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, done)
+
+	reopened, err := wm.OpenTriggerEngine(path, staticPriceSource{})
+	require.NoError(t, err)
+	orders := reopened.Orders()
+	require.Len(t, orders, 2)
+	assert.Equal(t, first.ID, orders[0].ID)
+	assert.Equal(t, OrderCanceled, orders[0].Status)
+	assert.Equal(t, second.ID, orders[1].ID)
+	assert.Equal(t, OrderCanceled, orders[1].Status)
+}
@@ -0,0 +1,182 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/compute_budget"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TxOption 交易构建选项，所有构建交易的方法都接受该选项
+type TxOption func(*txOptions)
+
+type txOptions struct {
+	nonceAccount   *common.PublicKey
+	nonceAuthority Signer
+	// priorityFee 每个计算单元的优先费（micro-lamports），computeUnitLimit 为 0 时不设置计算单元上限
+	priorityFee      *uint64
+	computeUnitLimit uint32
+	// memo 不为空时在交易末尾加入 Memo 指令
+	memo *string
+	// beforeSign 在签名前检查交易消息，返回错误时不签名（用于 PolicyEngine）
+	beforeSign func(ctx context.Context, message types.Message) error
+	// err 选项参数无效时记录，组装交易时返回
+	err error
+}
+
+func newTxOptions(opts []TxOption) txOptions {
+	var o txOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithDurableNonce 使用持久 nonce 代替最近区块哈希构建交易。
+// 最近区块哈希约 1 分钟后过期，使用 nonce 的交易在 nonce 被推进前一直有效，
+// 适合多方审批、冷钱包签名等耗时较长的流程。
+// nonceAuthority 为 nil 时由手续费支付账户作为 nonce 权限账户签名。
+func WithDurableNonce(nonceAccount string, nonceAuthority Signer) TxOption {
+	return func(o *txOptions) {
+		pubkey, err := ParseAddress(nonceAccount)
+		if err != nil {
+			o.err = fmt.Errorf("invalid nonce account: %w", err)
+			return
+		}
+		o.nonceAccount = &pubkey
+		o.nonceAuthority = nonceAuthority
+	}
+}
+
+// WithPriorityFee 在交易开头加入 ComputeBudget 指令，按每个计算单元 microLamports 支付优先费。
+// computeUnitLimit 不为 0 时同时设置计算单元上限，优先费总额为 microLamports * 上限 / 1e6。
+func WithPriorityFee(microLamports uint64, computeUnitLimit uint32) TxOption {
+	return func(o *txOptions) {
+		o.priorityFee = &microLamports
+		o.computeUnitLimit = computeUnitLimit
+	}
+}
+
+// buildTransaction 组装并签名交易，signers 的第一个账户为手续费支付账户。
+func buildTransaction(
+	ctx context.Context,
+	c *client.Client,
+	instructions []types.Instruction,
+	signers []Signer,
+	opts ...TxOption,
+) (types.Transaction, error) {
+	if len(signers) == 0 {
+		return types.Transaction{}, fmt.Errorf("no fee payer provided")
+	}
+	o := newTxOptions(opts)
+
+	buildCtx, span := startSpan(ctx, "wallet.build", attribute.Int("solana.instructions", len(instructions)))
+	message, err := buildMessage(buildCtx, c, signers[0].PublicKey(), instructions, o)
+	endSpan(span, err)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	if o.beforeSign != nil {
+		if err := o.beforeSign(ctx, message); err != nil {
+			return types.Transaction{}, err
+		}
+	}
+	if o.nonceAuthority != nil {
+		signers = appendSigner(signers, o.nonceAuthority)
+	}
+
+	tx, err := types.NewTransaction(types.NewTransactionParam{Message: message})
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	if err := signMessage(ctx, &tx, signers...); err != nil {
+		return types.Transaction{}, err
+	}
+	return tx, nil
+}
+
+// buildMessage 组装交易消息。
+// 默认使用最近区块哈希；设置了持久 nonce 时，会在指令最前面插入 AdvanceNonceAccount，
+// 并以 nonce 账户中保存的 nonce 作为 RecentBlockhash。
+// 设置了优先费时，ComputeBudget 指令紧跟在 AdvanceNonceAccount 之后（没有 nonce 时位于最前面）。
+// 设置了 memo 时，Memo 指令位于最后。
+func buildMessage(
+	ctx context.Context,
+	c *client.Client,
+	feePayer common.PublicKey,
+	instructions []types.Instruction,
+	o txOptions,
+) (types.Message, error) {
+	if o.err != nil {
+		return types.Message{}, o.err
+	}
+	if o.memo != nil {
+		ins, err := memoInstruction(feePayer, *o.memo)
+		if err != nil {
+			return types.Message{}, err
+		}
+		instructions = append(instructions[:len(instructions):len(instructions)], ins)
+	}
+	if o.priorityFee != nil {
+		budget := []types.Instruction{
+			compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{MicroLamports: *o.priorityFee}),
+		}
+		if o.computeUnitLimit > 0 {
+			budget = append(budget, compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{Units: o.computeUnitLimit}))
+		}
+		instructions = append(budget, instructions...)
+	}
+
+	var blockhash string
+	if o.nonceAccount == nil {
+		res, err := c.GetLatestBlockhash(ctx)
+		if err != nil {
+			return types.Message{}, fmt.Errorf("failed to get latest blockhash: %w", wrapRPCError(err, nil))
+		}
+		blockhash = res.Blockhash
+	} else {
+		authority := feePayer
+		if o.nonceAuthority != nil {
+			authority = o.nonceAuthority.PublicKey()
+		}
+
+		nonce, err := getNonceAccount(ctx, c, o.nonceAccount.ToBase58())
+		if err != nil {
+			return types.Message{}, err
+		}
+		if nonce.Authority != authority.ToBase58() {
+			return types.Message{}, fmt.Errorf("nonce authority mismatch: account %s is controlled by %s, not %s",
+				o.nonceAccount.ToBase58(), nonce.Authority, authority.ToBase58())
+		}
+		blockhash = nonce.Nonce
+
+		// AdvanceNonceAccount 必须是交易中的第一条指令
+		instructions = append([]types.Instruction{
+			system.AdvanceNonceAccount(system.AdvanceNonceAccountParam{
+				Nonce: *o.nonceAccount,
+				Auth:  authority,
+			}),
+		}, instructions...)
+	}
+
+	return types.NewMessage(types.NewMessageParam{
+		FeePayer:        feePayer,
+		RecentBlockhash: blockhash,
+		Instructions:    instructions,
+	}), nil
+}
+
+// appendSigner 追加签名者，已存在时跳过
+func appendSigner(signers []Signer, signer Signer) []Signer {
+	for _, s := range signers {
+		if s.PublicKey() == signer.PublicKey() {
+			return signers
+		}
+	}
+	return append(signers, signer)
+}
@@ -1,9 +1,7 @@
 package wallet
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
+	"log/slog"
 
 	"github.com/blocto/solana-go-sdk/types"
 )
@@ -11,26 +9,37 @@ import (
 func GenerateWallets(numWallets int) error {
 	for i := 0; i < numWallets; i++ {
 		wallet := types.NewAccount() // Generate new wallet
-		privateKey := wallet.PrivateKey
-		publicKey := wallet.PublicKey.ToBase58()
 
-		// Create a JSON file for each private key using the public key in the filename
-		// Truncate the public key for the filename
-		truncatedPublicKey := publicKey[:10] // Use first 10 characters
-		filename := fmt.Sprintf("assets/wallet_%s.json", truncatedPublicKey)
-		file, err := os.Create(filename)
+		filename, err := SaveWallet(DefaultKeyDir, wallet)
 		if err != nil {
-			return fmt.Errorf("unable to create file: %v", err)
+			return err
 		}
-		defer file.Close()
+		slog.Info("wallet generated", "operation", "generate_wallets", "pubkey", wallet.PublicKey.ToBase58(), "path", filename)
+	}
 
-		// Write private key to JSON file
-		if err := json.NewEncoder(file).Encode(privateKey); err != nil {
-			return fmt.Errorf("unable to write to file: %v", err)
-		}
+	return nil
+}
 
-		fmt.Printf("Wallet with Public Key: %s, Private Key saved to %s\n", publicKey, filename)
+// GenerateWalletsFromMnemonic 从同一助记词派生序号 0..numWallets-1 的钱包并保存，
+// 丢失钱包文件后可以用备份的助记词重新生成完全相同的钱包；已存在的钱包文件会跳过，重复恢复不会产生多余文件
+func GenerateWalletsFromMnemonic(mnemonic string, passphrase string, numWallets int) error {
+	accounts, err := DeriveAccounts(mnemonic, passphrase, 0, numWallets)
+	if err != nil {
+		return err
 	}
 
+	for i, wallet := range accounts {
+		filename, existed, err := saveWalletIfMissing(DefaultKeyDir, wallet)
+		if err != nil {
+			return err
+		}
+		if existed {
+			slog.Info("wallet already exists", "operation", "generate_wallets_from_mnemonic",
+				"index", i, "pubkey", wallet.PublicKey.ToBase58(), "path", filename)
+			continue
+		}
+		slog.Info("wallet restored", "operation", "generate_wallets_from_mnemonic",
+			"index", i, "derivationPath", DerivationPath(uint32(i)), "pubkey", wallet.PublicKey.ToBase58(), "path", filename)
+	}
 	return nil
 }
@@ -0,0 +1,197 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// base58Alphabet Solana 地址使用的 base58 字符表（不含 0、O、I、l）
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// VanityOptions 靓号地址搜索条件
+type VanityOptions struct {
+	Prefix     string
+	Suffix     string
+	IgnoreCase bool
+	Workers    int // 并发数，0 表示使用全部 CPU 核心
+
+	// Progress 可选，每隔 ProgressInterval（默认 1 秒）回调一次搜索进度
+	Progress         func(VanityStats)
+	ProgressInterval time.Duration
+}
+
+// VanityStats 搜索进度
+type VanityStats struct {
+	Attempts         uint64
+	Elapsed          time.Duration
+	KeysPerSecond    float64
+	ExpectedAttempts float64 // 期望尝试次数（50% 概率在该次数的 ln2 倍内找到）
+}
+
+// VanityResult 搜索结果
+type VanityResult struct {
+	Account  types.Account
+	Filename string // 通过 GenerateVanityWallet 保存后的文件路径
+	Attempts uint64
+	Elapsed  time.Duration
+}
+
+// EstimateVanityDifficulty 估算匹配前缀/后缀的期望尝试次数。
+// 按每个字符均匀分布估算，首字符实际分布并不均匀，结果仅供参考。
+func EstimateVanityDifficulty(prefix string, suffix string, ignoreCase bool) (float64, error) {
+	expected := 1.0
+	for _, c := range prefix + suffix {
+		matches := 0
+		for _, a := range base58Alphabet {
+			if a == c || (ignoreCase && strings.EqualFold(string(a), string(c))) {
+				matches++
+			}
+		}
+		if matches == 0 {
+			return 0, fmt.Errorf("invalid character %q: base58 addresses never contain 0, O, I or l", c)
+		}
+		expected *= float64(len(base58Alphabet)) / float64(matches)
+	}
+	return expected, nil
+}
+
+// FindVanityAccount 使用多个 goroutine 搜索匹配前缀/后缀的账户，ctx 取消时返回 ctx.Err()
+func FindVanityAccount(ctx context.Context, opts VanityOptions) (*VanityResult, error) {
+	if opts.Prefix == "" && opts.Suffix == "" {
+		return nil, fmt.Errorf("prefix or suffix is required")
+	}
+	expected, err := EstimateVanityDifficulty(opts.Prefix, opts.Suffix, opts.IgnoreCase)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts atomic.Uint64
+		found    = make(chan types.Account, 1)
+		wg       sync.WaitGroup
+		start    = time.Now()
+	)
+	match := vanityMatcher(opts.Prefix, opts.Suffix, opts.IgnoreCase)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := uint64(1); ; n++ {
+				// 每 256 次检查一次取消并汇总计数，减少原子操作开销
+				if n%256 == 0 {
+					attempts.Add(256)
+					if ctx.Err() != nil {
+						return
+					}
+				}
+
+				publicKey, privateKey, err := ed25519.GenerateKey(nil)
+				if err != nil {
+					continue
+				}
+				if !match(base58.Encode(publicKey)) {
+					continue
+				}
+
+				account, err := types.AccountFromBytes(privateKey)
+				if err != nil {
+					continue
+				}
+				attempts.Add(n % 256)
+				select {
+				case found <- account:
+					cancel()
+				default:
+				}
+				return
+			}
+		}()
+	}
+
+	stats := func() VanityStats {
+		elapsed := time.Since(start)
+		n := attempts.Load()
+		return VanityStats{
+			Attempts:         n,
+			Elapsed:          elapsed,
+			KeysPerSecond:    float64(n) / math.Max(elapsed.Seconds(), 1e-9),
+			ExpectedAttempts: expected,
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case account := <-found:
+			cancel()
+			wg.Wait()
+			s := stats()
+			return &VanityResult{Account: account, Attempts: s.Attempts, Elapsed: s.Elapsed}, nil
+		case <-ctx.Done():
+			wg.Wait()
+			// 取消与找到结果可能同时发生，优先返回结果
+			select {
+			case account := <-found:
+				s := stats()
+				return &VanityResult{Account: account, Attempts: s.Attempts, Elapsed: s.Elapsed}, nil
+			default:
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+			if opts.Progress != nil {
+				opts.Progress(stats())
+			}
+		}
+	}
+}
+
+// GenerateVanityWallet 搜索靓号地址并按 GenerateWallets 相同的方式保存私钥文件
+func GenerateVanityWallet(ctx context.Context, opts VanityOptions) (*VanityResult, error) {
+	result, err := FindVanityAccount(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	filename, err := SaveWallet(DefaultKeyDir, result.Account)
+	if err != nil {
+		return nil, err
+	}
+	result.Filename = filename
+	return result, nil
+}
+
+func vanityMatcher(prefix string, suffix string, ignoreCase bool) func(string) bool {
+	if ignoreCase {
+		return func(address string) bool {
+			return len(address) >= len(prefix)+len(suffix) &&
+				strings.EqualFold(address[:len(prefix)], prefix) &&
+				strings.EqualFold(address[len(address)-len(suffix):], suffix)
+		}
+	}
+	return func(address string) bool {
+		return strings.HasPrefix(address, prefix) && strings.HasSuffix(address, suffix)
+	}
+}
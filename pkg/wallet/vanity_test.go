@@ -0,0 +1,44 @@
+package wallet
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateVanityDifficulty(t *testing.T) {
+	expected, err := EstimateVanityDifficulty("ab", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, 58.0*58.0, expected)
+
+	// 忽略大小写时 a/A 都能匹配；l 只能匹配 L
+	expected, err = EstimateVanityDifficulty("a", "l", true)
+	require.NoError(t, err)
+	assert.Equal(t, 29.0*58.0, expected)
+
+	_, err = EstimateVanityDifficulty("0x", "", false)
+	assert.Error(t, err)
+	_, err = EstimateVanityDifficulty("l", "", false)
+	assert.Error(t, err)
+}
+
+func TestFindVanityAccount(t *testing.T) {
+	result, err := FindVanityAccount(context.Background(), VanityOptions{Prefix: "a", Suffix: "b", IgnoreCase: true, Workers: 2})
+	require.NoError(t, err)
+	address := result.Account.PublicKey.ToBase58()
+	assert.True(t, strings.EqualFold(address[:1], "a"), address)
+	assert.True(t, strings.EqualFold(address[len(address)-1:], "b"), address)
+	assert.NotZero(t, result.Attempts)
+}
+
+func TestFindVanityAccountCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := FindVanityAccount(ctx, VanityOptions{Prefix: "zzzzzzzz", Workers: 2})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -7,18 +7,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/blocto/solana-go-sdk/client"
 	"github.com/blocto/solana-go-sdk/common"
 	"github.com/blocto/solana-go-sdk/program/associated_token_account"
 	"github.com/blocto/solana-go-sdk/program/system"
 	"github.com/blocto/solana-go-sdk/program/token"
-	"github.com/blocto/solana-go-sdk/rpc"
 	"github.com/blocto/solana-go-sdk/types"
 	"github.com/paxzhu/go-solana/pkg/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const SOL_MINT_ADDR = "So11111111111111111111111111111111111111112"
@@ -29,6 +32,18 @@ type WalletManager struct {
 	Client  *client.Client
 	Network string // "mainnet", "testnet", "devnet", "localhost"
 	Account types.Account
+	Signer  Signer        // 为空时使用 Account 在内存中签名
+	Logger  *slog.Logger  // 为空时使用 slog.Default()，可用 NewLogger 创建自动脱敏的日志记录器
+	Metrics *Metrics      // 为空时不记录指标，通过 WithMetrics 启用
+	Policy  *PolicyEngine // 为空时不做策略检查，通过 WithPolicy 启用
+
+	// AddressBook 为空时不解析联系人名称，也不提示首次转账，通过 WithAddressBook 启用
+	AddressBook *AddressBook
+
+	// TracerProvider 为空时使用全局的 otel.GetTracerProvider()
+	TracerProvider trace.TracerProvider
+
+	endpoint string
 	// TokenCache map[string]common.PublicKey // 缓存代币地址
 }
 
@@ -72,131 +87,172 @@ type SwapInfo struct {
 	FeeMint    string `json:"feeMint"`
 }
 
+// ManagerOption NewWalletManager 的可选配置
+type ManagerOption func(*WalletManager)
+
+// WithLogger 设置日志记录器
+func WithLogger(logger *slog.Logger) ManagerOption {
+	return func(wm *WalletManager) {
+		wm.Logger = logger
+	}
+}
+
+// WithMetrics 启用 Prometheus 指标，RPC 调用、交易发送与确认、手续费和余额都会被记录
+func WithMetrics(m *Metrics) ManagerOption {
+	return func(wm *WalletManager) {
+		wm.Metrics = m
+		wm.Client = m.Client(wm.endpoint)
+	}
+}
+
+// WithTracerProvider 设置 OpenTelemetry TracerProvider，每次操作的报价、构建、签名、发送和确认都会生成 span
+func WithTracerProvider(tp trace.TracerProvider) ManagerOption {
+	return func(wm *WalletManager) {
+		wm.TracerProvider = tp
+	}
+}
+
 // NewWalletManager 创建新的钱包管理器
-func NewWalletManager(network string) (*WalletManager, error) {
+func NewWalletManager(network string, opts ...ManagerOption) (*WalletManager, error) {
 	endpoint, ok := config.NetworkConfig[network]
 	if !ok {
 		return nil, fmt.Errorf("unsupported network: %s", network)
 	}
 
-	return &WalletManager{
-		Client:  client.NewClient(endpoint),
-		Network: network,
+	wm := &WalletManager{
+		Client:   newRPCClient(endpoint, nil),
+		Network:  network,
+		endpoint: endpoint,
 		// tokenCache: make(map[string]common.PublicKey),
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(wm)
+	}
+	return wm, nil
+}
+
+// PublicKey 返回当前钱包账户的公钥
+func (wm *WalletManager) PublicKey() common.PublicKey {
+	return wm.signer().PublicKey()
+}
+
+// signer 返回当前钱包的签名者，未设置 Signer 时使用内存中的 Account
+func (wm *WalletManager) signer() Signer {
+	if wm.Signer != nil {
+		return wm.Signer
+	}
+	return NewAccountSigner(wm.Account)
 }
 
 // CreateAccount 创建新账户
 func (wm *WalletManager) CreateAccount() (string, error) {
 	account := types.NewAccount()
 	wm.Account = account
+	wm.Signer = NewAccountSigner(account)
 
-	// Create a JSON file to save the private key
-	// Truncate the public key for the filename
-	truncatedPublicKey := account.PublicKey.ToBase58()[:10] // Use first 10 characters
-	filename := fmt.Sprintf("assets/wallet_%s.json", truncatedPublicKey)
-	file, err := os.Create(filename)
+	// 以 0600 权限保存私钥，不会覆盖已有文件
+	filename, err := SaveWallet(DefaultKeyDir, account)
 	if err != nil {
-		return "", fmt.Errorf("unable to create file: %v", err)
-	}
-	defer file.Close()
-
-	// Write private key to JSON file
-	if err := json.NewEncoder(file).Encode(account.PrivateKey); err != nil {
-		return "", fmt.Errorf("unable to write to file: %v", err)
+		return "", err
 	}
 
-	fmt.Printf("New account created with Public Key: %s, Private Key saved to %s\n", account.PublicKey.ToBase58(), filename)
+	wm.logger().Info("account created", "operation", "create_account", "pubkey", account.PublicKey.ToBase58(), "path", filename)
 
 	return account.PublicKey.ToBase58(), nil
 }
 
 // Mint需要和集群匹配，否则会出现“incorrect program id”的错误
-func (wm *WalletManager) CreateTokenAccount(ctx context.Context, mintAddr string) (string, error) {
+func (wm *WalletManager) CreateTokenAccount(ctx context.Context, mintAddr string, opts ...TxOption) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "CreateTokenAccount", attrMint.String(mintAddr))
+	defer func() { endSpan(span, err) }()
+
 	mintPubkey := common.PublicKeyFromString(mintAddr)
-	ata, _, err := common.FindAssociatedTokenAddress(wm.Account.PublicKey, mintPubkey)
+	ata, _, err := common.FindAssociatedTokenAddress(wm.PublicKey(), mintPubkey)
 	if err != nil {
-		return "", fmt.Errorf("find ata error, err: %v", err)
+		return "", fmt.Errorf("find ata error, err: %w", err)
 	}
-	fmt.Println("Associated Token Address, ata:", ata.ToBase58())
 
-	recentBlockhashResponse, err := wm.Client.GetLatestBlockhash(ctx)
+	rentExemptionBalance, err := wm.Client.GetMinimumBalanceForRentExemption(ctx, token.TokenAccountSize)
 	if err != nil {
-		return "", fmt.Errorf("get recent block hash error, err: %w", err)
+		return "", fmt.Errorf("failed to get min balance for rent exemption: %w", wrapRPCError(err, nil))
 	}
-
 	createTokenAccountInstruction := associated_token_account.Create(associated_token_account.CreateParam{
-		Funder:                 wm.Account.PublicKey,
-		Owner:                  wm.Account.PublicKey,
+		Funder:                 wm.PublicKey(),
+		Owner:                  wm.PublicKey(),
 		Mint:                   mintPubkey,
 		AssociatedTokenAccount: ata,
 	})
+	if err := wm.ensurePayerRentExempt(ctx, rentExemptionBalance, []types.Instruction{createTokenAccountInstruction}, opts); err != nil {
+		return "", err
+	}
 
-	message := types.NewMessage(types.NewMessageParam{
-		FeePayer:        wm.Account.PublicKey,
-		RecentBlockhash: recentBlockhashResponse.Blockhash,
-		Instructions: []types.Instruction{
-			createTokenAccountInstruction,
-		},
-	})
-	// fmt.Println("message:", message)
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{wm.Account},
-		Message: message,
-	})
+	tx, err := buildTransaction(ctx, wm.Client, []types.Instruction{
+		createTokenAccountInstruction,
+	}, []Signer{wm.signer()}, wm.policyOpts("create_token_account", opts)...)
 	if err != nil {
-		return "", fmt.Errorf("generate tx error, err: %v", err)
+		return "", fmt.Errorf("generate tx error, err: %w", err)
 	}
-	// fmt.Println("tx:", tx)
-	txhash, err := wm.Client.SendTransaction(ctx, tx)
-	if err != nil {
-		return "", fmt.Errorf("send raw tx error, err: %v", err)
+	if _, err := wm.sendTransaction(ctx, "create_token_account", tx, "mint", mintAddr, "ata", ata.ToBase58()); err != nil {
+		return "", fmt.Errorf("send raw tx error, err: %w", err)
 	}
-
-	fmt.Println("txhash:", txhash)
 	return ata.ToBase58(), nil
 }
 
 // RequestAirdrop 请求空投
 func (wm *WalletManager) RequestAirdrop(publicKey string, amount uint64) bool {
-	_, err := wm.Client.RequestAirdrop(
+	start := time.Now()
+	txhash, err := wm.Client.RequestAirdrop(
 		context.Background(),
 		publicKey,
 		amount,
 	)
+	attrs := []any{"operation", "request_airdrop", "network", wm.Network, "pubkey", publicKey, "amount", amount, "latency", time.Since(start)}
 	if err != nil {
-		fmt.Printf("Failed to request airdrop: %v\n", err)
+		wm.logger().Error("airdrop failed", append(attrs, "error", err)...)
 		return false
 	}
-	fmt.Println("Airdrop successful!")
+	wm.logger().Info("airdrop requested", append(attrs, "signature", txhash)...)
 	return true
 }
 
 // LoadAccount 从私钥文件加载账户
 func (wm *WalletManager) LoadAccount(keyPath string) (string, error) {
+	account, err := LoadKeypair(keyPath)
+	if err != nil {
+		return "", err
+	}
+
+	wm.Account = account
+	wm.Signer = NewAccountSigner(account)
+	return account.PublicKey.ToBase58(), nil
+}
+
+// LoadKeypair 从私钥文件读取账户，不需要连接网络（离线签名机使用）
+func LoadKeypair(keyPath string) (types.Account, error) {
 	data, err := os.ReadFile(keyPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read key file: %w", err)
+		return types.Account{}, fmt.Errorf("failed to read key file: %w", err)
 	}
 
 	var privateKey []byte
 	err = json.Unmarshal(data, &privateKey)
 	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal private key: %w", err)
+		return types.Account{}, fmt.Errorf("failed to unmarshal private key: %w", err)
 	}
 
 	account, err := types.AccountFromBytes(privateKey)
 	if err != nil {
-		return "", fmt.Errorf("failed to create account from bytes: %w", err)
+		return types.Account{}, fmt.Errorf("failed to create account from bytes: %w", err)
 	}
-
-	wm.Account = account
-	return account.PublicKey.ToBase58(), nil
+	return account, nil
 }
 
-// CheckAmount 检查指定代币的余额
-func (wm *WalletManager) CheckAmount(ctx context.Context, mintAddr string) (uint64, error) {
-	if wm.Account.PublicKey.ToBase58() == "" {
+// CheckAmount 检查指定代币的余额。mintAddr 为 SOL_MINT_ADDR 时返回原生 SOL 余额，wSOL 余额见 SOLBalances
+func (wm *WalletManager) CheckAmount(ctx context.Context, mintAddr string) (_ uint64, err error) {
+	ctx, span := wm.startOperation(ctx, "CheckAmount", attrMint.String(mintAddr))
+	defer func() { endSpan(span, err) }()
+
+	if wm.PublicKey() == (common.PublicKey{}) {
 		return 0, errors.New("no account loaded")
 	}
 
@@ -204,17 +260,18 @@ func (wm *WalletManager) CheckAmount(ctx context.Context, mintAddr string) (uint
 	if mintAddr == SOL_MINT_ADDR {
 		balance, err := wm.Client.GetBalance(
 			ctx,
-			wm.Account.PublicKey.ToBase58(),
+			wm.PublicKey().ToBase58(),
 		)
 		if err != nil {
-			return 0, fmt.Errorf("failed to get SOL balance: %w", err)
+			return 0, fmt.Errorf("failed to get SOL balance: %w", wrapRPCError(err, nil))
 		}
+		wm.Metrics.observeBalance(wm.Network, wm.PublicKey().ToBase58(), mintAddr, balance)
 		return balance, nil
 	}
 
 	mintPubkey := common.PublicKeyFromString(mintAddr)
 	// 获取关联代币的账户地址
-	ata, _, err := common.FindAssociatedTokenAddress(wm.Account.PublicKey, mintPubkey)
+	ata, _, err := common.FindAssociatedTokenAddress(wm.PublicKey(), mintPubkey)
 	if err != nil {
 		return 0, fmt.Errorf("failed to find associated token address: %w", err)
 	}
@@ -224,31 +281,24 @@ func (wm *WalletManager) CheckAmount(ctx context.Context, mintAddr string) (uint
 		ata.ToBase58(),
 	)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get token balance: %w", err)
+		return 0, fmt.Errorf("failed to get token balance: %w", wrapRPCError(err, nil))
 	}
 	balance := tokenAmount.Amount
+	wm.Metrics.observeBalance(wm.Network, wm.PublicKey().ToBase58(), mintAddr, balance)
 	return balance, nil
 }
 
 // Transfer 转账功能
-func (wm *WalletManager) TransferSOL(ctx context.Context, toAddress string, amount uint64) (string, error) {
-	senderPubKey := wm.Account.PublicKey
-	receiverPubKey := common.PublicKeyFromString(toAddress)
-
-	// 检查余额是否足够
-	balance, err := wm.CheckAmount(ctx, SOL_MINT_ADDR)
-	if err != nil {
-		return "", fmt.Errorf("failed to check balance: %v", err)
-	}
-	if balance < amount {
-		return "", fmt.Errorf("insufficient balance: have %d lamports, need %d lamports", balance, amount)
-	}
+func (wm *WalletManager) TransferSOL(ctx context.Context, toAddress string, amount uint64, opts ...TxOption) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "TransferSOL", attrMint.String(SOL_MINT_ADDR), attrAmount.Int64(int64(amount)))
+	defer func() { endSpan(span, err) }()
 
-	// 获取最近的区块哈希（用于构建交易）
-	recentBlockhashResponse, err := wm.Client.GetLatestBlockhash(ctx)
+	senderPubKey := wm.PublicKey()
+	receiverPubKey, err := wm.resolveRecipient(ctx, "transfer_sol", toAddress)
 	if err != nil {
-		return "", fmt.Errorf("failed to get latest blockhash: %v", err)
+		return "", err
 	}
+	toAddress = receiverPubKey.ToBase58()
 
 	transferInstruction := system.Transfer(system.TransferParam{
 		From:   senderPubKey,   // 发送账户的公钥
@@ -256,79 +306,79 @@ func (wm *WalletManager) TransferSOL(ctx context.Context, toAddress string, amou
 		Amount: amount,
 	})
 
-	message := types.NewMessage(types.NewMessageParam{
-		FeePayer:        senderPubKey,
-		RecentBlockhash: recentBlockhashResponse.Blockhash,
-		Instructions: []types.Instruction{
-			transferInstruction,
-		},
-	})
+	// 检查余额是否足够支付转账和手续费，且不会留下低于免租金额的零头
+	if err := wm.ensurePayerRentExempt(ctx, amount, []types.Instruction{transferInstruction}, opts); err != nil {
+		return "", err
+	}
 
-	// create a transfer tx
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{wm.Account},
-		Message: message,
-	})
+	// create a transfer tx（默认使用最近区块哈希，可通过 WithDurableNonce 改用 nonce）
+	tx, err := buildTransaction(ctx, wm.Client, []types.Instruction{
+		transferInstruction,
+	}, []Signer{wm.signer()}, wm.policyOpts("transfer_sol", opts)...)
 	if err != nil {
-		log.Fatalf("failed to new a transaction, err: %v", err)
+		return "", fmt.Errorf("failed to new a transaction: %w", err)
 	}
 
 	// 发送交易
-	txhash, err := wm.Client.SendTransaction(ctx, tx)
+	txhash, err := wm.sendTransaction(ctx, "transfer_sol", tx, "to", toAddress, "amount", amount)
 	if err != nil {
-		return "", fmt.Errorf("failed to send transaction : %v", err)
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	if wm.AddressBook != nil {
+		if err := wm.AddressBook.MarkSent(wm.Network, toAddress); err != nil {
+			wm.logger().WarnContext(ctx, "failed to record recipient", "network", wm.Network, "to", toAddress, "error", err)
+		}
 	}
-	log.Println("txhash:", txhash)
 	return txhash, nil
 }
 
 // TransferTokensChecked transfers a specified amount of tokens from one account to another on the Solana blockchain.
 // It returns the transaction hash if successful, or an error if something goes wrong.
 func (wm *WalletManager) TransferTokensChecked(
+	ctx context.Context,
 	mintAddr string, // mint地址
-	mintAuthority types.Account, // mint的权限账户
+	mintAuthority Signer, // mint的权限账户
 	fromTokenAddr string, // 转出的代币地址
 	toTokenAddr string, // 转入的代币地址
 	amount uint64, // 转账数量
 	decimals uint8,
-) (string, error) {
-	feePayer := wm.Account
-	res, err := wm.Client.GetLatestBlockhash(context.Background())
+	opts ...TxOption,
+) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "TransferTokensChecked", attrMint.String(mintAddr), attrAmount.Int64(int64(amount)))
+	defer func() { endSpan(span, err) }()
+
+	feePayer := wm.signer()
+	mintPubkey, err := ParseAddress(mintAddr)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("invalid mint: %w", err)
 	}
-	mintPubkey := common.PublicKeyFromString(mintAddr)
-	fromTokenPubkey := common.PublicKeyFromString(fromTokenAddr)
-	toTokenPubkey := common.PublicKeyFromString(toTokenAddr)
-
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:        feePayer.PublicKey,
-			RecentBlockhash: res.Blockhash,
-			Instructions: []types.Instruction{
-				token.TransferChecked(token.TransferCheckedParam{
-					From:     fromTokenPubkey,
-					To:       toTokenPubkey,
-					Mint:     mintPubkey,
-					Auth:     mintAuthority.PublicKey,
-					Signers:  []common.PublicKey{},
-					Amount:   amount,
-					Decimals: decimals,
-				}),
-			},
-		}),
-		Signers: []types.Account{feePayer, mintAuthority},
-	})
+	fromTokenPubkey, err := ParseAddress(fromTokenAddr)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("invalid source token account: %w", err)
 	}
-
-	txhash, err := wm.Client.SendTransaction(context.Background(), tx)
+	// 收款方是代币账户，只校验地址格式
+	toTokenPubkey, err := ParseAddress(toTokenAddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid destination token account: %w", err)
+	}
+
+	tx, err := buildTransaction(ctx, wm.Client, []types.Instruction{
+		token.TransferChecked(token.TransferCheckedParam{
+			From:     fromTokenPubkey,
+			To:       toTokenPubkey,
+			Mint:     mintPubkey,
+			Auth:     mintAuthority.PublicKey(),
+			Signers:  []common.PublicKey{},
+			Amount:   amount,
+			Decimals: decimals,
+		}),
+	}, appendSigner([]Signer{feePayer}, mintAuthority), wm.policyOpts("transfer_tokens", opts)...)
 	if err != nil {
 		return "", err
 	}
-	log.Println("txhash:", txhash)
-	return txhash, nil
+
+	return wm.sendTransaction(ctx, "transfer_tokens", tx,
+		"mint", mintAddr, "from", fromTokenAddr, "to", toTokenAddr, "amount", amount)
 }
 
 // // Buy 市价买入代币
@@ -359,7 +409,10 @@ func (wm *WalletManager) TransferTokensChecked(
 // }
 
 // Sell 市价卖出代币
-func (wm *WalletManager) Sell(ctx context.Context, mintAddr string, amount float64) error {
+func (wm *WalletManager) Sell(ctx context.Context, mintAddr string, amount float64) (err error) {
+	ctx, span := wm.startOperation(ctx, "Sell", attrMint.String(mintAddr), attrAmount.Float64(amount))
+	defer func() { endSpan(span, err) }()
+
 	// 检查代币余额
 	balance, err := wm.CheckAmount(ctx, mintAddr)
 	if err != nil {
@@ -367,24 +420,24 @@ func (wm *WalletManager) Sell(ctx context.Context, mintAddr string, amount float
 	}
 
 	if float64(balance) < amount {
-		return fmt.Errorf("insufficient balance: have %v, need %v", balance, amount)
+		return fmt.Errorf("%w: have %d, need %v", ErrInsufficientFunds, balance, amount)
+	}
+
+	if err := wm.Policy.CheckSlippage("sell", defaultSlippageBps); err != nil {
+		return err
 	}
 
 	// 构建报价请求
-	quoteURL := fmt.Sprintf("%s?inputMint=%s&outputMint=%s&amount=%d&slippageBps=%d",
-		JupiterQuoteAPI,
-		mintAddr,
-		SOL_MINT_ADDR, // SOL
-		uint64(amount),
-		defaultSlippageBps,
-	)
+	quoteURL := QuoteURL(mintAddr, SOL_MINT_ADDR, uint64(amount), defaultSlippageBps)
 
 	// 获取报价
-	quote, err := wm.GetQuote(quoteURL)
+	quote, err := wm.GetQuoteContext(ctx, quoteURL)
 	if err != nil {
 		return fmt.Errorf("failed to get quote: %w", err)
 	}
-	fmt.Println("Quote:", quote)
+	wm.logger().InfoContext(ctx, "quote received", "operation", "sell", "network", wm.Network,
+		"inputMint", quote.InputMint, "outputMint", quote.OutputMint,
+		"inAmount", quote.InAmount, "outAmount", quote.OutAmount, "priceImpactPct", quote.PriceImpactPct)
 	// 执行交换
 	// err = wm.executeSwap(ctx, quote)
 	// if err != nil {
@@ -395,68 +448,146 @@ func (wm *WalletManager) Sell(ctx context.Context, mintAddr string, amount float
 	return nil
 }
 
+// SwapOption Swap 的可选配置
+type SwapOption func(*swapOptions)
+
+type swapOptions struct {
+	wrapAndUnwrapSOL bool
+}
+
+// WithWrapAndUnwrapSOL 设置 Jupiter 是否在兑换交易中自动包装输入的 SOL、解包输出的 wSOL（默认开启）。
+// 关闭后兑换直接使用 wSOL 账户：输入为 SOL 时先把不足的部分从原生 SOL 包装进去，输出的 wSOL 留在账户中。
+func WithWrapAndUnwrapSOL(enabled bool) SwapOption {
+	return func(o *swapOptions) {
+		o.wrapAndUnwrapSOL = enabled
+	}
+}
+
+// Swap 通过 Jupiter 将 amount 个 inputMint 兑换为 outputMint，返回使用的报价和交易签名
+func (wm *WalletManager) Swap(ctx context.Context, inputMint string, outputMint string, amount uint64, slippageBps int, opts ...SwapOption) (_ *QuoteResponse, _ string, err error) {
+	ctx, span := wm.startOperation(ctx, "Swap", attrMint.String(inputMint), attrAmount.Int64(int64(amount)))
+	defer func() { endSpan(span, err) }()
+
+	o := swapOptions{wrapAndUnwrapSOL: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := wm.Policy.CheckSlippage("swap", slippageBps); err != nil {
+		return nil, "", err
+	}
+	quote, err := wm.GetQuoteContext(ctx, QuoteURL(inputMint, outputMint, amount, slippageBps))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get quote: %w", err)
+	}
+	if !o.wrapAndUnwrapSOL && inputMint == SOL_MINT_ADDR {
+		if err := wm.ensureWrappedSOL(ctx, amount); err != nil {
+			return quote, "", err
+		}
+	}
+	txhash, err := wm.executeSwap(ctx, quote, o)
+	if err != nil {
+		return quote, "", fmt.Errorf("swap failed: %w", err)
+	}
+	return quote, txhash, nil
+}
+
 // 内部辅助方法
 
-// getQuote 从 Jupiter 获取报价
+// QuoteURL 构建 Jupiter 报价请求地址
+func QuoteURL(inputMint string, outputMint string, amount uint64, slippageBps int) string {
+	return quoteURL(JupiterQuoteAPI, inputMint, outputMint, amount, slippageBps)
+}
+
+// quoteURL 构建 api 地址上的 Jupiter 报价请求
+func quoteURL(api string, inputMint string, outputMint string, amount uint64, slippageBps int) string {
+	return fmt.Sprintf("%s?inputMint=%s&outputMint=%s&amount=%d&slippageBps=%d",
+		api, inputMint, outputMint, amount, slippageBps)
+}
+
+// GetQuote 从 Jupiter 获取报价
 func (wm *WalletManager) GetQuote(quoteURL string) (*QuoteResponse, error) {
-	fmt.Println("Fetching quote from URL:", quoteURL) // 输出请求的URL
+	return wm.GetQuoteContext(context.Background(), quoteURL)
+}
+
+// GetQuoteContext 同 GetQuote，ctx 用于取消请求和传递 trace
+func (wm *WalletManager) GetQuoteContext(ctx context.Context, quoteURL string) (_ *QuoteResponse, err error) {
+	ctx, span := wm.startOperation(ctx, "GetQuote", attrEndpoint.String(redactURL(quoteURL)))
+	defer func() { endSpan(span, err) }()
 
-	resp, err := http.Get(quoteURL)
+	logger := wm.logger().With("operation", "get_quote", "url", redactURL(quoteURL))
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, quoteURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote url: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		fmt.Println("Error fetching quote:", err) // 输出错误信息
-		return nil, err
+		logger.Error("quote request failed", "error", err)
+		return nil, fmt.Errorf("quote request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Println("Error reading response body:", err)
-		return nil, err
+		logger.Error("failed to read quote response", "error", err)
+		return nil, fmt.Errorf("failed to read quote response: %w", err)
 	}
-	fmt.Println("Received response body:", string(body)) // 输出响应体
+	logger.Debug("quote response", "status", resp.StatusCode, "body", truncate(string(body), maxLoggedBody))
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		fmt.Println("Received non-OK status code:", resp.StatusCode, "with body:", string(body)) // 输出状态码和响应体
+		logger.Warn("quote rejected", "status", resp.StatusCode, "latency", time.Since(start))
 		return nil, fmt.Errorf("quote failed: %s", string(body))
 	}
 
 	var quote QuoteResponse
 	if err := json.Unmarshal(body, &quote); err != nil {
-		fmt.Println("Error decoding JSON:", err)
-		return nil, err
+		logger.Error("failed to decode quote", "error", err)
+		return nil, fmt.Errorf("failed to decode quote: %w", err)
 	}
 
-	fmt.Println("Successfully fetched quote:", quote)
+	span.SetAttributes(attrMint.String(quote.InputMint), attribute.String("jupiter.out_amount", quote.OutAmount))
+	logger.Info("quote fetched", "inAmount", quote.InAmount, "outAmount", quote.OutAmount, "latency", time.Since(start))
 	return &quote, nil
 }
 
 // executeSwap 执行代币交换
-func (wm *WalletManager) executeSwap(ctx context.Context, quote *QuoteResponse) error {
+func (wm *WalletManager) executeSwap(ctx context.Context, quote *QuoteResponse, o swapOptions) (_ string, err error) {
+	ctx, span := startSpan(ctx, "jupiter.swap", attrEndpoint.String(JupiterSwapAPI), attrMint.String(quote.InputMint))
+	defer func() { endSpan(span, err) }()
+
 	// 构建交换请求
 	swapReq := struct {
-		UserPublicKey string        `json:"userPublicKey"`
-		QuoteResp     QuoteResponse `json:"quoteResponse"`
+		UserPublicKey    string        `json:"userPublicKey"`
+		QuoteResp        QuoteResponse `json:"quoteResponse"`
+		WrapAndUnwrapSOL bool          `json:"wrapAndUnwrapSol"`
 	}{
-		UserPublicKey: wm.Account.PublicKey.ToBase58(),
-		QuoteResp:     *quote,
+		UserPublicKey:    wm.PublicKey().ToBase58(),
+		QuoteResp:        *quote,
+		WrapAndUnwrapSOL: o.wrapAndUnwrapSOL,
 	}
 
 	// 发送交换请求
 	reqBody, err := json.Marshal(swapReq)
 	if err != nil {
-		return err
+		return "", err
 	}
-	fmt.Println("Sending swap request:", string(reqBody))
-	resp, err := http.Post(JupiterSwapAPI, "application/json", bytes.NewBuffer(reqBody))
+	wm.logger().DebugContext(ctx, "sending swap request", "operation", "swap", "network", wm.Network,
+		"pubkey", swapReq.UserPublicKey, "body", truncate(string(reqBody), maxLoggedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, JupiterSwapAPI, bytes.NewBuffer(reqBody))
 	if err != nil {
-		return err
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("swap request failed: %s", string(body))
+		return "", fmt.Errorf("swap request failed: %s", string(body))
 	}
 
 	// 处理响应，获取交易指令
@@ -464,76 +595,72 @@ func (wm *WalletManager) executeSwap(ctx context.Context, quote *QuoteResponse)
 		SwapTransaction []byte `json:"swapTransaction"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&swapResp); err != nil {
-		return err
+		return "", fmt.Errorf("failed to decode swap response: %w", err)
 	}
 
-	// 使用 Transaction 对象进行初始化，假设您有 swapTransaction 数据
-	// tx, err := types.NewTransaction(swapResp.SwapTransaction)
-
-	// // 签名交易
-	// tx.Sign([]types.Account{wm.Account})
-
-	// // 发送交易
-	// sig, err := wm.Client.SendTransaction(ctx, tx)
-	// if err != nil {
-	// 	return err
-	// }
-
-	return nil
+	// Jupiter 返回由当前账户支付手续费的未签名交易
+	tx, err := types.TransactionDeserialize(swapResp.SwapTransaction)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode swap transaction: %w", err)
+	}
+	// Jupiter 的交易使用地址查找表，无法从指令解析转出金额，按报价中的输入金额检查策略
+	inAmount, err := strconv.ParseUint(quote.InAmount, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid quote inAmount %q: %w", quote.InAmount, err)
+	}
+	if err := wm.enforcePolicy(ctx, "swap", tx.Message, []Spend{{Operation: "swap", Mint: quote.InputMint, Amount: inAmount}}); err != nil {
+		return "", err
+	}
+	if err := signMessage(ctx, &tx, wm.signer()); err != nil {
+		return "", err
+	}
+	return wm.sendTransaction(ctx, "swap", tx,
+		"inputMint", quote.InputMint, "outputMint", quote.OutputMint, "inAmount", quote.InAmount, "outAmount", quote.OutAmount)
 }
 
-func CreateMint(feePayer types.Account, mintAuthority types.Account) {
-	c := client.NewClient(rpc.DevnetRPCEndpoint)
+// CreateMint 创建精度为 8 的新代币，当前账户支付费用，返回 mint 地址和交易哈希
+func (wm *WalletManager) CreateMint(ctx context.Context, mintAuthority Signer, opts ...TxOption) (_ string, _ string, err error) {
+	ctx, span := wm.startOperation(ctx, "CreateMint")
+	defer func() { endSpan(span, err) }()
+
+	feePayer := wm.signer()
 
 	// create an mint account
 	mint := types.NewAccount()
-	fmt.Println("mint:", mint.PublicKey.ToBase58())
 
 	// get rent
-	rentExemptionBalance, err := c.GetMinimumBalanceForRentExemption(
-		context.Background(),
-		token.MintAccountSize,
-	)
+	rentExemptionBalance, err := wm.Client.GetMinimumBalanceForRentExemption(ctx, token.MintAccountSize)
 	if err != nil {
-		log.Fatalf("get min balacne for rent exemption, err: %v", err)
-	}
-	fmt.Println("rentExemptionBalance:", rentExemptionBalance)
-
-	res, err := c.GetLatestBlockhash(context.Background())
-	if err != nil {
-		log.Fatalf("get recent block hash error, err: %v\n", err)
-	}
-
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:        feePayer.PublicKey,
-			RecentBlockhash: res.Blockhash,
-			Instructions: []types.Instruction{
-				system.CreateAccount(system.CreateAccountParam{
-					From:     feePayer.PublicKey,
-					New:      mint.PublicKey,
-					Owner:    common.TokenProgramID,
-					Lamports: rentExemptionBalance,
-					Space:    token.MintAccountSize,
-				}),
-				token.InitializeMint(token.InitializeMintParam{
-					Decimals:   8,
-					Mint:       mint.PublicKey,
-					MintAuth:   mintAuthority.PublicKey,
-					FreezeAuth: nil,
-				}),
-			},
+		return "", "", fmt.Errorf("get min balance for rent exemption: %w", wrapRPCError(err, nil))
+	}
+	instructions := []types.Instruction{
+		system.CreateAccount(system.CreateAccountParam{
+			From:     feePayer.PublicKey(),
+			New:      mint.PublicKey,
+			Owner:    common.TokenProgramID,
+			Lamports: rentExemptionBalance,
+			Space:    token.MintAccountSize,
 		}),
-		Signers: []types.Account{feePayer, mint},
-	})
-	if err != nil {
-		log.Fatalf("generate tx error, err: %v\n", err)
+		token.InitializeMint(token.InitializeMintParam{
+			Decimals:   8,
+			Mint:       mint.PublicKey,
+			MintAuth:   mintAuthority.PublicKey(),
+			FreezeAuth: nil,
+		}),
+	}
+	if err := wm.ensurePayerRentExempt(ctx, rentExemptionBalance, instructions, opts); err != nil {
+		return "", "", err
 	}
 
-	txhash, err := c.SendTransaction(context.Background(), tx)
+	tx, err := buildTransaction(ctx, wm.Client, instructions, []Signer{feePayer, NewAccountSigner(mint)}, wm.policyOpts("create_mint", opts)...)
 	if err != nil {
-		log.Fatalf("send tx error, err: %v\n", err)
+		return "", "", fmt.Errorf("generate tx error: %w", err)
 	}
 
-	log.Println("txhash:", txhash)
+	span.SetAttributes(attrMint.String(mint.PublicKey.ToBase58()))
+	txhash, err := wm.sendTransaction(ctx, "create_mint", tx, "mint", mint.PublicKey.ToBase58(), "rentLamports", rentExemptionBalance)
+	if err != nil {
+		return "", "", fmt.Errorf("send tx error: %w", err)
+	}
+	return mint.PublicKey.ToBase58(), txhash, nil
 }
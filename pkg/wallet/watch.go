@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 交易确认状态
+const (
+	SignaturePending   = "pending" // 节点尚未看到交易
+	SignatureProcessed = "processed"
+	SignatureConfirmed = "confirmed"
+	SignatureFinalized = "finalized"
+	SignatureFailed    = "failed"
+)
+
+// SignatureUpdate 交易确认状态的一次变化
+type SignatureUpdate struct {
+	Signature string
+	Status    string
+	Slot      uint64
+	Err       error // Status 为 SignatureFailed 时为解析后的错误（例如 *ProgramError）
+}
+
+// WatchSignature 轮询交易状态，每次状态变化时调用 onUpdate，交易 finalized 或执行失败后返回 nil。
+// onUpdate 返回错误时停止并返回该错误。
+func (wm *WalletManager) WatchSignature(ctx context.Context, signature string, onUpdate func(SignatureUpdate) error) error {
+	ticker := time.NewTicker(confirmPollInterval)
+	defer ticker.Stop()
+
+	last := ""
+	for {
+		status, err := wm.Client.GetSignatureStatus(ctx, signature)
+		if err != nil {
+			return fmt.Errorf("failed to get signature status: %w", wrapRPCError(err, nil))
+		}
+
+		update := SignatureUpdate{Signature: signature, Status: SignaturePending}
+		if status != nil {
+			update.Slot = status.Slot
+			switch {
+			case status.Err != nil:
+				update.Status = SignatureFailed
+				update.Err = parseTransactionError(status.Err, nil)
+			case status.ConfirmationStatus != nil:
+				update.Status = string(*status.ConfirmationStatus)
+			}
+		}
+		if update.Status != last {
+			last = update.Status
+			if err := onUpdate(update); err != nil {
+				return err
+			}
+		}
+		if last == SignatureFinalized || last == SignatureFailed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WatchBalance 每隔 interval 查询一次余额，先回调当前余额，之后只在余额变化时回调，直到 ctx 取消。
+// 代币账户不存在时视为余额为 0。
+func (wm *WalletManager) WatchBalance(ctx context.Context, mintAddr string, interval time.Duration, onChange func(uint64) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last uint64
+	first := true
+	for {
+		balance, err := wm.CheckAmount(ctx, mintAddr)
+		if errors.Is(err, ErrAccountNotFound) {
+			balance, err = 0, nil
+		}
+		if err != nil {
+			return err
+		}
+		if first || balance != last {
+			first = false
+			last = balance
+			if err := onChange(balance); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
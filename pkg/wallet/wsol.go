@@ -0,0 +1,161 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// wrappedSOLMint wSOL 的 mint，即 SOL_MINT_ADDR
+var wrappedSOLMint = common.PublicKeyFromString(SOL_MINT_ADDR)
+
+// SOLBalance 当前钱包的原生 SOL 和 wSOL 关联代币账户余额，单位 lamports
+type SOLBalance struct {
+	Native  uint64
+	Wrapped uint64 // wSOL 账户不存在时为 0
+}
+
+// Total 原生和 wSOL 余额之和
+func (b SOLBalance) Total() uint64 {
+	return b.Native + b.Wrapped
+}
+
+// wrappedSOLAccount 当前钱包的 wSOL 关联代币账户地址
+func (wm *WalletManager) wrappedSOLAccount() (common.PublicKey, error) {
+	ata, _, err := common.FindAssociatedTokenAddress(wm.PublicKey(), wrappedSOLMint)
+	if err != nil {
+		return common.PublicKey{}, fmt.Errorf("failed to find associated token address: %w", err)
+	}
+	return ata, nil
+}
+
+// SOLBalances 分别返回原生 SOL 和 wSOL 余额。CheckAmount(SOL_MINT_ADDR) 只返回原生余额。
+func (wm *WalletManager) SOLBalances(ctx context.Context) (_ *SOLBalance, err error) {
+	ctx, span := wm.startOperation(ctx, "SOLBalances", attrMint.String(SOL_MINT_ADDR))
+	defer func() { endSpan(span, err) }()
+
+	native, err := wm.CheckAmount(ctx, SOL_MINT_ADDR)
+	if err != nil {
+		return nil, err
+	}
+	ata, err := wm.wrappedSOLAccount()
+	if err != nil {
+		return nil, err
+	}
+	balance := &SOLBalance{Native: native}
+	info, err := getAccountInfo(ctx, wm.Client, ata.ToBase58())
+	if errors.Is(err, ErrAccountNotFound) {
+		return balance, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wSOL account: %w", err)
+	}
+	account, err := token.TokenAccountFromData(info.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse wSOL account %s: %w", ata.ToBase58(), err)
+	}
+	balance.Wrapped = account.Amount
+	return balance, nil
+}
+
+// WrapSOL 把 lamports 个原生 SOL 转入当前钱包的 wSOL 关联代币账户（不存在时创建）并执行 SyncNative，返回交易哈希
+func (wm *WalletManager) WrapSOL(ctx context.Context, lamports uint64, opts ...TxOption) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "WrapSOL", attrMint.String(SOL_MINT_ADDR), attrAmount.Int64(int64(lamports)))
+	defer func() { endSpan(span, err) }()
+
+	if lamports == 0 {
+		return "", errors.New("amount must be greater than 0")
+	}
+	owner := wm.PublicKey()
+	ata, err := wm.wrappedSOLAccount()
+	if err != nil {
+		return "", err
+	}
+
+	// wSOL 账户不存在时还要支付它的免租金额
+	spend := lamports
+	_, err = getAccountInfo(ctx, wm.Client, ata.ToBase58())
+	switch {
+	case errors.Is(err, ErrAccountNotFound):
+		rent, err := wm.Client.GetMinimumBalanceForRentExemption(ctx, token.TokenAccountSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to get min balance for rent exemption: %w", wrapRPCError(err, nil))
+		}
+		spend += rent
+	case err != nil:
+		return "", fmt.Errorf("failed to get wSOL account: %w", err)
+	}
+	instructions := []types.Instruction{
+		associated_token_account.CreateIdempotent(associated_token_account.CreateIdempotentParam{
+			Funder:                 owner,
+			Owner:                  owner,
+			Mint:                   wrappedSOLMint,
+			AssociatedTokenAccount: ata,
+		}),
+		system.Transfer(system.TransferParam{From: owner, To: ata, Amount: lamports}),
+		token.SyncNative(token.SyncNativeParam{Account: ata}),
+	}
+	if err := wm.ensurePayerRentExempt(ctx, spend, instructions, opts); err != nil {
+		return "", err
+	}
+
+	tx, err := buildTransaction(ctx, wm.Client, instructions, []Signer{wm.signer()}, wm.policyOpts("wrap_sol", opts)...)
+	if err != nil {
+		return "", err
+	}
+	txhash, err := wm.sendTransaction(ctx, "wrap_sol", tx, "ata", ata.ToBase58(), "lamports", lamports)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return txhash, nil
+}
+
+// UnwrapSOL 关闭当前钱包的 wSOL 关联代币账户，全部 wSOL 和租金以原生 SOL 退回钱包，返回交易哈希。
+// wSOL 账户不能部分解包，需要保留一部分时先解包再 WrapSOL。
+func (wm *WalletManager) UnwrapSOL(ctx context.Context, opts ...TxOption) (_ string, err error) {
+	ctx, span := wm.startOperation(ctx, "UnwrapSOL", attrMint.String(SOL_MINT_ADDR))
+	defer func() { endSpan(span, err) }()
+
+	owner := wm.PublicKey()
+	ata, err := wm.wrappedSOLAccount()
+	if err != nil {
+		return "", err
+	}
+	if _, err := getAccountInfo(ctx, wm.Client, ata.ToBase58()); err != nil {
+		return "", fmt.Errorf("failed to get wSOL account: %w", err)
+	}
+
+	tx, err := buildTransaction(ctx, wm.Client, []types.Instruction{
+		token.CloseAccount(token.CloseAccountParam{Account: ata, Auth: owner, To: owner}),
+	}, []Signer{wm.signer()}, wm.policyOpts("unwrap_sol", opts)...)
+	if err != nil {
+		return "", err
+	}
+	txhash, err := wm.sendTransaction(ctx, "unwrap_sol", tx, "ata", ata.ToBase58())
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return txhash, nil
+}
+
+// ensureWrappedSOL wSOL 余额不足 amount 时把差额从原生 SOL 包装进去，并等待交易确认
+func (wm *WalletManager) ensureWrappedSOL(ctx context.Context, amount uint64) error {
+	balance, err := wm.SOLBalances(ctx)
+	if err != nil {
+		return err
+	}
+	if balance.Wrapped >= amount {
+		return nil
+	}
+	txhash, err := wm.WrapSOL(ctx, amount-balance.Wrapped)
+	if err != nil {
+		return fmt.Errorf("failed to wrap SOL: %w", err)
+	}
+	return wm.WaitForConfirmation(ctx, txhash)
+}
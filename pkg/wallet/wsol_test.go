@@ -0,0 +1,71 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/program/associated_token_account"
+	"github.com/blocto/solana-go-sdk/program/system"
+	"github.com/blocto/solana-go-sdk/program/token"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapSOL(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	stub.on("getAccountInfo", func([]json.RawMessage) any { return withContext(nil) })
+	// 包装不算支出，收款人白名单不影响
+	policy, err := NewPolicyEngine(Policy{AllowRecipients: []string{types.NewAccount().PublicKey.ToBase58()}}, nil)
+	require.NoError(t, err)
+	wm.Policy = policy
+	ata, err := wm.wrappedSOLAccount()
+	require.NoError(t, err)
+
+	_, err = wm.WrapSOL(context.Background(), 1_000_000_000-lamportsPerSignature)
+	assert.ErrorIs(t, err, ErrInsufficientFunds, "the new wSOL account needs rent")
+
+	_, err = wm.WrapSOL(context.Background(), 100_000_000)
+	require.NoError(t, err)
+	tx := sentTransaction(t, stub, 0)
+	require.Len(t, tx.Message.Instructions, 3)
+	programs := make([]common.PublicKey, 0, 3)
+	for _, ins := range tx.Message.Instructions {
+		programs = append(programs, tx.Message.Accounts[ins.ProgramIDIndex])
+	}
+	assert.Equal(t, []common.PublicKey{common.SPLAssociatedTokenAccountProgramID, common.SystemProgramID, common.TokenProgramID}, programs)
+	assert.Equal(t, []byte{byte(associated_token_account.InstructionCreateIdempotent)}, tx.Message.Instructions[0].Data)
+	assert.Equal(t, byte(system.InstructionTransfer), tx.Message.Instructions[1].Data[0])
+	assert.Equal(t, ata, tx.Message.Accounts[tx.Message.Instructions[1].Accounts[1]])
+	assert.Equal(t, []byte{byte(token.InstructionSyncNative)}, tx.Message.Instructions[2].Data)
+}
+
+func TestSOLBalancesAndUnwrap(t *testing.T) {
+	wm, stub := newErrorTestWallet(t, rpcStubError{})
+	stub.on("sendTransaction", func([]json.RawMessage) any { return "5Sig" })
+	stub.on("getAccountInfo", func([]json.RawMessage) any { return withContext(nil) })
+
+	balance, err := wm.SOLBalances(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, SOLBalance{Native: 1_000_000_000}, *balance)
+	_, err = wm.UnwrapSOL(context.Background())
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	data := tokenAccountData(wrappedSOLMint, wm.PublicKey(), 42, 1, nil)
+	stub.on("getAccountInfo", func([]json.RawMessage) any {
+		return withContext(accountInfo(common.TokenProgramID.ToBase58(), testRentExempt(token.TokenAccountSize)+42, data))
+	})
+	balance, err = wm.SOLBalances(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), balance.Wrapped)
+	assert.Equal(t, uint64(1_000_000_042), balance.Total())
+
+	_, err = wm.UnwrapSOL(context.Background())
+	require.NoError(t, err)
+	tx := sentTransaction(t, stub, 0)
+	require.Len(t, tx.Message.Instructions, 1)
+	assert.Equal(t, []byte{byte(token.InstructionCloseAccount)}, tx.Message.Instructions[0].Data)
+}
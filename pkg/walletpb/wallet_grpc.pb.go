@@ -0,0 +1,586 @@
+// 钱包 gRPC 服务定义，服务端实现见 pkg/wallet/grpcserver.go。
+// 修改后重新生成：
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	       --go-grpc_out=. --go-grpc_opt=paths=source_relative pkg/walletpb/wallet.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pkg/walletpb/wallet.proto
+
+package walletpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WalletService_CreateAccount_FullMethodName      = "/wallet.v1.WalletService/CreateAccount"
+	WalletService_LoadAccount_FullMethodName        = "/wallet.v1.WalletService/LoadAccount"
+	WalletService_ListAccounts_FullMethodName       = "/wallet.v1.WalletService/ListAccounts"
+	WalletService_GetBalance_FullMethodName         = "/wallet.v1.WalletService/GetBalance"
+	WalletService_TransferSOL_FullMethodName        = "/wallet.v1.WalletService/TransferSOL"
+	WalletService_TransferTokens_FullMethodName     = "/wallet.v1.WalletService/TransferTokens"
+	WalletService_CreateTokenAccount_FullMethodName = "/wallet.v1.WalletService/CreateTokenAccount"
+	WalletService_CreateMint_FullMethodName         = "/wallet.v1.WalletService/CreateMint"
+	WalletService_GetQuote_FullMethodName           = "/wallet.v1.WalletService/GetQuote"
+	WalletService_Swap_FullMethodName               = "/wallet.v1.WalletService/Swap"
+	WalletService_WatchConfirmation_FullMethodName  = "/wallet.v1.WalletService/WatchConfirmation"
+	WalletService_WatchBalance_FullMethodName       = "/wallet.v1.WalletService/WatchBalance"
+)
+
+// WalletServiceClient is the client API for WalletService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WalletService 在服务端持有私钥的钱包上执行操作。
+// 除 CreateAccount / LoadAccount 外，请求中的 wallet 字段选择使用哪个已加载的钱包（公钥），
+// 为空时使用服务启动时加载的默认钱包。
+// 所有调用都需要在 metadata 中携带 authorization: Bearer <key> 或 x-api-key: <key>。
+type WalletServiceClient interface {
+	// CreateAccount 生成新账户并保存私钥文件，label 非空时同时登记到钱包登记表
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	// LoadAccount 按登记表标签加载钱包
+	LoadAccount(ctx context.Context, in *LoadAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	// ListAccounts 列出已加载的钱包
+	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	// GetBalance 查询 SOL 或代币余额
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*Balance, error)
+	// TransferSOL 转账 SOL
+	TransferSOL(ctx context.Context, in *TransferSOLRequest, opts ...grpc.CallOption) (*TransactionResult, error)
+	// TransferTokens 转账 SPL 代币
+	TransferTokens(ctx context.Context, in *TransferTokensRequest, opts ...grpc.CallOption) (*TransactionResult, error)
+	// CreateTokenAccount 为钱包创建关联代币账户
+	CreateTokenAccount(ctx context.Context, in *CreateTokenAccountRequest, opts ...grpc.CallOption) (*CreateTokenAccountResponse, error)
+	// CreateMint 创建精度为 8 的新代币，钱包为 mint authority
+	CreateMint(ctx context.Context, in *CreateMintRequest, opts ...grpc.CallOption) (*CreateMintResponse, error)
+	// GetQuote 获取 Jupiter 报价
+	GetQuote(ctx context.Context, in *QuoteRequest, opts ...grpc.CallOption) (*Quote, error)
+	// Swap 通过 Jupiter 兑换代币
+	Swap(ctx context.Context, in *SwapRequest, opts ...grpc.CallOption) (*SwapResponse, error)
+	// WatchConfirmation 推送交易确认状态的每次变化，交易 finalized 或失败后结束
+	WatchConfirmation(ctx context.Context, in *WatchConfirmationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConfirmationStatus], error)
+	// WatchBalance 先推送当前余额，之后每次余额变化时推送，直到客户端取消
+	WatchBalance(ctx context.Context, in *WatchBalanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Balance], error)
+}
+
+type walletServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWalletServiceClient(cc grpc.ClientConnInterface) WalletServiceClient {
+	return &walletServiceClient{cc}
+}
+
+func (c *walletServiceClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, WalletService_CreateAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) LoadAccount(ctx context.Context, in *LoadAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Account)
+	err := c.cc.Invoke(ctx, WalletService_LoadAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAccountsResponse)
+	err := c.cc.Invoke(ctx, WalletService_ListAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*Balance, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Balance)
+	err := c.cc.Invoke(ctx, WalletService_GetBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) TransferSOL(ctx context.Context, in *TransferSOLRequest, opts ...grpc.CallOption) (*TransactionResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResult)
+	err := c.cc.Invoke(ctx, WalletService_TransferSOL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) TransferTokens(ctx context.Context, in *TransferTokensRequest, opts ...grpc.CallOption) (*TransactionResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResult)
+	err := c.cc.Invoke(ctx, WalletService_TransferTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) CreateTokenAccount(ctx context.Context, in *CreateTokenAccountRequest, opts ...grpc.CallOption) (*CreateTokenAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTokenAccountResponse)
+	err := c.cc.Invoke(ctx, WalletService_CreateTokenAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) CreateMint(ctx context.Context, in *CreateMintRequest, opts ...grpc.CallOption) (*CreateMintResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateMintResponse)
+	err := c.cc.Invoke(ctx, WalletService_CreateMint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) GetQuote(ctx context.Context, in *QuoteRequest, opts ...grpc.CallOption) (*Quote, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Quote)
+	err := c.cc.Invoke(ctx, WalletService_GetQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) Swap(ctx context.Context, in *SwapRequest, opts ...grpc.CallOption) (*SwapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SwapResponse)
+	err := c.cc.Invoke(ctx, WalletService_Swap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) WatchConfirmation(ctx context.Context, in *WatchConfirmationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ConfirmationStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WalletService_ServiceDesc.Streams[0], WalletService_WatchConfirmation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchConfirmationRequest, ConfirmationStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WalletService_WatchConfirmationClient = grpc.ServerStreamingClient[ConfirmationStatus]
+
+func (c *walletServiceClient) WatchBalance(ctx context.Context, in *WatchBalanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Balance], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WalletService_ServiceDesc.Streams[1], WalletService_WatchBalance_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchBalanceRequest, Balance]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WalletService_WatchBalanceClient = grpc.ServerStreamingClient[Balance]
+
+// WalletServiceServer is the server API for WalletService service.
+// All implementations must embed UnimplementedWalletServiceServer
+// for forward compatibility.
+//
+// WalletService 在服务端持有私钥的钱包上执行操作。
+// 除 CreateAccount / LoadAccount 外，请求中的 wallet 字段选择使用哪个已加载的钱包（公钥），
+// 为空时使用服务启动时加载的默认钱包。
+// 所有调用都需要在 metadata 中携带 authorization: Bearer <key> 或 x-api-key: <key>。
+type WalletServiceServer interface {
+	// CreateAccount 生成新账户并保存私钥文件，label 非空时同时登记到钱包登记表
+	CreateAccount(context.Context, *CreateAccountRequest) (*Account, error)
+	// LoadAccount 按登记表标签加载钱包
+	LoadAccount(context.Context, *LoadAccountRequest) (*Account, error)
+	// ListAccounts 列出已加载的钱包
+	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	// GetBalance 查询 SOL 或代币余额
+	GetBalance(context.Context, *GetBalanceRequest) (*Balance, error)
+	// TransferSOL 转账 SOL
+	TransferSOL(context.Context, *TransferSOLRequest) (*TransactionResult, error)
+	// TransferTokens 转账 SPL 代币
+	TransferTokens(context.Context, *TransferTokensRequest) (*TransactionResult, error)
+	// CreateTokenAccount 为钱包创建关联代币账户
+	CreateTokenAccount(context.Context, *CreateTokenAccountRequest) (*CreateTokenAccountResponse, error)
+	// CreateMint 创建精度为 8 的新代币，钱包为 mint authority
+	CreateMint(context.Context, *CreateMintRequest) (*CreateMintResponse, error)
+	// GetQuote 获取 Jupiter 报价
+	GetQuote(context.Context, *QuoteRequest) (*Quote, error)
+	// Swap 通过 Jupiter 兑换代币
+	Swap(context.Context, *SwapRequest) (*SwapResponse, error)
+	// WatchConfirmation 推送交易确认状态的每次变化，交易 finalized 或失败后结束
+	WatchConfirmation(*WatchConfirmationRequest, grpc.ServerStreamingServer[ConfirmationStatus]) error
+	// WatchBalance 先推送当前余额，之后每次余额变化时推送，直到客户端取消
+	WatchBalance(*WatchBalanceRequest, grpc.ServerStreamingServer[Balance]) error
+	mustEmbedUnimplementedWalletServiceServer()
+}
+
+// UnimplementedWalletServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWalletServiceServer struct{}
+
+func (UnimplementedWalletServiceServer) CreateAccount(context.Context, *CreateAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedWalletServiceServer) LoadAccount(context.Context, *LoadAccountRequest) (*Account, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadAccount not implemented")
+}
+func (UnimplementedWalletServiceServer) ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccounts not implemented")
+}
+func (UnimplementedWalletServiceServer) GetBalance(context.Context, *GetBalanceRequest) (*Balance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedWalletServiceServer) TransferSOL(context.Context, *TransferSOLRequest) (*TransactionResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferSOL not implemented")
+}
+func (UnimplementedWalletServiceServer) TransferTokens(context.Context, *TransferTokensRequest) (*TransactionResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferTokens not implemented")
+}
+func (UnimplementedWalletServiceServer) CreateTokenAccount(context.Context, *CreateTokenAccountRequest) (*CreateTokenAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTokenAccount not implemented")
+}
+func (UnimplementedWalletServiceServer) CreateMint(context.Context, *CreateMintRequest) (*CreateMintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMint not implemented")
+}
+func (UnimplementedWalletServiceServer) GetQuote(context.Context, *QuoteRequest) (*Quote, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuote not implemented")
+}
+func (UnimplementedWalletServiceServer) Swap(context.Context, *SwapRequest) (*SwapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Swap not implemented")
+}
+func (UnimplementedWalletServiceServer) WatchConfirmation(*WatchConfirmationRequest, grpc.ServerStreamingServer[ConfirmationStatus]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchConfirmation not implemented")
+}
+func (UnimplementedWalletServiceServer) WatchBalance(*WatchBalanceRequest, grpc.ServerStreamingServer[Balance]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBalance not implemented")
+}
+func (UnimplementedWalletServiceServer) mustEmbedUnimplementedWalletServiceServer() {}
+func (UnimplementedWalletServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeWalletServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WalletServiceServer will
+// result in compilation errors.
+type UnsafeWalletServiceServer interface {
+	mustEmbedUnimplementedWalletServiceServer()
+}
+
+func RegisterWalletServiceServer(s grpc.ServiceRegistrar, srv WalletServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWalletServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WalletService_ServiceDesc, srv)
+}
+
+func _WalletService_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_LoadAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).LoadAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_LoadAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).LoadAccount(ctx, req.(*LoadAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_ListAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListAccounts(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_GetBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_TransferSOL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferSOLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).TransferSOL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_TransferSOL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).TransferSOL(ctx, req.(*TransferSOLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_TransferTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).TransferTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_TransferTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).TransferTokens(ctx, req.(*TransferTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_CreateTokenAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTokenAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).CreateTokenAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_CreateTokenAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).CreateTokenAccount(ctx, req.(*CreateTokenAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_CreateMint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).CreateMint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_CreateMint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).CreateMint(ctx, req.(*CreateMintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_GetQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_GetQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetQuote(ctx, req.(*QuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_Swap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).Swap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_Swap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).Swap(ctx, req.(*SwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_WatchConfirmation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchConfirmationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).WatchConfirmation(m, &grpc.GenericServerStream[WatchConfirmationRequest, ConfirmationStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WalletService_WatchConfirmationServer = grpc.ServerStreamingServer[ConfirmationStatus]
+
+func _WalletService_WatchBalance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBalanceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).WatchBalance(m, &grpc.GenericServerStream[WatchBalanceRequest, Balance]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WalletService_WatchBalanceServer = grpc.ServerStreamingServer[Balance]
+
+// WalletService_ServiceDesc is the grpc.ServiceDesc for WalletService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WalletService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wallet.v1.WalletService",
+	HandlerType: (*WalletServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAccount",
+			Handler:    _WalletService_CreateAccount_Handler,
+		},
+		{
+			MethodName: "LoadAccount",
+			Handler:    _WalletService_LoadAccount_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _WalletService_ListAccounts_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _WalletService_GetBalance_Handler,
+		},
+		{
+			MethodName: "TransferSOL",
+			Handler:    _WalletService_TransferSOL_Handler,
+		},
+		{
+			MethodName: "TransferTokens",
+			Handler:    _WalletService_TransferTokens_Handler,
+		},
+		{
+			MethodName: "CreateTokenAccount",
+			Handler:    _WalletService_CreateTokenAccount_Handler,
+		},
+		{
+			MethodName: "CreateMint",
+			Handler:    _WalletService_CreateMint_Handler,
+		},
+		{
+			MethodName: "GetQuote",
+			Handler:    _WalletService_GetQuote_Handler,
+		},
+		{
+			MethodName: "Swap",
+			Handler:    _WalletService_Swap_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchConfirmation",
+			Handler:       _WalletService_WatchConfirmation_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchBalance",
+			Handler:       _WalletService_WatchBalance_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/walletpb/wallet.proto",
+}